@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
@@ -15,16 +16,33 @@ import (
 	"github.com/casbin/casbin/v2"
 	gormadapter "github.com/casbin/gorm-adapter/v3"
 	"github.com/mrhumster/web-server-gin/config"
+	authpb "github.com/mrhumster/web-server-gin/gen/go/auth"
 	permissionpb "github.com/mrhumster/web-server-gin/gen/go/permission"
+	userpb "github.com/mrhumster/web-server-gin/gen/go/user"
 	"github.com/mrhumster/web-server-gin/internal/database"
 	"github.com/mrhumster/web-server-gin/internal/delivery/http/routes"
+	"github.com/mrhumster/web-server-gin/internal/lifecycle"
 	"github.com/mrhumster/web-server-gin/internal/permission"
+	"github.com/mrhumster/web-server-gin/internal/repository"
 	"github.com/mrhumster/web-server-gin/internal/service"
 	"github.com/mrhumster/web-server-gin/pkg/auth"
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"gorm.io/gorm"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "policy" {
+		if err := runPolicyCommand(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	opts := &slog.HandlerOptions{
 		Level:     slog.LevelDebug,
 		AddSource: true,
@@ -41,28 +59,77 @@ func main() {
 	}
 	db := database.SetupDatabase(cfg)
 
-	permGRPCClient, err := auth.NewPermissionClient(cfg.Server.AuthServiceAddr)
+	tokenService, err := service.NewTokenService(&cfg.JWT)
 	if err != nil {
-		panic(fmt.Sprintf("❌ Permission gRPC client: %s", err.Error()))
+		panic(fmt.Sprintf("❌ Token service: %s", err.Error()))
 	}
 
-	r := routes.SetupRoutes(db, "release", permGRPCClient)
-
-	defer func() {
-		log.Println("🟡 Closing database pool...")
+	manager := lifecycle.NewManager(cfg.Server.ShutdownTimeout)
+	manager.OnShutdown("database pool", func(context.Context) error {
 		sqlDB, err := db.DB()
 		if err != nil {
-			log.Printf("failed to get sql.DB: %s", err.Error())
+			return err
 		}
-		if err := sqlDB.Close(); err != nil {
-			log.Println("🟢 Database pool closed")
+		return sqlDB.Close()
+	})
+
+	// The gRPC user service checks with the permission service itself, so
+	// it needs no client. Policy operations of users created over gRPC are
+	// applied by the outbox started below.
+	userService := service.NewUserService(repository.NewGormUserRepository(db), nil)
+	if cfg.Server.ProvisioningFile != "" {
+		templates, err := auth.ReadProvisioningTemplates(cfg.Server.ProvisioningFile)
+		if err != nil {
+			panic(fmt.Sprintf("❌ Provisioning templates: %s", err.Error()))
 		}
-		log.Printf("🟡 Closing gRPC client...")
-		permGRPCClient.Close()
-	}()
+		userService.SetProvisioningTemplates(templates)
+	}
 
-	httpErr := make(chan error, 1)
-	grpcErr := make(chan error, 1)
+	healthMonitor, err := startPermissionServer(manager, cfg, db, tokenService, userService)
+	if err != nil {
+		slog.Error("Error start permission server", "error", err)
+		os.Exit(1)
+	}
+
+	// Local clients load their policies from the server on creation, so it
+	// must be listening first.
+	mode := auth.Mode(cfg.GRPC.ClientMode)
+	permClient, err := auth.NewPermissionClientWithMode(mode, cfg.Server.AuthServiceAddr,
+		localOptions(manager, cfg), clientOptions(cfg, tokenService)...)
+	if err != nil {
+		panic(fmt.Sprintf("❌ Permission client: %s", err.Error()))
+	}
+	slog.Info("Permission client", "mode", mode)
+
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	if cfg.Cache.Size > 0 && (mode == auth.ModeRemote || mode == "") {
+		cached := auth.NewCachingPermissionClient(permClient, auth.CacheOptions{
+			Size: cfg.Cache.Size,
+			TTL:  cfg.Cache.TTL,
+		})
+		if watcher, ok := permClient.(auth.PolicyWatcher); ok {
+			go cached.WatchPolicies(watchCtx, watcher)
+		}
+		permClient = cached
+	}
+	manager.OnShutdown("permission client", func(context.Context) error {
+		stopWatch()
+		return permClient.Close()
+	})
+
+	gatewayConn, err := auth.Dial(cfg.Server.AuthServiceAddr, clientOptions(cfg, tokenService)...)
+	if err != nil {
+		panic(fmt.Sprintf("❌ Gateway connection: %s", err.Error()))
+	}
+	manager.OnShutdown("gateway connection", func(context.Context) error {
+		return gatewayConn.Close()
+	})
+
+	policyOutbox := service.NewPolicyOutbox(repository.NewGormPolicyOutboxRepository(db), permClient)
+	startPolicyOutbox(manager, policyOutbox)
+
+	r := routes.SetupRoutes(db, "release", permClient,
+		routes.WithGateway(gatewayConn), routes.WithPolicyOutbox(policyOutbox))
 
 	srv := &http.Server{
 		Addr:         cfg.Server.ServerAddr,
@@ -71,68 +138,187 @@ func main() {
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	log.Printf("ENV DOMAIN: %s", cfg.Server.Domain)
+	manager.Start(lifecycle.Component{
+		Name: "HTTP server " + cfg.Server.ServerAddr,
+		Serve: func() error {
+			if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+		Shutdown: srv.Shutdown,
+	})
+	// Probes see the gRPC server go away before anything stops.
+	manager.OnShutdown("gRPC health", func(context.Context) error {
+		healthMonitor.Shutdown()
+		return nil
+	})
 
-	go func() {
-		log.Printf("🚀 Server starting on %s\n", cfg.Server.ServerAddr)
-		log.Printf("ENV DOMAIN: %s", cfg.Server.Domain)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatal("🔴 Server error: ", err)
-			httpErr <- err
-		}
-	}()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if err := manager.Wait(ctx); err != nil {
+		slog.Error("🔴 Server stopped with errors", "error", err)
+		os.Exit(1)
+	}
+	log.Println("🟢 Server stoped")
+}
 
-	go func() {
-		lis, err := net.Listen("tcp", ":50051")
-		if err != nil {
-			log.Fatalf("🔴 Failed to listen: %v", err)
-		}
+// startPermissionServer sets up the permission service on the policies in db
+// and serves it over gRPC next to users and tokens, with health checking and,
+// when enabled, reflection. The returned monitor reports the server's health.
+func startPermissionServer(manager *lifecycle.Manager, cfg *config.Config, db *gorm.DB, tokenService *service.TokenService, users *service.UserService) (*permission.HealthMonitor, error) {
+	lis, err := net.Listen("tcp", cfg.GRPC.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	authorizer := permission.NewAuthorizer(tokenService, cfg.GRPC.PolicyAdmins, cfg.GRPC.PolicyReaders)
+	serverOpts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(authorizer.UnaryInterceptor()),
+		grpc.StreamInterceptor(authorizer.StreamInterceptor()),
+	}
+	serverOpts = append(serverOpts, permission.KeepaliveOptions(cfg.GRPC)...)
+	creds, err := permission.ServerCredentials(cfg.GRPC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load gRPC TLS credentials: %w", err)
+	}
+	if creds != nil {
+		serverOpts = append(serverOpts, grpc.Creds(creds))
+	} else {
+		slog.Warn("gRPC server is running without TLS")
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
 
-		grpcServer := grpc.NewServer()
+	adapter, err := gormadapter.NewAdapterByDB(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize casbin adapter: %w", err)
+	}
 
-		adapter, err := gormadapter.NewAdapterByDB(db)
-		if err != nil {
-			panic(fmt.Sprintf("failed to initialize casbin adapter: %v", err))
-		}
+	if err := database.MigrateLegacyPolicies(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate casbin policies: %w", err)
+	}
+	if err := database.MigrateDomainPolicies(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate casbin policies to domains: %w", err)
+	}
+	if err := database.MigrateOwnerPolicies(db); err != nil {
+		return nil, fmt.Errorf("failed to collapse per-user casbin policies: %w", err)
+	}
 
-		enforcer, err := casbin.NewEnforcer(cfg.Server.CasbinModel, adapter)
-		if err != nil {
-			log.Printf("⚠️ Casbin Load Error, %s", err.Error())
-			panic("⚠️ Error loading roles config")
-		}
+	enforcer, err := casbin.NewEnforcer(cfg.Server.CasbinModel, adapter)
+	if err != nil {
+		return nil, fmt.Errorf("error loading roles config: %w", err)
+	}
 
-		permissionService, err := service.NewPermissionService(enforcer, cfg.Redis)
-		if err != nil {
-			slog.Error("Error init permission service", "error", err)
-			panic("⚠️ Error init permission service")
-		}
+	watcher, err := service.NewPolicyWatcher(cfg, db)
+	if err != nil {
+		return nil, fmt.Errorf("error init policy watcher %q: %w", cfg.PolicyWatcher, err)
+	}
+	slog.Info("Policy watcher", "watcher", cfg.PolicyWatcher)
 
-		defer func() {
-			log.Println("🟡 Closing Permission Service (Watcher)...")
-			permissionService.Close()
-		}()
+	permissionService, err := service.NewPermissionService(enforcer, watcher)
+	if err != nil {
+		return nil, fmt.Errorf("error init permission service: %w", err)
+	}
+	permissionService.SetHistory(repository.NewGormPolicyChangeRepository(db))
+	manager.OnShutdown("permission service (watcher)", func(context.Context) error {
+		return permissionService.Close()
+	})
 
-		permissionServer := permission.NewPermissionGRPCServer(permissionService)
-		permissionpb.RegisterPermissionServiceServer(grpcServer, permissionServer)
+	permissionServer := permission.NewPermissionGRPCServer(permissionService)
+	permissionpb.RegisterPermissionServiceServer(grpcServer, permissionServer)
+	userpb.RegisterUserServiceServer(grpcServer, permission.NewUserGRPCServer(users, permissionService))
+	authpb.RegisterAuthServiceServer(grpcServer, permission.NewAuthGRPCServer(tokenService, users, authorizer))
 
-		log.Printf("🛰️ gRPC server listened at %v", lis.Addr())
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	monitor := permission.NewHealthMonitor(healthServer,
+		permission.HealthCheck{Name: "database", Check: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		}},
+		permission.HealthCheck{Name: "enforcer", Check: permissionService.CheckEnforcer},
+		permission.HealthCheck{Name: "watcher", Check: permissionService.CheckWatcher},
+	)
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	go monitor.Run(healthCtx)
 
-		if err := grpcServer.Serve(lis); err != nil {
-			log.Fatalf("🔴 Failed to serve: %v", err)
-			grpcErr <- err
-		}
-	}()
+	if cfg.GRPC.Reflection {
+		reflection.Register(grpcServer)
+	}
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Println("🟡 Shutting down server...")
+	manager.Start(lifecycle.Component{
+		Name: "gRPC server " + lis.Addr().String(),
+		Serve: func() error {
+			return grpcServer.Serve(lis)
+		},
+		Shutdown: func(ctx context.Context) error {
+			stopHealth()
+			permissionServer.EndStreams()
+			return lifecycle.GracefulStop(ctx, grpcServer)
+		},
+	})
+	return monitor, nil
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+// startPolicyOutbox applies stored policy operations until shutdown, which
+// waits for the batch in progress.
+func startPolicyOutbox(manager *lifecycle.Manager, outbox *service.PolicyOutbox) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	manager.Start(lifecycle.Component{
+		Name: "policy outbox",
+		Serve: func() error {
+			defer close(done)
+			outbox.Run(ctx)
+			return nil
+		},
+		Shutdown: func(shutdownCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-shutdownCtx.Done():
+				return shutdownCtx.Err()
+			}
+		},
+	})
+}
 
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("🔴 Server shutdown error: ", err)
+// localOptions make local permission clients follow the Redis channel the
+// permission server publishes changes on, when it uses the Redis watcher.
+// Otherwise they follow the WatchPolicies stream of the server.
+func localOptions(manager *lifecycle.Manager, cfg *config.Config) auth.LocalOptions {
+	if cfg.PolicyWatcher != service.WatcherRedis {
+		return auth.LocalOptions{}
 	}
+	rdb := redis.NewClient(&redis.Options{Addr: cfg.Redis.Addr, Password: cfg.Redis.Password})
+	manager.OnShutdown("permission client redis", func(context.Context) error {
+		return rdb.Close()
+	})
+	return auth.LocalOptions{Redis: rdb}
+}
 
-	log.Println("🟢 Server stoped")
+// clientOptions authenticate the permission client as this service.
+func clientOptions(cfg *config.Config, tokenService *service.TokenService) []auth.ClientOption {
+	opts := []auth.ClientOption{
+		auth.WithServiceToken(func() (string, time.Time, error) {
+			return tokenService.GenerateServiceToken(cfg.GRPC.ServiceName)
+		}),
+		auth.WithCallTimeout(cfg.Client.CallTimeout),
+		auth.WithRetries(cfg.Client.RetryAttempts),
+		auth.WithCircuitBreaker(cfg.Client.BreakerFailures, cfg.Client.BreakerTimeout),
+	}
+	if cfg.GRPC.CAFile != "" || cfg.GRPC.ClientCertFile != "" {
+		opts = append(opts, auth.WithTLS(auth.TLSConfig{
+			CAFile:     cfg.GRPC.CAFile,
+			CertFile:   cfg.GRPC.ClientCertFile,
+			KeyFile:    cfg.GRPC.ClientKeyFile,
+			ServerName: cfg.GRPC.ServerName,
+		}))
+	}
+	return opts
 }
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mrhumster/web-server-gin/config"
+	"github.com/mrhumster/web-server-gin/internal/service"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+)
+
+const policyUsage = `usage:
+  policy export [-format csv|yaml] [-o file]
+  policy import -f file
+  policy sync -f file [-dry-run]
+
+export writes every policy and role link the permission server holds.
+import adds the rules of a file that are missing.
+sync also removes the stored rules the file doesn't list, within the domains
+of its policies and for the subjects of its role links.`
+
+// runPolicyCommand manages the policies of the permission server at
+// AUTH_SERVICE_ADDRESS, authenticating as GRPC_SERVICE_NAME.
+func runPolicyCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New(policyUsage)
+	}
+	cmd, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("policy "+cmd, flag.ContinueOnError)
+	format := fs.String("format", auth.PolicyFormatYAML, "export format: csv or yaml")
+	out := fs.String("o", "", "write the export to this file instead of stdout")
+	file := fs.String("f", "", "policy file, .csv or .yaml")
+	dryRun := fs.Bool("dry-run", false, "print the plan without applying it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	tokenService, err := service.NewTokenService(&cfg.JWT)
+	if err != nil {
+		return fmt.Errorf("token service: %w", err)
+	}
+	client, err := auth.NewPermissionGRPCClient(cfg.Server.AuthServiceAddr, clientOptions(cfg, tokenService)...)
+	if err != nil {
+		return fmt.Errorf("permission client: %w", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	switch cmd {
+	case "export":
+		return exportPolicies(ctx, client, *format, *out)
+	case "import", "sync":
+		if *file == "" {
+			return errors.New("policy " + cmd + ": -f is required")
+		}
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		rules, err := auth.ParsePolicies(data, auth.PolicyFormat(*file))
+		if err != nil {
+			return fmt.Errorf("%s: %w", *file, err)
+		}
+		plan, err := client.SyncPolicies(ctx, rules, auth.SyncOptions{Prune: cmd == "sync", DryRun: *dryRun})
+		if err != nil {
+			return err
+		}
+		printPlan(os.Stdout, plan, *dryRun)
+		return nil
+	default:
+		return errors.New(policyUsage)
+	}
+}
+
+func exportPolicies(ctx context.Context, client *auth.PermissionGRPCClient, format, out string) error {
+	rules, err := client.PolicySnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := auth.EncodePolicies(rules, format)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(out, data, 0o644)
+}
+
+func printPlan(w io.Writer, plan *auth.PolicyPlan, dryRun bool) {
+	for _, rule := range plan.Removed {
+		fmt.Fprintf(w, "- %s\n", strings.Join(rule, ", "))
+	}
+	for _, rule := range plan.Added {
+		fmt.Fprintf(w, "+ %s\n", strings.Join(rule, ", "))
+	}
+	verb := "applied"
+	if dryRun {
+		verb = "planned, nothing applied"
+	}
+	fmt.Fprintf(w, "%d to add, %d to remove: %s\n", len(plan.Added), len(plan.Removed), verb)
+}
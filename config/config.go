@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -29,6 +31,67 @@ type Server struct {
 	CasbinModel     string
 	Domain          string
 	AuthServiceAddr string
+	// ShutdownTimeout bounds stopping the servers and releasing what they
+	// use once the process is asked to stop.
+	ShutdownTimeout time.Duration
+	// ProvisioningFile replaces the embedded provisioning.yaml when set.
+	ProvisioningFile string
+}
+
+// GRPC configures the permission gRPC server and the client the HTTP layer
+// uses to reach it. TLS is enabled when a certificate is set; a client CA on
+// the server side turns on mutual TLS.
+type GRPC struct {
+	Addr string
+
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+
+	CAFile         string
+	ClientCertFile string
+	ClientKeyFile  string
+	ServerName     string
+
+	// ServiceName is the identity this process presents with its service JWT.
+	ServiceName string
+	// PolicyAdmins may change policies and roles. PolicyReaders may only run
+	// checks and introspection; when empty any authenticated caller may.
+	PolicyAdmins  []string
+	PolicyReaders []string
+
+	// ClientMode is remote, local or local-fallback, see auth.Mode.
+	ClientMode string
+
+	// Reflection registers the server reflection service for grpcurl and
+	// similar tools.
+	Reflection bool
+	// KeepaliveTime is how long a connection may be idle before the server
+	// pings the client; a client not answering within KeepaliveTimeout is
+	// disconnected.
+	KeepaliveTime    time.Duration
+	KeepaliveTimeout time.Duration
+}
+
+// PermissionCache configures the HTTP layer's decision cache. A zero Size
+// disables it.
+type PermissionCache struct {
+	Size int
+	TTL  time.Duration
+}
+
+// PermissionClient configures how the HTTP layer calls the permission
+// service. FailMode, closed or open, decides checks the service can't answer;
+// FailOpen and FailClosed override it for the listed actions.
+type PermissionClient struct {
+	CallTimeout     time.Duration
+	RetryAttempts   int
+	BreakerFailures uint32
+	BreakerTimeout  time.Duration
+
+	FailMode   string
+	FailOpen   []string
+	FailClosed []string
 }
 
 type JWT struct {
@@ -42,9 +105,15 @@ type JWT struct {
 }
 type Config struct {
 	Database `mapstructure:",squash"`
-	Server   Server `mapstructure:"server"`
-	JWT      JWT    `mapstructure:"jwt"`
-	Redis    Redis  `mapstructure:"redis"`
+	Server   Server           `mapstructure:"server"`
+	JWT      JWT              `mapstructure:"jwt"`
+	Redis    Redis            `mapstructure:"redis"`
+	GRPC     GRPC             `mapstructure:"grpc"`
+	Cache    PermissionCache  `mapstructure:"permission_cache"`
+	Client   PermissionClient `mapstructure:"permission_client"`
+	// PolicyWatcher tells the permission server replicas about policy changes:
+	// postgres, redis or memory.
+	PolicyWatcher string `mapstructure:"policy_watcher"`
 }
 
 func GetRootDir() string {
@@ -74,11 +143,12 @@ func LoadConfig() (*Config, error) {
 			TimeZone: "UTC",
 		},
 		Server: Server{
-			ServerAddr:      os.Getenv("SERVER_ADDR"),
-			JwtSecret:       os.Getenv("JWT_SECRET"),
-			CasbinModel:     os.Getenv("CASBIN_MODEL"),
-			Domain:          os.Getenv("DOMAIN"),
-			AuthServiceAddr: os.Getenv("AUTH_SERVICE_ADDRESS"),
+			ServerAddr:       os.Getenv("SERVER_ADDR"),
+			JwtSecret:        os.Getenv("JWT_SECRET"),
+			CasbinModel:      os.Getenv("CASBIN_MODEL"),
+			Domain:           os.Getenv("DOMAIN"),
+			AuthServiceAddr:  os.Getenv("AUTH_SERVICE_ADDRESS"),
+			ProvisioningFile: os.Getenv("PROVISIONING_TEMPLATES"),
 		},
 		JWT: JWT{
 			AccessPrivateKey:   getEnv("JWT_ACCESS_PRIVATE_KEY", ""),
@@ -93,6 +163,23 @@ func LoadConfig() (*Config, error) {
 			Addr:     getEnv("REDIS_ADDR", "localhost"),
 			Password: getEnv("REDIS_PASS", ""),
 		},
+		PolicyWatcher: getEnv("POLICY_WATCHER", defaultPolicyWatcher()),
+	}
+	cfg.Server.ShutdownTimeout, err = time.ParseDuration(getEnv("SHUTDOWN_TIMEOUT", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("Config error. Plase set ENV SHUTDOWN_TIMEOUT. %v", err)
+	}
+	cfg.GRPC, err = loadGRPC()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Cache, err = loadPermissionCache()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Client, err = loadPermissionClient()
+	if err != nil {
+		return nil, err
 	}
 	return cfg, nil
 }
@@ -111,6 +198,103 @@ func (config *Config) GetDsn() string {
 		config.TimeZone)
 }
 
+func loadGRPC() (GRPC, error) {
+	keepaliveTime, err := time.ParseDuration(getEnv("GRPC_KEEPALIVE_TIME", "1m"))
+	if err != nil {
+		return GRPC{}, fmt.Errorf("Config error. Plase set ENV GRPC_KEEPALIVE_TIME. %v", err)
+	}
+	keepaliveTimeout, err := time.ParseDuration(getEnv("GRPC_KEEPALIVE_TIMEOUT", "20s"))
+	if err != nil {
+		return GRPC{}, fmt.Errorf("Config error. Plase set ENV GRPC_KEEPALIVE_TIMEOUT. %v", err)
+	}
+	serviceName := getEnv("GRPC_SERVICE_NAME", "web-server-gin")
+	return GRPC{
+		Addr:             getEnv("GRPC_ADDR", ":50051"),
+		CertFile:         os.Getenv("GRPC_TLS_CERT"),
+		KeyFile:          os.Getenv("GRPC_TLS_KEY"),
+		ClientCAFile:     os.Getenv("GRPC_TLS_CLIENT_CA"),
+		CAFile:           os.Getenv("GRPC_TLS_CA"),
+		ClientCertFile:   os.Getenv("GRPC_TLS_CLIENT_CERT"),
+		ClientKeyFile:    os.Getenv("GRPC_TLS_CLIENT_KEY"),
+		ServerName:       os.Getenv("GRPC_TLS_SERVER_NAME"),
+		ServiceName:      serviceName,
+		PolicyAdmins:     getEnvList("GRPC_POLICY_ADMINS", []string{serviceName}),
+		PolicyReaders:    getEnvList("GRPC_POLICY_READERS", nil),
+		ClientMode:       getEnv("PERMISSION_CLIENT_MODE", "remote"),
+		Reflection:       getEnv("GRPC_REFLECTION", "false") == "true",
+		KeepaliveTime:    keepaliveTime,
+		KeepaliveTimeout: keepaliveTimeout,
+	}, nil
+}
+
+// defaultPolicyWatcher keeps deployments that configure Redis on it and
+// needs nothing but the database otherwise.
+func defaultPolicyWatcher() string {
+	if os.Getenv("REDIS_ADDR") != "" {
+		return "redis"
+	}
+	return "postgres"
+}
+
+func loadPermissionCache() (PermissionCache, error) {
+	size, err := strconv.Atoi(getEnv("PERMISSION_CACHE_SIZE", "10000"))
+	if err != nil {
+		return PermissionCache{}, fmt.Errorf("Config error. Plase set ENV PERMISSION_CACHE_SIZE. %v", err)
+	}
+	ttl, err := time.ParseDuration(getEnv("PERMISSION_CACHE_TTL", "10s"))
+	if err != nil {
+		return PermissionCache{}, fmt.Errorf("Config error. Plase set ENV PERMISSION_CACHE_TTL. %v", err)
+	}
+	return PermissionCache{Size: size, TTL: ttl}, nil
+}
+
+func loadPermissionClient() (PermissionClient, error) {
+	callTimeout, err := time.ParseDuration(getEnv("PERMISSION_CALL_TIMEOUT", "3s"))
+	if err != nil {
+		return PermissionClient{}, fmt.Errorf("Config error. Plase set ENV PERMISSION_CALL_TIMEOUT. %v", err)
+	}
+	retryAttempts, err := strconv.Atoi(getEnv("PERMISSION_RETRY_ATTEMPTS", "3"))
+	if err != nil {
+		return PermissionClient{}, fmt.Errorf("Config error. Plase set ENV PERMISSION_RETRY_ATTEMPTS. %v", err)
+	}
+	breakerFailures, err := strconv.ParseUint(getEnv("PERMISSION_BREAKER_FAILURES", "5"), 10, 32)
+	if err != nil {
+		return PermissionClient{}, fmt.Errorf("Config error. Plase set ENV PERMISSION_BREAKER_FAILURES. %v", err)
+	}
+	breakerTimeout, err := time.ParseDuration(getEnv("PERMISSION_BREAKER_TIMEOUT", "30s"))
+	if err != nil {
+		return PermissionClient{}, fmt.Errorf("Config error. Plase set ENV PERMISSION_BREAKER_TIMEOUT. %v", err)
+	}
+	failMode := getEnv("PERMISSION_FAIL_MODE", "closed")
+	if failMode != "closed" && failMode != "open" {
+		return PermissionClient{}, fmt.Errorf("Config error. PERMISSION_FAIL_MODE must be closed or open, got %q", failMode)
+	}
+	return PermissionClient{
+		CallTimeout:     callTimeout,
+		RetryAttempts:   retryAttempts,
+		BreakerFailures: uint32(breakerFailures),
+		BreakerTimeout:  breakerTimeout,
+		FailMode:        failMode,
+		FailOpen:        getEnvList("PERMISSION_FAIL_OPEN", nil),
+		FailClosed:      getEnvList("PERMISSION_FAIL_CLOSED", nil),
+	}, nil
+}
+
+// getEnvList reads a comma separated list.
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -157,6 +341,11 @@ func TestConfig() (*Config, error) {
 		return nil, fmt.Errorf("Config error. RefreshPublicKey not read: %w", err)
 	}
 
+	grpcConfig, err := loadGRPC()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		Database: Database{
 			Host:     getEnv("TEST_DB_HOST", "localhost"),
@@ -183,5 +372,6 @@ func TestConfig() (*Config, error) {
 			RefreshTokenExpiry: refreshTokenExpiry,
 			Issuer:             getEnv("JWT_ISSUER", "auth-service"),
 		},
+		GRPC: grpcConfig,
 	}, nil
 }
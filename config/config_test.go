@@ -25,3 +25,34 @@ func TestTestConfig(t *testing.T) {
 	dsn := cfg.GetDsn()
 	assert.NotEmpty(t, dsn)
 }
+
+func TestLoadConfig_PolicyWatcher(t *testing.T) {
+	t.Setenv("POLICY_WATCHER", "")
+	t.Setenv("REDIS_ADDR", "")
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "postgres", cfg.PolicyWatcher, "no Redis needed by default")
+
+	t.Setenv("REDIS_ADDR", "redis:6379")
+	cfg, err = LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "redis", cfg.PolicyWatcher, "deployments with Redis keep using it")
+
+	t.Setenv("POLICY_WATCHER", "memory")
+	cfg, err = LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "memory", cfg.PolicyWatcher)
+}
+
+func TestLoadConfig_PermissionClient(t *testing.T) {
+	t.Setenv("PERMISSION_FAIL_OPEN", "read, list")
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "closed", cfg.Client.FailMode)
+	assert.Equal(t, []string{"read", "list"}, cfg.Client.FailOpen)
+	assert.Equal(t, 3, cfg.Client.RetryAttempts)
+
+	t.Setenv("PERMISSION_FAIL_MODE", "maybe")
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, "PERMISSION_FAIL_MODE")
+}
@@ -0,0 +1,27 @@
+package config
+
+import _ "embed"
+
+// ModelConf is model.conf compiled into the binary, so enforcers embedded in
+// other services run exactly the model the permission server runs.
+//
+//go:embed model.conf
+var ModelConf string
+
+// BaselinePolicies is policies.yaml, the policies every deployment starts
+// with, in the format auth.ParsePolicies reads.
+//
+//go:embed policies.yaml
+var BaselinePolicies []byte
+
+// ProvisioningTemplates is provisioning.yaml, the rules granted per resource
+// type on creation, in the format auth.ParseProvisioningTemplates reads.
+//
+//go:embed provisioning.yaml
+var ProvisioningTemplates []byte
+
+// GatewayRules is gateway.yaml, the HTTP bindings of the gRPC methods, in the
+// format gateway.ParseRules reads.
+//
+//go:embed gateway.yaml
+var GatewayRules []byte
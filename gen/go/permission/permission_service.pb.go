@@ -23,12 +23,18 @@ const (
 )
 
 type CheckPermissionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Resource      string                 `protobuf:"bytes,2,opt,name=resource,proto3" json:"resource,omitempty"`
-	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	UserId   string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Resource string                 `protobuf:"bytes,2,opt,name=resource,proto3" json:"resource,omitempty"`
+	Action   string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Domain   string                 `protobuf:"bytes,4,opt,name=domain,proto3" json:"domain,omitempty"`
+	// ABAC inputs, available to conditions as r2.attrs.subject.*,
+	// r2.attrs.resource.* and r2.attrs.env.*.
+	SubjectAttributes  map[string]string `protobuf:"bytes,5,rep,name=subject_attributes,json=subjectAttributes,proto3" json:"subject_attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ResourceAttributes map[string]string `protobuf:"bytes,6,rep,name=resource_attributes,json=resourceAttributes,proto3" json:"resource_attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Environment        map[string]string `protobuf:"bytes,7,rep,name=environment,proto3" json:"environment,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *CheckPermissionRequest) Reset() {
@@ -82,10 +88,39 @@ func (x *CheckPermissionRequest) GetAction() string {
 	return ""
 }
 
+func (x *CheckPermissionRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *CheckPermissionRequest) GetSubjectAttributes() map[string]string {
+	if x != nil {
+		return x.SubjectAttributes
+	}
+	return nil
+}
+
+func (x *CheckPermissionRequest) GetResourceAttributes() map[string]string {
+	if x != nil {
+		return x.ResourceAttributes
+	}
+	return nil
+}
+
+func (x *CheckPermissionRequest) GetEnvironment() map[string]string {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
 type CheckPermissionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Allowed       bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Allowed bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	// Deprecated: failures are returned as gRPC status errors.
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -135,10 +170,14 @@ func (x *CheckPermissionResponse) GetError() string {
 }
 
 type AddPolicyRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Policy        string                 `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
-	Resorce       string                 `protobuf:"bytes,2,opt,name=resorce,proto3" json:"resorce,omitempty"`
-	Permission    string                 `protobuf:"bytes,3,opt,name=permission,proto3" json:"permission,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Policy     string                 `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	Resorce    string                 `protobuf:"bytes,2,opt,name=resorce,proto3" json:"resorce,omitempty"`
+	Permission string                 `protobuf:"bytes,3,opt,name=permission,proto3" json:"permission,omitempty"`
+	Domain     string                 `protobuf:"bytes,4,opt,name=domain,proto3" json:"domain,omitempty"`
+	// A non-empty condition stores an ABAC rule evaluated against the
+	// request attributes instead of a plain RBAC policy.
+	Condition     string `protobuf:"bytes,5,opt,name=condition,proto3" json:"condition,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -194,10 +233,25 @@ func (x *AddPolicyRequest) GetPermission() string {
 	return ""
 }
 
+func (x *AddPolicyRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *AddPolicyRequest) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
 type AddPolicyResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Added         bool                   `protobuf:"varint,1,opt,name=added,proto3" json:"added,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Added bool                   `protobuf:"varint,1,opt,name=added,proto3" json:"added,omitempty"`
+	// Deprecated: failures are returned as gRPC status errors.
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -251,6 +305,8 @@ type RemovePolicyRequest struct {
 	Policy        string                 `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
 	Resource      string                 `protobuf:"bytes,2,opt,name=resource,proto3" json:"resource,omitempty"`
 	Permission    string                 `protobuf:"bytes,3,opt,name=permission,proto3" json:"permission,omitempty"`
+	Domain        string                 `protobuf:"bytes,4,opt,name=domain,proto3" json:"domain,omitempty"`
+	Condition     string                 `protobuf:"bytes,5,opt,name=condition,proto3" json:"condition,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -306,10 +362,25 @@ func (x *RemovePolicyRequest) GetPermission() string {
 	return ""
 }
 
+func (x *RemovePolicyRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *RemovePolicyRequest) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
 type RemovePolicyResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Removed       bool                   `protobuf:"varint,1,opt,name=removed,proto3" json:"removed,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Removed bool                   `protobuf:"varint,1,opt,name=removed,proto3" json:"removed,omitempty"`
+	// Deprecated: failures are returned as gRPC status errors.
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -363,6 +434,7 @@ type AddPolicyIfNotExistsRequest struct {
 	Policy        string                 `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
 	Resource      string                 `protobuf:"bytes,2,opt,name=resource,proto3" json:"resource,omitempty"`
 	Permission    string                 `protobuf:"bytes,3,opt,name=permission,proto3" json:"permission,omitempty"`
+	Domain        string                 `protobuf:"bytes,4,opt,name=domain,proto3" json:"domain,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -418,10 +490,18 @@ func (x *AddPolicyIfNotExistsRequest) GetPermission() string {
 	return ""
 }
 
+func (x *AddPolicyIfNotExistsRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
 type AddPolicyIfNotExistsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Exists        bool                   `protobuf:"varint,1,opt,name=exists,proto3" json:"exists,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Exists bool                   `protobuf:"varint,1,opt,name=exists,proto3" json:"exists,omitempty"`
+	// Deprecated: failures are returned as gRPC status errors.
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -470,51 +550,2278 @@ func (x *AddPolicyIfNotExistsResponse) GetError() string {
 	return ""
 }
 
-var File_proto_permission_permission_service_proto protoreflect.FileDescriptor
+type AssignRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Domain        string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_permission_permission_service_proto_rawDesc = "" +
-	"\n" +
-	")proto/permission/permission_service.proto\x12\n" +
-	"permission\"e\n" +
-	"\x16CheckPermissionRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
-	"\bresource\x18\x02 \x01(\tR\bresource\x12\x16\n" +
-	"\x06action\x18\x03 \x01(\tR\x06action\"I\n" +
-	"\x17CheckPermissionResponse\x12\x18\n" +
-	"\aallowed\x18\x01 \x01(\bR\aallowed\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"d\n" +
-	"\x10AddPolicyRequest\x12\x16\n" +
-	"\x06policy\x18\x01 \x01(\tR\x06policy\x12\x18\n" +
-	"\aresorce\x18\x02 \x01(\tR\aresorce\x12\x1e\n" +
-	"\n" +
-	"permission\x18\x03 \x01(\tR\n" +
-	"permission\"?\n" +
-	"\x11AddPolicyResponse\x12\x14\n" +
-	"\x05added\x18\x01 \x01(\bR\x05added\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"i\n" +
-	"\x13RemovePolicyRequest\x12\x16\n" +
-	"\x06policy\x18\x01 \x01(\tR\x06policy\x12\x1a\n" +
-	"\bresource\x18\x02 \x01(\tR\bresource\x12\x1e\n" +
-	"\n" +
-	"permission\x18\x03 \x01(\tR\n" +
-	"permission\"F\n" +
-	"\x14RemovePolicyResponse\x12\x18\n" +
-	"\aremoved\x18\x01 \x01(\bR\aremoved\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"q\n" +
-	"\x1bAddPolicyIfNotExistsRequest\x12\x16\n" +
-	"\x06policy\x18\x01 \x01(\tR\x06policy\x12\x1a\n" +
-	"\bresource\x18\x02 \x01(\tR\bresource\x12\x1e\n" +
-	"\n" +
-	"permission\x18\x03 \x01(\tR\n" +
-	"permission\"L\n" +
-	"\x1cAddPolicyIfNotExistsResponse\x12\x16\n" +
-	"\x06exists\x18\x01 \x01(\bR\x06exists\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error2\xf7\x02\n" +
-	"\x11PermissionService\x12Z\n" +
-	"\x0fCheckPermission\x12\".permission.CheckPermissionRequest\x1a#.permission.CheckPermissionResponse\x12H\n" +
-	"\tAddPolicy\x12\x1c.permission.AddPolicyRequest\x1a\x1d.permission.AddPolicyResponse\x12Q\n" +
-	"\fRemovePolicy\x12\x1f.permission.RemovePolicyRequest\x1a .permission.RemovePolicyResponse\x12i\n" +
-	"\x14AddPolicyIfNotExists\x12'.permission.AddPolicyIfNotExistsRequest\x1a(.permission.AddPolicyIfNotExistsResponseB=Z;github.com/mrhumster/web-server-gin/proto/gen/go/permissionb\x06proto3"
+func (x *AssignRoleRequest) Reset() {
+	*x = AssignRoleRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignRoleRequest) ProtoMessage() {}
+
+func (x *AssignRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignRoleRequest.ProtoReflect.Descriptor instead.
+func (*AssignRoleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AssignRoleRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AssignRoleRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *AssignRoleRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type AssignRoleResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Assigned bool                   `protobuf:"varint,1,opt,name=assigned,proto3" json:"assigned,omitempty"`
+	// Deprecated: failures are returned as gRPC status errors.
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignRoleResponse) Reset() {
+	*x = AssignRoleResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignRoleResponse) ProtoMessage() {}
+
+func (x *AssignRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignRoleResponse.ProtoReflect.Descriptor instead.
+func (*AssignRoleResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AssignRoleResponse) GetAssigned() bool {
+	if x != nil {
+		return x.Assigned
+	}
+	return false
+}
+
+func (x *AssignRoleResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type UnassignRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Domain        string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnassignRoleRequest) Reset() {
+	*x = UnassignRoleRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnassignRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnassignRoleRequest) ProtoMessage() {}
+
+func (x *UnassignRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnassignRoleRequest.ProtoReflect.Descriptor instead.
+func (*UnassignRoleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UnassignRoleRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UnassignRoleRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *UnassignRoleRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type UnassignRoleResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Unassigned bool                   `protobuf:"varint,1,opt,name=unassigned,proto3" json:"unassigned,omitempty"`
+	// Deprecated: failures are returned as gRPC status errors.
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnassignRoleResponse) Reset() {
+	*x = UnassignRoleResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnassignRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnassignRoleResponse) ProtoMessage() {}
+
+func (x *UnassignRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnassignRoleResponse.ProtoReflect.Descriptor instead.
+func (*UnassignRoleResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UnassignRoleResponse) GetUnassigned() bool {
+	if x != nil {
+		return x.Unassigned
+	}
+	return false
+}
+
+func (x *UnassignRoleResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ListRoleMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Domain        string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoleMembersRequest) Reset() {
+	*x = ListRoleMembersRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoleMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoleMembersRequest) ProtoMessage() {}
+
+func (x *ListRoleMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoleMembersRequest.ProtoReflect.Descriptor instead.
+func (*ListRoleMembersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListRoleMembersRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ListRoleMembersRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type ListRoleMembersResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserIds []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	// Deprecated: failures are returned as gRPC status errors.
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoleMembersResponse) Reset() {
+	*x = ListRoleMembersResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoleMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoleMembersResponse) ProtoMessage() {}
+
+func (x *ListRoleMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoleMembersResponse.ProtoReflect.Descriptor instead.
+func (*ListRoleMembersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListRoleMembersResponse) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *ListRoleMembersResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchCheckPermissionRequest struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Checks        []*CheckPermissionRequest `protobuf:"bytes,1,rep,name=checks,proto3" json:"checks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCheckPermissionRequest) Reset() {
+	*x = BatchCheckPermissionRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCheckPermissionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCheckPermissionRequest) ProtoMessage() {}
+
+func (x *BatchCheckPermissionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCheckPermissionRequest.ProtoReflect.Descriptor instead.
+func (*BatchCheckPermissionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchCheckPermissionRequest) GetChecks() []*CheckPermissionRequest {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+type BatchCheckPermissionResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Allowed []bool                 `protobuf:"varint,1,rep,packed,name=allowed,proto3" json:"allowed,omitempty"`
+	// Deprecated: failures are returned as gRPC status errors.
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCheckPermissionResponse) Reset() {
+	*x = BatchCheckPermissionResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCheckPermissionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCheckPermissionResponse) ProtoMessage() {}
+
+func (x *BatchCheckPermissionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCheckPermissionResponse.ProtoReflect.Descriptor instead.
+func (*BatchCheckPermissionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BatchCheckPermissionResponse) GetAllowed() []bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return nil
+}
+
+func (x *BatchCheckPermissionResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type Policy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Domain        string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	Resource      string                 `protobuf:"bytes,3,opt,name=resource,proto3" json:"resource,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Condition     string                 `protobuf:"bytes,5,opt,name=condition,proto3" json:"condition,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Policy) Reset() {
+	*x = Policy{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Policy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy) ProtoMessage() {}
+
+func (x *Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
+func (*Policy) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Policy) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Policy) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *Policy) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *Policy) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *Policy) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+type ListPermissionsForSubjectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Domain        string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPermissionsForSubjectRequest) Reset() {
+	*x = ListPermissionsForSubjectRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPermissionsForSubjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPermissionsForSubjectRequest) ProtoMessage() {}
+
+func (x *ListPermissionsForSubjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPermissionsForSubjectRequest.ProtoReflect.Descriptor instead.
+func (*ListPermissionsForSubjectRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListPermissionsForSubjectRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ListPermissionsForSubjectRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ListPermissionsForSubjectRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListPermissionsForSubjectRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListPermissionsForSubjectResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Permissions   []*Policy              `protobuf:"bytes,1,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Deprecated: failures are returned as gRPC status errors.
+	Error         string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPermissionsForSubjectResponse) Reset() {
+	*x = ListPermissionsForSubjectResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPermissionsForSubjectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPermissionsForSubjectResponse) ProtoMessage() {}
+
+func (x *ListPermissionsForSubjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPermissionsForSubjectResponse.ProtoReflect.Descriptor instead.
+func (*ListPermissionsForSubjectResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListPermissionsForSubjectResponse) GetPermissions() []*Policy {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+func (x *ListPermissionsForSubjectResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListPermissionsForSubjectResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ListSubjectsForResourceRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Resource string                 `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
+	// Empty action matches every action.
+	Action        string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Domain        string `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	PageSize      int32  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubjectsForResourceRequest) Reset() {
+	*x = ListSubjectsForResourceRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubjectsForResourceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubjectsForResourceRequest) ProtoMessage() {}
+
+func (x *ListSubjectsForResourceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubjectsForResourceRequest.ProtoReflect.Descriptor instead.
+func (*ListSubjectsForResourceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListSubjectsForResourceRequest) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *ListSubjectsForResourceRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ListSubjectsForResourceRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ListSubjectsForResourceRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListSubjectsForResourceRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListSubjectsForResourceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subjects      []string               `protobuf:"bytes,1,rep,name=subjects,proto3" json:"subjects,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Deprecated: failures are returned as gRPC status errors.
+	Error         string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubjectsForResourceResponse) Reset() {
+	*x = ListSubjectsForResourceResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubjectsForResourceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubjectsForResourceResponse) ProtoMessage() {}
+
+func (x *ListSubjectsForResourceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubjectsForResourceResponse.ProtoReflect.Descriptor instead.
+func (*ListSubjectsForResourceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListSubjectsForResourceResponse) GetSubjects() []string {
+	if x != nil {
+		return x.Subjects
+	}
+	return nil
+}
+
+func (x *ListSubjectsForResourceResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListSubjectsForResourceResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetImplicitRolesForUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Domain        string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetImplicitRolesForUserRequest) Reset() {
+	*x = GetImplicitRolesForUserRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetImplicitRolesForUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetImplicitRolesForUserRequest) ProtoMessage() {}
+
+func (x *GetImplicitRolesForUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetImplicitRolesForUserRequest.ProtoReflect.Descriptor instead.
+func (*GetImplicitRolesForUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetImplicitRolesForUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetImplicitRolesForUserRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type GetImplicitRolesForUserResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Roles []string               `protobuf:"bytes,1,rep,name=roles,proto3" json:"roles,omitempty"`
+	// Deprecated: failures are returned as gRPC status errors.
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetImplicitRolesForUserResponse) Reset() {
+	*x = GetImplicitRolesForUserResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetImplicitRolesForUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetImplicitRolesForUserResponse) ProtoMessage() {}
+
+func (x *GetImplicitRolesForUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetImplicitRolesForUserResponse.ProtoReflect.Descriptor instead.
+func (*GetImplicitRolesForUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetImplicitRolesForUserResponse) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *GetImplicitRolesForUserResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ExplainPermissionResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Allowed bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	// The policy that granted access, empty when the request is denied.
+	MatchedPolicies []*Policy `protobuf:"bytes,2,rep,name=matched_policies,json=matchedPolicies,proto3" json:"matched_policies,omitempty"`
+	Roles           []string  `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`
+	Reason          string    `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Deprecated: failures are returned as gRPC status errors.
+	Error         string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainPermissionResponse) Reset() {
+	*x = ExplainPermissionResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainPermissionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainPermissionResponse) ProtoMessage() {}
+
+func (x *ExplainPermissionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainPermissionResponse.ProtoReflect.Descriptor instead.
+func (*ExplainPermissionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ExplainPermissionResponse) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+func (x *ExplainPermissionResponse) GetMatchedPolicies() []*Policy {
+	if x != nil {
+		return x.MatchedPolicies
+	}
+	return nil
+}
+
+func (x *ExplainPermissionResponse) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *ExplainPermissionResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ExplainPermissionResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetPolicySnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPolicySnapshotRequest) Reset() {
+	*x = GetPolicySnapshotRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPolicySnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPolicySnapshotRequest) ProtoMessage() {}
+
+func (x *GetPolicySnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPolicySnapshotRequest.ProtoReflect.Descriptor instead.
+func (*GetPolicySnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{24}
+}
+
+// PolicyRule is a raw Casbin rule: its type (p, p2, g) and values.
+type PolicyRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ptype         string                 `protobuf:"bytes,1,opt,name=ptype,proto3" json:"ptype,omitempty"`
+	Values        []string               `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyRule) Reset() {
+	*x = PolicyRule{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyRule) ProtoMessage() {}
+
+func (x *PolicyRule) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyRule.ProtoReflect.Descriptor instead.
+func (*PolicyRule) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PolicyRule) GetPtype() string {
+	if x != nil {
+		return x.Ptype
+	}
+	return ""
+}
+
+func (x *PolicyRule) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type GetPolicySnapshotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*PolicyRule          `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPolicySnapshotResponse) Reset() {
+	*x = GetPolicySnapshotResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPolicySnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPolicySnapshotResponse) ProtoMessage() {}
+
+func (x *GetPolicySnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPolicySnapshotResponse.ProtoReflect.Descriptor instead.
+func (*GetPolicySnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetPolicySnapshotResponse) GetRules() []*PolicyRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type WatchPoliciesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Epoch and revision of the last event seen; empty to start fresh.
+	Epoch         string `protobuf:"bytes,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Revision      uint64 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchPoliciesRequest) Reset() {
+	*x = WatchPoliciesRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchPoliciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPoliciesRequest) ProtoMessage() {}
+
+func (x *WatchPoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPoliciesRequest.ProtoReflect.Descriptor instead.
+func (*WatchPoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *WatchPoliciesRequest) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+func (x *WatchPoliciesRequest) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+// PolicyEvent is one policy change. type is "add" or "remove" for the given
+// rules, "reload" when the client has to refetch every policy, and "sync"
+// once a resumed stream has caught up.
+type PolicyEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Epoch         string                 `protobuf:"bytes,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Revision      uint64                 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Rules         []*PolicyRule          `protobuf:"bytes,4,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyEvent) Reset() {
+	*x = PolicyEvent{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyEvent) ProtoMessage() {}
+
+func (x *PolicyEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyEvent.ProtoReflect.Descriptor instead.
+func (*PolicyEvent) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *PolicyEvent) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+func (x *PolicyEvent) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *PolicyEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PolicyEvent) GetRules() []*PolicyRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// PolicyFilter selects policies by exact field values. Empty fields match
+// any value.
+type PolicyFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Domain        string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	Resource      string                 `protobuf:"bytes,3,opt,name=resource,proto3" json:"resource,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyFilter) Reset() {
+	*x = PolicyFilter{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyFilter) ProtoMessage() {}
+
+func (x *PolicyFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyFilter.ProtoReflect.Descriptor instead.
+func (*PolicyFilter) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *PolicyFilter) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *PolicyFilter) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *PolicyFilter) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *PolicyFilter) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+type ListPoliciesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *PolicyFilter          `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPoliciesRequest) Reset() {
+	*x = ListPoliciesRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPoliciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoliciesRequest) ProtoMessage() {}
+
+func (x *ListPoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoliciesRequest.ProtoReflect.Descriptor instead.
+func (*ListPoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListPoliciesRequest) GetFilter() *PolicyFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListPoliciesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListPoliciesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListPoliciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policies      []*Policy              `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPoliciesResponse) Reset() {
+	*x = ListPoliciesResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPoliciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoliciesResponse) ProtoMessage() {}
+
+func (x *ListPoliciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoliciesResponse.ProtoReflect.Descriptor instead.
+func (*ListPoliciesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListPoliciesResponse) GetPolicies() []*Policy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+func (x *ListPoliciesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type ReplacePoliciesRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *PolicyFilter          `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Every policy has to match the filter. Empty domains mean the global one.
+	Policies      []*Policy `protobuf:"bytes,2,rep,name=policies,proto3" json:"policies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplacePoliciesRequest) Reset() {
+	*x = ReplacePoliciesRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplacePoliciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplacePoliciesRequest) ProtoMessage() {}
+
+func (x *ReplacePoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplacePoliciesRequest.ProtoReflect.Descriptor instead.
+func (*ReplacePoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ReplacePoliciesRequest) GetFilter() *PolicyFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ReplacePoliciesRequest) GetPolicies() []*Policy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+type ReplacePoliciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Added         int32                  `protobuf:"varint,1,opt,name=added,proto3" json:"added,omitempty"`
+	Removed       int32                  `protobuf:"varint,2,opt,name=removed,proto3" json:"removed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplacePoliciesResponse) Reset() {
+	*x = ReplacePoliciesResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplacePoliciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplacePoliciesResponse) ProtoMessage() {}
+
+func (x *ReplacePoliciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplacePoliciesResponse.ProtoReflect.Descriptor instead.
+func (*ReplacePoliciesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ReplacePoliciesResponse) GetAdded() int32 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *ReplacePoliciesResponse) GetRemoved() int32 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+type ListRolesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty lists the roles of every domain.
+	Domain        string `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRolesRequest) Reset() {
+	*x = ListRolesRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRolesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRolesRequest) ProtoMessage() {}
+
+func (x *ListRolesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRolesRequest.ProtoReflect.Descriptor instead.
+func (*ListRolesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListRolesRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type ListRolesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Roles         []string               `protobuf:"bytes,1,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRolesResponse) Reset() {
+	*x = ListRolesResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRolesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRolesResponse) ProtoMessage() {}
+
+func (x *ListRolesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRolesResponse.ProtoReflect.Descriptor instead.
+func (*ListRolesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListRolesResponse) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type SyncPoliciesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Rules []*PolicyRule          `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	Prune bool                   `protobuf:"varint,2,opt,name=prune,proto3" json:"prune,omitempty"`
+	// Only report what would change.
+	DryRun        bool `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncPoliciesRequest) Reset() {
+	*x = SyncPoliciesRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncPoliciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncPoliciesRequest) ProtoMessage() {}
+
+func (x *SyncPoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncPoliciesRequest.ProtoReflect.Descriptor instead.
+func (*SyncPoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SyncPoliciesRequest) GetRules() []*PolicyRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *SyncPoliciesRequest) GetPrune() bool {
+	if x != nil {
+		return x.Prune
+	}
+	return false
+}
+
+func (x *SyncPoliciesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type SyncPoliciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Added         []*PolicyRule          `protobuf:"bytes,1,rep,name=added,proto3" json:"added,omitempty"`
+	Removed       []*PolicyRule          `protobuf:"bytes,2,rep,name=removed,proto3" json:"removed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncPoliciesResponse) Reset() {
+	*x = SyncPoliciesResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncPoliciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncPoliciesResponse) ProtoMessage() {}
+
+func (x *SyncPoliciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncPoliciesResponse.ProtoReflect.Descriptor instead.
+func (*SyncPoliciesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *SyncPoliciesResponse) GetAdded() []*PolicyRule {
+	if x != nil {
+		return x.Added
+	}
+	return nil
+}
+
+func (x *SyncPoliciesResponse) GetRemoved() []*PolicyRule {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+type ListPolicyChangesRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Actor   string                 `protobuf:"bytes,1,opt,name=actor,proto3" json:"actor,omitempty"`
+	Service string                 `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	Subject string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	Domain  string                 `protobuf:"bytes,4,opt,name=domain,proto3" json:"domain,omitempty"`
+	// RFC 3339 bounds on when the change was made; since is inclusive.
+	Since         string `protobuf:"bytes,5,opt,name=since,proto3" json:"since,omitempty"`
+	Until         string `protobuf:"bytes,6,opt,name=until,proto3" json:"until,omitempty"`
+	PageSize      int32  `protobuf:"varint,7,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string `protobuf:"bytes,8,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPolicyChangesRequest) Reset() {
+	*x = ListPolicyChangesRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPolicyChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPolicyChangesRequest) ProtoMessage() {}
+
+func (x *ListPolicyChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPolicyChangesRequest.ProtoReflect.Descriptor instead.
+func (*ListPolicyChangesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListPolicyChangesRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *ListPolicyChangesRequest) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *ListPolicyChangesRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ListPolicyChangesRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ListPolicyChangesRequest) GetSince() string {
+	if x != nil {
+		return x.Since
+	}
+	return ""
+}
+
+func (x *ListPolicyChangesRequest) GetUntil() string {
+	if x != nil {
+		return x.Until
+	}
+	return ""
+}
+
+func (x *ListPolicyChangesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListPolicyChangesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type PolicyChange struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Revision uint64                 `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	// RFC 3339.
+	ChangedAt string `protobuf:"bytes,2,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	Service   string `protobuf:"bytes,3,opt,name=service,proto3" json:"service,omitempty"`
+	Actor     string `protobuf:"bytes,4,opt,name=actor,proto3" json:"actor,omitempty"`
+	// "add" or "remove".
+	Type string `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	// The rule removed and the rule added; the other is unset.
+	Before        *PolicyRule `protobuf:"bytes,6,opt,name=before,proto3" json:"before,omitempty"`
+	After         *PolicyRule `protobuf:"bytes,7,opt,name=after,proto3" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyChange) Reset() {
+	*x = PolicyChange{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyChange) ProtoMessage() {}
+
+func (x *PolicyChange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyChange.ProtoReflect.Descriptor instead.
+func (*PolicyChange) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *PolicyChange) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *PolicyChange) GetChangedAt() string {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return ""
+}
+
+func (x *PolicyChange) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *PolicyChange) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *PolicyChange) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PolicyChange) GetBefore() *PolicyRule {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *PolicyChange) GetAfter() *PolicyRule {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+type ListPolicyChangesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*PolicyChange        `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPolicyChangesResponse) Reset() {
+	*x = ListPolicyChangesResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPolicyChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPolicyChangesResponse) ProtoMessage() {}
+
+func (x *ListPolicyChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPolicyChangesResponse.ProtoReflect.Descriptor instead.
+func (*ListPolicyChangesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListPolicyChangesResponse) GetChanges() []*PolicyChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *ListPolicyChangesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type RevertPoliciesRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Revision uint64                 `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	// Only report what would change.
+	DryRun        bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevertPoliciesRequest) Reset() {
+	*x = RevertPoliciesRequest{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevertPoliciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevertPoliciesRequest) ProtoMessage() {}
+
+func (x *RevertPoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevertPoliciesRequest.ProtoReflect.Descriptor instead.
+func (*RevertPoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *RevertPoliciesRequest) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *RevertPoliciesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type RevertPoliciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Added         []*PolicyRule          `protobuf:"bytes,1,rep,name=added,proto3" json:"added,omitempty"`
+	Removed       []*PolicyRule          `protobuf:"bytes,2,rep,name=removed,proto3" json:"removed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevertPoliciesResponse) Reset() {
+	*x = RevertPoliciesResponse{}
+	mi := &file_proto_permission_permission_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevertPoliciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevertPoliciesResponse) ProtoMessage() {}
+
+func (x *RevertPoliciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_permission_permission_service_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevertPoliciesResponse.ProtoReflect.Descriptor instead.
+func (*RevertPoliciesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_permission_permission_service_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *RevertPoliciesResponse) GetAdded() []*PolicyRule {
+	if x != nil {
+		return x.Added
+	}
+	return nil
+}
+
+func (x *RevertPoliciesResponse) GetRemoved() []*PolicyRule {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+var File_proto_permission_permission_service_proto protoreflect.FileDescriptor
+
+const file_proto_permission_permission_service_proto_rawDesc = "" +
+	"\n" +
+	")proto/permission/permission_service.proto\x12\n" +
+	"permission\"\xf8\x04\n" +
+	"\x16CheckPermissionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bresource\x18\x02 \x01(\tR\bresource\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x16\n" +
+	"\x06domain\x18\x04 \x01(\tR\x06domain\x12h\n" +
+	"\x12subject_attributes\x18\x05 \x03(\v29.permission.CheckPermissionRequest.SubjectAttributesEntryR\x11subjectAttributes\x12k\n" +
+	"\x13resource_attributes\x18\x06 \x03(\v2:.permission.CheckPermissionRequest.ResourceAttributesEntryR\x12resourceAttributes\x12U\n" +
+	"\venvironment\x18\a \x03(\v23.permission.CheckPermissionRequest.EnvironmentEntryR\venvironment\x1aD\n" +
+	"\x16SubjectAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aE\n" +
+	"\x17ResourceAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a>\n" +
+	"\x10EnvironmentEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"I\n" +
+	"\x17CheckPermissionResponse\x12\x18\n" +
+	"\aallowed\x18\x01 \x01(\bR\aallowed\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x9a\x01\n" +
+	"\x10AddPolicyRequest\x12\x16\n" +
+	"\x06policy\x18\x01 \x01(\tR\x06policy\x12\x18\n" +
+	"\aresorce\x18\x02 \x01(\tR\aresorce\x12\x1e\n" +
+	"\n" +
+	"permission\x18\x03 \x01(\tR\n" +
+	"permission\x12\x16\n" +
+	"\x06domain\x18\x04 \x01(\tR\x06domain\x12\x1c\n" +
+	"\tcondition\x18\x05 \x01(\tR\tcondition\"?\n" +
+	"\x11AddPolicyResponse\x12\x14\n" +
+	"\x05added\x18\x01 \x01(\bR\x05added\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x9f\x01\n" +
+	"\x13RemovePolicyRequest\x12\x16\n" +
+	"\x06policy\x18\x01 \x01(\tR\x06policy\x12\x1a\n" +
+	"\bresource\x18\x02 \x01(\tR\bresource\x12\x1e\n" +
+	"\n" +
+	"permission\x18\x03 \x01(\tR\n" +
+	"permission\x12\x16\n" +
+	"\x06domain\x18\x04 \x01(\tR\x06domain\x12\x1c\n" +
+	"\tcondition\x18\x05 \x01(\tR\tcondition\"F\n" +
+	"\x14RemovePolicyResponse\x12\x18\n" +
+	"\aremoved\x18\x01 \x01(\bR\aremoved\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x89\x01\n" +
+	"\x1bAddPolicyIfNotExistsRequest\x12\x16\n" +
+	"\x06policy\x18\x01 \x01(\tR\x06policy\x12\x1a\n" +
+	"\bresource\x18\x02 \x01(\tR\bresource\x12\x1e\n" +
+	"\n" +
+	"permission\x18\x03 \x01(\tR\n" +
+	"permission\x12\x16\n" +
+	"\x06domain\x18\x04 \x01(\tR\x06domain\"L\n" +
+	"\x1cAddPolicyIfNotExistsResponse\x12\x16\n" +
+	"\x06exists\x18\x01 \x01(\bR\x06exists\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"X\n" +
+	"\x11AssignRoleRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\"F\n" +
+	"\x12AssignRoleResponse\x12\x1a\n" +
+	"\bassigned\x18\x01 \x01(\bR\bassigned\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"Z\n" +
+	"\x13UnassignRoleRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\"L\n" +
+	"\x14UnassignRoleResponse\x12\x1e\n" +
+	"\n" +
+	"unassigned\x18\x01 \x01(\bR\n" +
+	"unassigned\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"D\n" +
+	"\x16ListRoleMembersRequest\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\"J\n" +
+	"\x17ListRoleMembersResponse\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"Y\n" +
+	"\x1bBatchCheckPermissionRequest\x12:\n" +
+	"\x06checks\x18\x01 \x03(\v2\".permission.CheckPermissionRequestR\x06checks\"N\n" +
+	"\x1cBatchCheckPermissionResponse\x12\x18\n" +
+	"\aallowed\x18\x01 \x03(\bR\aallowed\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x8c\x01\n" +
+	"\x06Policy\x12\x18\n" +
+	"\asubject\x18\x01 \x01(\tR\asubject\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x1a\n" +
+	"\bresource\x18\x03 \x01(\tR\bresource\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x1c\n" +
+	"\tcondition\x18\x05 \x01(\tR\tcondition\"\x90\x01\n" +
+	" ListPermissionsForSubjectRequest\x12\x18\n" +
+	"\asubject\x18\x01 \x01(\tR\asubject\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"\x97\x01\n" +
+	"!ListPermissionsForSubjectResponse\x124\n" +
+	"\vpermissions\x18\x01 \x03(\v2\x12.permission.PolicyR\vpermissions\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xa8\x01\n" +
+	"\x1eListSubjectsForResourceRequest\x12\x1a\n" +
+	"\bresource\x18\x01 \x01(\tR\bresource\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\"{\n" +
+	"\x1fListSubjectsForResourceResponse\x12\x1a\n" +
+	"\bsubjects\x18\x01 \x03(\tR\bsubjects\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"Q\n" +
+	"\x1eGetImplicitRolesForUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\"M\n" +
+	"\x1fGetImplicitRolesForUserResponse\x12\x14\n" +
+	"\x05roles\x18\x01 \x03(\tR\x05roles\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\xb8\x01\n" +
+	"\x19ExplainPermissionResponse\x12\x18\n" +
+	"\aallowed\x18\x01 \x01(\bR\aallowed\x12=\n" +
+	"\x10matched_policies\x18\x02 \x03(\v2\x12.permission.PolicyR\x0fmatchedPolicies\x12\x14\n" +
+	"\x05roles\x18\x03 \x03(\tR\x05roles\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"\x1a\n" +
+	"\x18GetPolicySnapshotRequest\":\n" +
+	"\n" +
+	"PolicyRule\x12\x14\n" +
+	"\x05ptype\x18\x01 \x01(\tR\x05ptype\x12\x16\n" +
+	"\x06values\x18\x02 \x03(\tR\x06values\"I\n" +
+	"\x19GetPolicySnapshotResponse\x12,\n" +
+	"\x05rules\x18\x01 \x03(\v2\x16.permission.PolicyRuleR\x05rules\"H\n" +
+	"\x14WatchPoliciesRequest\x12\x14\n" +
+	"\x05epoch\x18\x01 \x01(\tR\x05epoch\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x04R\brevision\"\x81\x01\n" +
+	"\vPolicyEvent\x12\x14\n" +
+	"\x05epoch\x18\x01 \x01(\tR\x05epoch\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x04R\brevision\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12,\n" +
+	"\x05rules\x18\x04 \x03(\v2\x16.permission.PolicyRuleR\x05rules\"t\n" +
+	"\fPolicyFilter\x12\x18\n" +
+	"\asubject\x18\x01 \x01(\tR\asubject\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x1a\n" +
+	"\bresource\x18\x03 \x01(\tR\bresource\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\"\x83\x01\n" +
+	"\x13ListPoliciesRequest\x120\n" +
+	"\x06filter\x18\x01 \x01(\v2\x18.permission.PolicyFilterR\x06filter\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"n\n" +
+	"\x14ListPoliciesResponse\x12.\n" +
+	"\bpolicies\x18\x01 \x03(\v2\x12.permission.PolicyR\bpolicies\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"z\n" +
+	"\x16ReplacePoliciesRequest\x120\n" +
+	"\x06filter\x18\x01 \x01(\v2\x18.permission.PolicyFilterR\x06filter\x12.\n" +
+	"\bpolicies\x18\x02 \x03(\v2\x12.permission.PolicyR\bpolicies\"I\n" +
+	"\x17ReplacePoliciesResponse\x12\x14\n" +
+	"\x05added\x18\x01 \x01(\x05R\x05added\x12\x18\n" +
+	"\aremoved\x18\x02 \x01(\x05R\aremoved\"*\n" +
+	"\x10ListRolesRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\")\n" +
+	"\x11ListRolesResponse\x12\x14\n" +
+	"\x05roles\x18\x01 \x03(\tR\x05roles\"r\n" +
+	"\x13SyncPoliciesRequest\x12,\n" +
+	"\x05rules\x18\x01 \x03(\v2\x16.permission.PolicyRuleR\x05rules\x12\x14\n" +
+	"\x05prune\x18\x02 \x01(\bR\x05prune\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"v\n" +
+	"\x14SyncPoliciesResponse\x12,\n" +
+	"\x05added\x18\x01 \x03(\v2\x16.permission.PolicyRuleR\x05added\x120\n" +
+	"\aremoved\x18\x02 \x03(\v2\x16.permission.PolicyRuleR\aremoved\"\xe4\x01\n" +
+	"\x18ListPolicyChangesRequest\x12\x14\n" +
+	"\x05actor\x18\x01 \x01(\tR\x05actor\x12\x18\n" +
+	"\aservice\x18\x02 \x01(\tR\aservice\x12\x18\n" +
+	"\asubject\x18\x03 \x01(\tR\asubject\x12\x16\n" +
+	"\x06domain\x18\x04 \x01(\tR\x06domain\x12\x14\n" +
+	"\x05since\x18\x05 \x01(\tR\x05since\x12\x14\n" +
+	"\x05until\x18\x06 \x01(\tR\x05until\x12\x1b\n" +
+	"\tpage_size\x18\a \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\b \x01(\tR\tpageToken\"\xeb\x01\n" +
+	"\fPolicyChange\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\x04R\brevision\x12\x1d\n" +
+	"\n" +
+	"changed_at\x18\x02 \x01(\tR\tchangedAt\x12\x18\n" +
+	"\aservice\x18\x03 \x01(\tR\aservice\x12\x14\n" +
+	"\x05actor\x18\x04 \x01(\tR\x05actor\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12.\n" +
+	"\x06before\x18\x06 \x01(\v2\x16.permission.PolicyRuleR\x06before\x12,\n" +
+	"\x05after\x18\a \x01(\v2\x16.permission.PolicyRuleR\x05after\"w\n" +
+	"\x19ListPolicyChangesResponse\x122\n" +
+	"\achanges\x18\x01 \x03(\v2\x18.permission.PolicyChangeR\achanges\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"L\n" +
+	"\x15RevertPoliciesRequest\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\x04R\brevision\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"x\n" +
+	"\x16RevertPoliciesResponse\x12,\n" +
+	"\x05added\x18\x01 \x03(\v2\x16.permission.PolicyRuleR\x05added\x120\n" +
+	"\aremoved\x18\x02 \x03(\v2\x16.permission.PolicyRuleR\aremoved2\xd7\x0e\n" +
+	"\x11PermissionService\x12Z\n" +
+	"\x0fCheckPermission\x12\".permission.CheckPermissionRequest\x1a#.permission.CheckPermissionResponse\x12H\n" +
+	"\tAddPolicy\x12\x1c.permission.AddPolicyRequest\x1a\x1d.permission.AddPolicyResponse\x12Q\n" +
+	"\fRemovePolicy\x12\x1f.permission.RemovePolicyRequest\x1a .permission.RemovePolicyResponse\x12i\n" +
+	"\x14AddPolicyIfNotExists\x12'.permission.AddPolicyIfNotExistsRequest\x1a(.permission.AddPolicyIfNotExistsResponse\x12K\n" +
+	"\n" +
+	"AssignRole\x12\x1d.permission.AssignRoleRequest\x1a\x1e.permission.AssignRoleResponse\x12Q\n" +
+	"\fUnassignRole\x12\x1f.permission.UnassignRoleRequest\x1a .permission.UnassignRoleResponse\x12Z\n" +
+	"\x0fListRoleMembers\x12\".permission.ListRoleMembersRequest\x1a#.permission.ListRoleMembersResponse\x12i\n" +
+	"\x14BatchCheckPermission\x12'.permission.BatchCheckPermissionRequest\x1a(.permission.BatchCheckPermissionResponse\x12x\n" +
+	"\x19ListPermissionsForSubject\x12,.permission.ListPermissionsForSubjectRequest\x1a-.permission.ListPermissionsForSubjectResponse\x12r\n" +
+	"\x17ListSubjectsForResource\x12*.permission.ListSubjectsForResourceRequest\x1a+.permission.ListSubjectsForResourceResponse\x12r\n" +
+	"\x17GetImplicitRolesForUser\x12*.permission.GetImplicitRolesForUserRequest\x1a+.permission.GetImplicitRolesForUserResponse\x12^\n" +
+	"\x11ExplainPermission\x12\".permission.CheckPermissionRequest\x1a%.permission.ExplainPermissionResponse\x12`\n" +
+	"\x11GetPolicySnapshot\x12$.permission.GetPolicySnapshotRequest\x1a%.permission.GetPolicySnapshotResponse\x12L\n" +
+	"\rWatchPolicies\x12 .permission.WatchPoliciesRequest\x1a\x17.permission.PolicyEvent0\x01\x12Q\n" +
+	"\fListPolicies\x12\x1f.permission.ListPoliciesRequest\x1a .permission.ListPoliciesResponse\x12Z\n" +
+	"\x0fReplacePolicies\x12\".permission.ReplacePoliciesRequest\x1a#.permission.ReplacePoliciesResponse\x12H\n" +
+	"\tListRoles\x12\x1c.permission.ListRolesRequest\x1a\x1d.permission.ListRolesResponse\x12Q\n" +
+	"\fSyncPolicies\x12\x1f.permission.SyncPoliciesRequest\x1a .permission.SyncPoliciesResponse\x12`\n" +
+	"\x11ListPolicyChanges\x12$.permission.ListPolicyChangesRequest\x1a%.permission.ListPolicyChangesResponse\x12W\n" +
+	"\x0eRevertPolicies\x12!.permission.RevertPoliciesRequest\x1a\".permission.RevertPoliciesResponseB=Z;github.com/mrhumster/web-server-gin/proto/gen/go/permissionb\x06proto3"
 
 var (
 	file_proto_permission_permission_service_proto_rawDescOnce sync.Once
@@ -529,32 +2836,122 @@ func file_proto_permission_permission_service_proto_rawDescGZIP() []byte {
 }
 
 var (
-	file_proto_permission_permission_service_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+	file_proto_permission_permission_service_proto_msgTypes = make([]protoimpl.MessageInfo, 46)
 	file_proto_permission_permission_service_proto_goTypes  = []any{
-		(*CheckPermissionRequest)(nil),       // 0: permission.CheckPermissionRequest
-		(*CheckPermissionResponse)(nil),      // 1: permission.CheckPermissionResponse
-		(*AddPolicyRequest)(nil),             // 2: permission.AddPolicyRequest
-		(*AddPolicyResponse)(nil),            // 3: permission.AddPolicyResponse
-		(*RemovePolicyRequest)(nil),          // 4: permission.RemovePolicyRequest
-		(*RemovePolicyResponse)(nil),         // 5: permission.RemovePolicyResponse
-		(*AddPolicyIfNotExistsRequest)(nil),  // 6: permission.AddPolicyIfNotExistsRequest
-		(*AddPolicyIfNotExistsResponse)(nil), // 7: permission.AddPolicyIfNotExistsResponse
+		(*CheckPermissionRequest)(nil),            // 0: permission.CheckPermissionRequest
+		(*CheckPermissionResponse)(nil),           // 1: permission.CheckPermissionResponse
+		(*AddPolicyRequest)(nil),                  // 2: permission.AddPolicyRequest
+		(*AddPolicyResponse)(nil),                 // 3: permission.AddPolicyResponse
+		(*RemovePolicyRequest)(nil),               // 4: permission.RemovePolicyRequest
+		(*RemovePolicyResponse)(nil),              // 5: permission.RemovePolicyResponse
+		(*AddPolicyIfNotExistsRequest)(nil),       // 6: permission.AddPolicyIfNotExistsRequest
+		(*AddPolicyIfNotExistsResponse)(nil),      // 7: permission.AddPolicyIfNotExistsResponse
+		(*AssignRoleRequest)(nil),                 // 8: permission.AssignRoleRequest
+		(*AssignRoleResponse)(nil),                // 9: permission.AssignRoleResponse
+		(*UnassignRoleRequest)(nil),               // 10: permission.UnassignRoleRequest
+		(*UnassignRoleResponse)(nil),              // 11: permission.UnassignRoleResponse
+		(*ListRoleMembersRequest)(nil),            // 12: permission.ListRoleMembersRequest
+		(*ListRoleMembersResponse)(nil),           // 13: permission.ListRoleMembersResponse
+		(*BatchCheckPermissionRequest)(nil),       // 14: permission.BatchCheckPermissionRequest
+		(*BatchCheckPermissionResponse)(nil),      // 15: permission.BatchCheckPermissionResponse
+		(*Policy)(nil),                            // 16: permission.Policy
+		(*ListPermissionsForSubjectRequest)(nil),  // 17: permission.ListPermissionsForSubjectRequest
+		(*ListPermissionsForSubjectResponse)(nil), // 18: permission.ListPermissionsForSubjectResponse
+		(*ListSubjectsForResourceRequest)(nil),    // 19: permission.ListSubjectsForResourceRequest
+		(*ListSubjectsForResourceResponse)(nil),   // 20: permission.ListSubjectsForResourceResponse
+		(*GetImplicitRolesForUserRequest)(nil),    // 21: permission.GetImplicitRolesForUserRequest
+		(*GetImplicitRolesForUserResponse)(nil),   // 22: permission.GetImplicitRolesForUserResponse
+		(*ExplainPermissionResponse)(nil),         // 23: permission.ExplainPermissionResponse
+		(*GetPolicySnapshotRequest)(nil),          // 24: permission.GetPolicySnapshotRequest
+		(*PolicyRule)(nil),                        // 25: permission.PolicyRule
+		(*GetPolicySnapshotResponse)(nil),         // 26: permission.GetPolicySnapshotResponse
+		(*WatchPoliciesRequest)(nil),              // 27: permission.WatchPoliciesRequest
+		(*PolicyEvent)(nil),                       // 28: permission.PolicyEvent
+		(*PolicyFilter)(nil),                      // 29: permission.PolicyFilter
+		(*ListPoliciesRequest)(nil),               // 30: permission.ListPoliciesRequest
+		(*ListPoliciesResponse)(nil),              // 31: permission.ListPoliciesResponse
+		(*ReplacePoliciesRequest)(nil),            // 32: permission.ReplacePoliciesRequest
+		(*ReplacePoliciesResponse)(nil),           // 33: permission.ReplacePoliciesResponse
+		(*ListRolesRequest)(nil),                  // 34: permission.ListRolesRequest
+		(*ListRolesResponse)(nil),                 // 35: permission.ListRolesResponse
+		(*SyncPoliciesRequest)(nil),               // 36: permission.SyncPoliciesRequest
+		(*SyncPoliciesResponse)(nil),              // 37: permission.SyncPoliciesResponse
+		(*ListPolicyChangesRequest)(nil),          // 38: permission.ListPolicyChangesRequest
+		(*PolicyChange)(nil),                      // 39: permission.PolicyChange
+		(*ListPolicyChangesResponse)(nil),         // 40: permission.ListPolicyChangesResponse
+		(*RevertPoliciesRequest)(nil),             // 41: permission.RevertPoliciesRequest
+		(*RevertPoliciesResponse)(nil),            // 42: permission.RevertPoliciesResponse
+		nil,                                       // 43: permission.CheckPermissionRequest.SubjectAttributesEntry
+		nil,                                       // 44: permission.CheckPermissionRequest.ResourceAttributesEntry
+		nil,                                       // 45: permission.CheckPermissionRequest.EnvironmentEntry
 	}
 )
 var file_proto_permission_permission_service_proto_depIdxs = []int32{
-	0, // 0: permission.PermissionService.CheckPermission:input_type -> permission.CheckPermissionRequest
-	2, // 1: permission.PermissionService.AddPolicy:input_type -> permission.AddPolicyRequest
-	4, // 2: permission.PermissionService.RemovePolicy:input_type -> permission.RemovePolicyRequest
-	6, // 3: permission.PermissionService.AddPolicyIfNotExists:input_type -> permission.AddPolicyIfNotExistsRequest
-	1, // 4: permission.PermissionService.CheckPermission:output_type -> permission.CheckPermissionResponse
-	3, // 5: permission.PermissionService.AddPolicy:output_type -> permission.AddPolicyResponse
-	5, // 6: permission.PermissionService.RemovePolicy:output_type -> permission.RemovePolicyResponse
-	7, // 7: permission.PermissionService.AddPolicyIfNotExists:output_type -> permission.AddPolicyIfNotExistsResponse
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	43, // 0: permission.CheckPermissionRequest.subject_attributes:type_name -> permission.CheckPermissionRequest.SubjectAttributesEntry
+	44, // 1: permission.CheckPermissionRequest.resource_attributes:type_name -> permission.CheckPermissionRequest.ResourceAttributesEntry
+	45, // 2: permission.CheckPermissionRequest.environment:type_name -> permission.CheckPermissionRequest.EnvironmentEntry
+	0,  // 3: permission.BatchCheckPermissionRequest.checks:type_name -> permission.CheckPermissionRequest
+	16, // 4: permission.ListPermissionsForSubjectResponse.permissions:type_name -> permission.Policy
+	16, // 5: permission.ExplainPermissionResponse.matched_policies:type_name -> permission.Policy
+	25, // 6: permission.GetPolicySnapshotResponse.rules:type_name -> permission.PolicyRule
+	25, // 7: permission.PolicyEvent.rules:type_name -> permission.PolicyRule
+	29, // 8: permission.ListPoliciesRequest.filter:type_name -> permission.PolicyFilter
+	16, // 9: permission.ListPoliciesResponse.policies:type_name -> permission.Policy
+	29, // 10: permission.ReplacePoliciesRequest.filter:type_name -> permission.PolicyFilter
+	16, // 11: permission.ReplacePoliciesRequest.policies:type_name -> permission.Policy
+	25, // 12: permission.SyncPoliciesRequest.rules:type_name -> permission.PolicyRule
+	25, // 13: permission.SyncPoliciesResponse.added:type_name -> permission.PolicyRule
+	25, // 14: permission.SyncPoliciesResponse.removed:type_name -> permission.PolicyRule
+	25, // 15: permission.PolicyChange.before:type_name -> permission.PolicyRule
+	25, // 16: permission.PolicyChange.after:type_name -> permission.PolicyRule
+	39, // 17: permission.ListPolicyChangesResponse.changes:type_name -> permission.PolicyChange
+	25, // 18: permission.RevertPoliciesResponse.added:type_name -> permission.PolicyRule
+	25, // 19: permission.RevertPoliciesResponse.removed:type_name -> permission.PolicyRule
+	0,  // 20: permission.PermissionService.CheckPermission:input_type -> permission.CheckPermissionRequest
+	2,  // 21: permission.PermissionService.AddPolicy:input_type -> permission.AddPolicyRequest
+	4,  // 22: permission.PermissionService.RemovePolicy:input_type -> permission.RemovePolicyRequest
+	6,  // 23: permission.PermissionService.AddPolicyIfNotExists:input_type -> permission.AddPolicyIfNotExistsRequest
+	8,  // 24: permission.PermissionService.AssignRole:input_type -> permission.AssignRoleRequest
+	10, // 25: permission.PermissionService.UnassignRole:input_type -> permission.UnassignRoleRequest
+	12, // 26: permission.PermissionService.ListRoleMembers:input_type -> permission.ListRoleMembersRequest
+	14, // 27: permission.PermissionService.BatchCheckPermission:input_type -> permission.BatchCheckPermissionRequest
+	17, // 28: permission.PermissionService.ListPermissionsForSubject:input_type -> permission.ListPermissionsForSubjectRequest
+	19, // 29: permission.PermissionService.ListSubjectsForResource:input_type -> permission.ListSubjectsForResourceRequest
+	21, // 30: permission.PermissionService.GetImplicitRolesForUser:input_type -> permission.GetImplicitRolesForUserRequest
+	0,  // 31: permission.PermissionService.ExplainPermission:input_type -> permission.CheckPermissionRequest
+	24, // 32: permission.PermissionService.GetPolicySnapshot:input_type -> permission.GetPolicySnapshotRequest
+	27, // 33: permission.PermissionService.WatchPolicies:input_type -> permission.WatchPoliciesRequest
+	30, // 34: permission.PermissionService.ListPolicies:input_type -> permission.ListPoliciesRequest
+	32, // 35: permission.PermissionService.ReplacePolicies:input_type -> permission.ReplacePoliciesRequest
+	34, // 36: permission.PermissionService.ListRoles:input_type -> permission.ListRolesRequest
+	36, // 37: permission.PermissionService.SyncPolicies:input_type -> permission.SyncPoliciesRequest
+	38, // 38: permission.PermissionService.ListPolicyChanges:input_type -> permission.ListPolicyChangesRequest
+	41, // 39: permission.PermissionService.RevertPolicies:input_type -> permission.RevertPoliciesRequest
+	1,  // 40: permission.PermissionService.CheckPermission:output_type -> permission.CheckPermissionResponse
+	3,  // 41: permission.PermissionService.AddPolicy:output_type -> permission.AddPolicyResponse
+	5,  // 42: permission.PermissionService.RemovePolicy:output_type -> permission.RemovePolicyResponse
+	7,  // 43: permission.PermissionService.AddPolicyIfNotExists:output_type -> permission.AddPolicyIfNotExistsResponse
+	9,  // 44: permission.PermissionService.AssignRole:output_type -> permission.AssignRoleResponse
+	11, // 45: permission.PermissionService.UnassignRole:output_type -> permission.UnassignRoleResponse
+	13, // 46: permission.PermissionService.ListRoleMembers:output_type -> permission.ListRoleMembersResponse
+	15, // 47: permission.PermissionService.BatchCheckPermission:output_type -> permission.BatchCheckPermissionResponse
+	18, // 48: permission.PermissionService.ListPermissionsForSubject:output_type -> permission.ListPermissionsForSubjectResponse
+	20, // 49: permission.PermissionService.ListSubjectsForResource:output_type -> permission.ListSubjectsForResourceResponse
+	22, // 50: permission.PermissionService.GetImplicitRolesForUser:output_type -> permission.GetImplicitRolesForUserResponse
+	23, // 51: permission.PermissionService.ExplainPermission:output_type -> permission.ExplainPermissionResponse
+	26, // 52: permission.PermissionService.GetPolicySnapshot:output_type -> permission.GetPolicySnapshotResponse
+	28, // 53: permission.PermissionService.WatchPolicies:output_type -> permission.PolicyEvent
+	31, // 54: permission.PermissionService.ListPolicies:output_type -> permission.ListPoliciesResponse
+	33, // 55: permission.PermissionService.ReplacePolicies:output_type -> permission.ReplacePoliciesResponse
+	35, // 56: permission.PermissionService.ListRoles:output_type -> permission.ListRolesResponse
+	37, // 57: permission.PermissionService.SyncPolicies:output_type -> permission.SyncPoliciesResponse
+	40, // 58: permission.PermissionService.ListPolicyChanges:output_type -> permission.ListPolicyChangesResponse
+	42, // 59: permission.PermissionService.RevertPolicies:output_type -> permission.RevertPoliciesResponse
+	40, // [40:60] is the sub-list for method output_type
+	20, // [20:40] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_proto_permission_permission_service_proto_init() }
@@ -568,7 +2965,7 @@ func file_proto_permission_permission_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_permission_permission_service_proto_rawDesc), len(file_proto_permission_permission_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   46,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,10 +19,26 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PermissionService_CheckPermission_FullMethodName      = "/permission.PermissionService/CheckPermission"
-	PermissionService_AddPolicy_FullMethodName            = "/permission.PermissionService/AddPolicy"
-	PermissionService_RemovePolicy_FullMethodName         = "/permission.PermissionService/RemovePolicy"
-	PermissionService_AddPolicyIfNotExists_FullMethodName = "/permission.PermissionService/AddPolicyIfNotExists"
+	PermissionService_CheckPermission_FullMethodName           = "/permission.PermissionService/CheckPermission"
+	PermissionService_AddPolicy_FullMethodName                 = "/permission.PermissionService/AddPolicy"
+	PermissionService_RemovePolicy_FullMethodName              = "/permission.PermissionService/RemovePolicy"
+	PermissionService_AddPolicyIfNotExists_FullMethodName      = "/permission.PermissionService/AddPolicyIfNotExists"
+	PermissionService_AssignRole_FullMethodName                = "/permission.PermissionService/AssignRole"
+	PermissionService_UnassignRole_FullMethodName              = "/permission.PermissionService/UnassignRole"
+	PermissionService_ListRoleMembers_FullMethodName           = "/permission.PermissionService/ListRoleMembers"
+	PermissionService_BatchCheckPermission_FullMethodName      = "/permission.PermissionService/BatchCheckPermission"
+	PermissionService_ListPermissionsForSubject_FullMethodName = "/permission.PermissionService/ListPermissionsForSubject"
+	PermissionService_ListSubjectsForResource_FullMethodName   = "/permission.PermissionService/ListSubjectsForResource"
+	PermissionService_GetImplicitRolesForUser_FullMethodName   = "/permission.PermissionService/GetImplicitRolesForUser"
+	PermissionService_ExplainPermission_FullMethodName         = "/permission.PermissionService/ExplainPermission"
+	PermissionService_GetPolicySnapshot_FullMethodName         = "/permission.PermissionService/GetPolicySnapshot"
+	PermissionService_WatchPolicies_FullMethodName             = "/permission.PermissionService/WatchPolicies"
+	PermissionService_ListPolicies_FullMethodName              = "/permission.PermissionService/ListPolicies"
+	PermissionService_ReplacePolicies_FullMethodName           = "/permission.PermissionService/ReplacePolicies"
+	PermissionService_ListRoles_FullMethodName                 = "/permission.PermissionService/ListRoles"
+	PermissionService_SyncPolicies_FullMethodName              = "/permission.PermissionService/SyncPolicies"
+	PermissionService_ListPolicyChanges_FullMethodName         = "/permission.PermissionService/ListPolicyChanges"
+	PermissionService_RevertPolicies_FullMethodName            = "/permission.PermissionService/RevertPolicies"
 )
 
 // PermissionServiceClient is the client API for PermissionService service.
@@ -33,6 +49,44 @@ type PermissionServiceClient interface {
 	AddPolicy(ctx context.Context, in *AddPolicyRequest, opts ...grpc.CallOption) (*AddPolicyResponse, error)
 	RemovePolicy(ctx context.Context, in *RemovePolicyRequest, opts ...grpc.CallOption) (*RemovePolicyResponse, error)
 	AddPolicyIfNotExists(ctx context.Context, in *AddPolicyIfNotExistsRequest, opts ...grpc.CallOption) (*AddPolicyIfNotExistsResponse, error)
+	AssignRole(ctx context.Context, in *AssignRoleRequest, opts ...grpc.CallOption) (*AssignRoleResponse, error)
+	UnassignRole(ctx context.Context, in *UnassignRoleRequest, opts ...grpc.CallOption) (*UnassignRoleResponse, error)
+	ListRoleMembers(ctx context.Context, in *ListRoleMembersRequest, opts ...grpc.CallOption) (*ListRoleMembersResponse, error)
+	// BatchCheckPermission evaluates many checks in one round trip. Decisions
+	// are returned in request order.
+	BatchCheckPermission(ctx context.Context, in *BatchCheckPermissionRequest, opts ...grpc.CallOption) (*BatchCheckPermissionResponse, error)
+	// ListPermissionsForSubject lists the policies that apply to a user in a
+	// domain, directly or through roles.
+	ListPermissionsForSubject(ctx context.Context, in *ListPermissionsForSubjectRequest, opts ...grpc.CallOption) (*ListPermissionsForSubjectResponse, error)
+	// ListSubjectsForResource lists the users and roles allowed to act on a
+	// resource. Conditional (ABAC) rules are not included.
+	ListSubjectsForResource(ctx context.Context, in *ListSubjectsForResourceRequest, opts ...grpc.CallOption) (*ListSubjectsForResourceResponse, error)
+	GetImplicitRolesForUser(ctx context.Context, in *GetImplicitRolesForUserRequest, opts ...grpc.CallOption) (*GetImplicitRolesForUserResponse, error)
+	// ExplainPermission evaluates a check like CheckPermission and reports the
+	// policy that decided it together with the subject's roles.
+	ExplainPermission(ctx context.Context, in *CheckPermissionRequest, opts ...grpc.CallOption) (*ExplainPermissionResponse, error)
+	// GetPolicySnapshot returns every policy and grouping rule, for clients
+	// that enforce locally.
+	GetPolicySnapshot(ctx context.Context, in *GetPolicySnapshotRequest, opts ...grpc.CallOption) (*GetPolicySnapshotResponse, error)
+	// WatchPolicies streams policy changes. A client resuming after a
+	// disconnect passes the epoch and revision of the last event it handled.
+	WatchPolicies(ctx context.Context, in *WatchPoliciesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PolicyEvent], error)
+	// ListPolicies returns the stored policies matching a filter, unlike
+	// ListPermissionsForSubject, which resolves roles and domains.
+	ListPolicies(ctx context.Context, in *ListPoliciesRequest, opts ...grpc.CallOption) (*ListPoliciesResponse, error)
+	// ReplacePolicies makes the policies matching the filter exactly the given
+	// ones, in one change.
+	ReplacePolicies(ctx context.Context, in *ReplacePoliciesRequest, opts ...grpc.CallOption) (*ReplacePoliciesResponse, error)
+	ListRoles(ctx context.Context, in *ListRolesRequest, opts ...grpc.CallOption) (*ListRolesResponse, error)
+	// SyncPolicies adds the given rules that are missing and, with prune,
+	// removes the stored ones the request doesn't list. Pruning is limited to
+	// the domains of the given policies and the subjects of the given role
+	// links, so rules made at runtime elsewhere are kept.
+	SyncPolicies(ctx context.Context, in *SyncPoliciesRequest, opts ...grpc.CallOption) (*SyncPoliciesResponse, error)
+	// ListPolicyChanges returns the recorded policy changes, newest first.
+	ListPolicyChanges(ctx context.Context, in *ListPolicyChangesRequest, opts ...grpc.CallOption) (*ListPolicyChangesResponse, error)
+	// RevertPolicies undoes every change made after a revision, as one change.
+	RevertPolicies(ctx context.Context, in *RevertPoliciesRequest, opts ...grpc.CallOption) (*RevertPoliciesResponse, error)
 }
 
 type permissionServiceClient struct {
@@ -83,6 +137,175 @@ func (c *permissionServiceClient) AddPolicyIfNotExists(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *permissionServiceClient) AssignRole(ctx context.Context, in *AssignRoleRequest, opts ...grpc.CallOption) (*AssignRoleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignRoleResponse)
+	err := c.cc.Invoke(ctx, PermissionService_AssignRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *permissionServiceClient) UnassignRole(ctx context.Context, in *UnassignRoleRequest, opts ...grpc.CallOption) (*UnassignRoleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnassignRoleResponse)
+	err := c.cc.Invoke(ctx, PermissionService_UnassignRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *permissionServiceClient) ListRoleMembers(ctx context.Context, in *ListRoleMembersRequest, opts ...grpc.CallOption) (*ListRoleMembersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRoleMembersResponse)
+	err := c.cc.Invoke(ctx, PermissionService_ListRoleMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *permissionServiceClient) BatchCheckPermission(ctx context.Context, in *BatchCheckPermissionRequest, opts ...grpc.CallOption) (*BatchCheckPermissionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchCheckPermissionResponse)
+	err := c.cc.Invoke(ctx, PermissionService_BatchCheckPermission_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *permissionServiceClient) ListPermissionsForSubject(ctx context.Context, in *ListPermissionsForSubjectRequest, opts ...grpc.CallOption) (*ListPermissionsForSubjectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPermissionsForSubjectResponse)
+	err := c.cc.Invoke(ctx, PermissionService_ListPermissionsForSubject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *permissionServiceClient) ListSubjectsForResource(ctx context.Context, in *ListSubjectsForResourceRequest, opts ...grpc.CallOption) (*ListSubjectsForResourceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSubjectsForResourceResponse)
+	err := c.cc.Invoke(ctx, PermissionService_ListSubjectsForResource_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *permissionServiceClient) GetImplicitRolesForUser(ctx context.Context, in *GetImplicitRolesForUserRequest, opts ...grpc.CallOption) (*GetImplicitRolesForUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetImplicitRolesForUserResponse)
+	err := c.cc.Invoke(ctx, PermissionService_GetImplicitRolesForUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *permissionServiceClient) ExplainPermission(ctx context.Context, in *CheckPermissionRequest, opts ...grpc.CallOption) (*ExplainPermissionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExplainPermissionResponse)
+	err := c.cc.Invoke(ctx, PermissionService_ExplainPermission_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *permissionServiceClient) GetPolicySnapshot(ctx context.Context, in *GetPolicySnapshotRequest, opts ...grpc.CallOption) (*GetPolicySnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPolicySnapshotResponse)
+	err := c.cc.Invoke(ctx, PermissionService_GetPolicySnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *permissionServiceClient) WatchPolicies(ctx context.Context, in *WatchPoliciesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PolicyEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PermissionService_ServiceDesc.Streams[0], PermissionService_WatchPolicies_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchPoliciesRequest, PolicyEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PermissionService_WatchPoliciesClient = grpc.ServerStreamingClient[PolicyEvent]
+
+func (c *permissionServiceClient) ListPolicies(ctx context.Context, in *ListPoliciesRequest, opts ...grpc.CallOption) (*ListPoliciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPoliciesResponse)
+	err := c.cc.Invoke(ctx, PermissionService_ListPolicies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *permissionServiceClient) ReplacePolicies(ctx context.Context, in *ReplacePoliciesRequest, opts ...grpc.CallOption) (*ReplacePoliciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplacePoliciesResponse)
+	err := c.cc.Invoke(ctx, PermissionService_ReplacePolicies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *permissionServiceClient) ListRoles(ctx context.Context, in *ListRolesRequest, opts ...grpc.CallOption) (*ListRolesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRolesResponse)
+	err := c.cc.Invoke(ctx, PermissionService_ListRoles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *permissionServiceClient) SyncPolicies(ctx context.Context, in *SyncPoliciesRequest, opts ...grpc.CallOption) (*SyncPoliciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncPoliciesResponse)
+	err := c.cc.Invoke(ctx, PermissionService_SyncPolicies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *permissionServiceClient) ListPolicyChanges(ctx context.Context, in *ListPolicyChangesRequest, opts ...grpc.CallOption) (*ListPolicyChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPolicyChangesResponse)
+	err := c.cc.Invoke(ctx, PermissionService_ListPolicyChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *permissionServiceClient) RevertPolicies(ctx context.Context, in *RevertPoliciesRequest, opts ...grpc.CallOption) (*RevertPoliciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevertPoliciesResponse)
+	err := c.cc.Invoke(ctx, PermissionService_RevertPolicies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PermissionServiceServer is the server API for PermissionService service.
 // All implementations must embed UnimplementedPermissionServiceServer
 // for forward compatibility.
@@ -91,6 +314,44 @@ type PermissionServiceServer interface {
 	AddPolicy(context.Context, *AddPolicyRequest) (*AddPolicyResponse, error)
 	RemovePolicy(context.Context, *RemovePolicyRequest) (*RemovePolicyResponse, error)
 	AddPolicyIfNotExists(context.Context, *AddPolicyIfNotExistsRequest) (*AddPolicyIfNotExistsResponse, error)
+	AssignRole(context.Context, *AssignRoleRequest) (*AssignRoleResponse, error)
+	UnassignRole(context.Context, *UnassignRoleRequest) (*UnassignRoleResponse, error)
+	ListRoleMembers(context.Context, *ListRoleMembersRequest) (*ListRoleMembersResponse, error)
+	// BatchCheckPermission evaluates many checks in one round trip. Decisions
+	// are returned in request order.
+	BatchCheckPermission(context.Context, *BatchCheckPermissionRequest) (*BatchCheckPermissionResponse, error)
+	// ListPermissionsForSubject lists the policies that apply to a user in a
+	// domain, directly or through roles.
+	ListPermissionsForSubject(context.Context, *ListPermissionsForSubjectRequest) (*ListPermissionsForSubjectResponse, error)
+	// ListSubjectsForResource lists the users and roles allowed to act on a
+	// resource. Conditional (ABAC) rules are not included.
+	ListSubjectsForResource(context.Context, *ListSubjectsForResourceRequest) (*ListSubjectsForResourceResponse, error)
+	GetImplicitRolesForUser(context.Context, *GetImplicitRolesForUserRequest) (*GetImplicitRolesForUserResponse, error)
+	// ExplainPermission evaluates a check like CheckPermission and reports the
+	// policy that decided it together with the subject's roles.
+	ExplainPermission(context.Context, *CheckPermissionRequest) (*ExplainPermissionResponse, error)
+	// GetPolicySnapshot returns every policy and grouping rule, for clients
+	// that enforce locally.
+	GetPolicySnapshot(context.Context, *GetPolicySnapshotRequest) (*GetPolicySnapshotResponse, error)
+	// WatchPolicies streams policy changes. A client resuming after a
+	// disconnect passes the epoch and revision of the last event it handled.
+	WatchPolicies(*WatchPoliciesRequest, grpc.ServerStreamingServer[PolicyEvent]) error
+	// ListPolicies returns the stored policies matching a filter, unlike
+	// ListPermissionsForSubject, which resolves roles and domains.
+	ListPolicies(context.Context, *ListPoliciesRequest) (*ListPoliciesResponse, error)
+	// ReplacePolicies makes the policies matching the filter exactly the given
+	// ones, in one change.
+	ReplacePolicies(context.Context, *ReplacePoliciesRequest) (*ReplacePoliciesResponse, error)
+	ListRoles(context.Context, *ListRolesRequest) (*ListRolesResponse, error)
+	// SyncPolicies adds the given rules that are missing and, with prune,
+	// removes the stored ones the request doesn't list. Pruning is limited to
+	// the domains of the given policies and the subjects of the given role
+	// links, so rules made at runtime elsewhere are kept.
+	SyncPolicies(context.Context, *SyncPoliciesRequest) (*SyncPoliciesResponse, error)
+	// ListPolicyChanges returns the recorded policy changes, newest first.
+	ListPolicyChanges(context.Context, *ListPolicyChangesRequest) (*ListPolicyChangesResponse, error)
+	// RevertPolicies undoes every change made after a revision, as one change.
+	RevertPolicies(context.Context, *RevertPoliciesRequest) (*RevertPoliciesResponse, error)
 	mustEmbedUnimplementedPermissionServiceServer()
 }
 
@@ -113,6 +374,54 @@ func (UnimplementedPermissionServiceServer) RemovePolicy(context.Context, *Remov
 func (UnimplementedPermissionServiceServer) AddPolicyIfNotExists(context.Context, *AddPolicyIfNotExistsRequest) (*AddPolicyIfNotExistsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddPolicyIfNotExists not implemented")
 }
+func (UnimplementedPermissionServiceServer) AssignRole(context.Context, *AssignRoleRequest) (*AssignRoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignRole not implemented")
+}
+func (UnimplementedPermissionServiceServer) UnassignRole(context.Context, *UnassignRoleRequest) (*UnassignRoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnassignRole not implemented")
+}
+func (UnimplementedPermissionServiceServer) ListRoleMembers(context.Context, *ListRoleMembersRequest) (*ListRoleMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRoleMembers not implemented")
+}
+func (UnimplementedPermissionServiceServer) BatchCheckPermission(context.Context, *BatchCheckPermissionRequest) (*BatchCheckPermissionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCheckPermission not implemented")
+}
+func (UnimplementedPermissionServiceServer) ListPermissionsForSubject(context.Context, *ListPermissionsForSubjectRequest) (*ListPermissionsForSubjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPermissionsForSubject not implemented")
+}
+func (UnimplementedPermissionServiceServer) ListSubjectsForResource(context.Context, *ListSubjectsForResourceRequest) (*ListSubjectsForResourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubjectsForResource not implemented")
+}
+func (UnimplementedPermissionServiceServer) GetImplicitRolesForUser(context.Context, *GetImplicitRolesForUserRequest) (*GetImplicitRolesForUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetImplicitRolesForUser not implemented")
+}
+func (UnimplementedPermissionServiceServer) ExplainPermission(context.Context, *CheckPermissionRequest) (*ExplainPermissionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExplainPermission not implemented")
+}
+func (UnimplementedPermissionServiceServer) GetPolicySnapshot(context.Context, *GetPolicySnapshotRequest) (*GetPolicySnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPolicySnapshot not implemented")
+}
+func (UnimplementedPermissionServiceServer) WatchPolicies(*WatchPoliciesRequest, grpc.ServerStreamingServer[PolicyEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchPolicies not implemented")
+}
+func (UnimplementedPermissionServiceServer) ListPolicies(context.Context, *ListPoliciesRequest) (*ListPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPolicies not implemented")
+}
+func (UnimplementedPermissionServiceServer) ReplacePolicies(context.Context, *ReplacePoliciesRequest) (*ReplacePoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplacePolicies not implemented")
+}
+func (UnimplementedPermissionServiceServer) ListRoles(context.Context, *ListRolesRequest) (*ListRolesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRoles not implemented")
+}
+func (UnimplementedPermissionServiceServer) SyncPolicies(context.Context, *SyncPoliciesRequest) (*SyncPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncPolicies not implemented")
+}
+func (UnimplementedPermissionServiceServer) ListPolicyChanges(context.Context, *ListPolicyChangesRequest) (*ListPolicyChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPolicyChanges not implemented")
+}
+func (UnimplementedPermissionServiceServer) RevertPolicies(context.Context, *RevertPoliciesRequest) (*RevertPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevertPolicies not implemented")
+}
 func (UnimplementedPermissionServiceServer) mustEmbedUnimplementedPermissionServiceServer() {}
 func (UnimplementedPermissionServiceServer) testEmbeddedByValue()                           {}
 
@@ -206,6 +515,287 @@ func _PermissionService_AddPolicyIfNotExists_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PermissionService_AssignRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).AssignRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_AssignRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).AssignRole(ctx, req.(*AssignRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PermissionService_UnassignRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnassignRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).UnassignRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_UnassignRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).UnassignRole(ctx, req.(*UnassignRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PermissionService_ListRoleMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRoleMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).ListRoleMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_ListRoleMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).ListRoleMembers(ctx, req.(*ListRoleMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PermissionService_BatchCheckPermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCheckPermissionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).BatchCheckPermission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_BatchCheckPermission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).BatchCheckPermission(ctx, req.(*BatchCheckPermissionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PermissionService_ListPermissionsForSubject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPermissionsForSubjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).ListPermissionsForSubject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_ListPermissionsForSubject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).ListPermissionsForSubject(ctx, req.(*ListPermissionsForSubjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PermissionService_ListSubjectsForResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubjectsForResourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).ListSubjectsForResource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_ListSubjectsForResource_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).ListSubjectsForResource(ctx, req.(*ListSubjectsForResourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PermissionService_GetImplicitRolesForUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetImplicitRolesForUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).GetImplicitRolesForUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_GetImplicitRolesForUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).GetImplicitRolesForUser(ctx, req.(*GetImplicitRolesForUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PermissionService_ExplainPermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPermissionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).ExplainPermission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_ExplainPermission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).ExplainPermission(ctx, req.(*CheckPermissionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PermissionService_GetPolicySnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPolicySnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).GetPolicySnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_GetPolicySnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).GetPolicySnapshot(ctx, req.(*GetPolicySnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PermissionService_WatchPolicies_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPoliciesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PermissionServiceServer).WatchPolicies(m, &grpc.GenericServerStream[WatchPoliciesRequest, PolicyEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PermissionService_WatchPoliciesServer = grpc.ServerStreamingServer[PolicyEvent]
+
+func _PermissionService_ListPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).ListPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_ListPolicies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).ListPolicies(ctx, req.(*ListPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PermissionService_ReplacePolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplacePoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).ReplacePolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_ReplacePolicies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).ReplacePolicies(ctx, req.(*ReplacePoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PermissionService_ListRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRolesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).ListRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_ListRoles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).ListRoles(ctx, req.(*ListRolesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PermissionService_SyncPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).SyncPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_SyncPolicies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).SyncPolicies(ctx, req.(*SyncPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PermissionService_ListPolicyChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPolicyChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).ListPolicyChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_ListPolicyChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).ListPolicyChanges(ctx, req.(*ListPolicyChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PermissionService_RevertPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PermissionServiceServer).RevertPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PermissionService_RevertPolicies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PermissionServiceServer).RevertPolicies(ctx, req.(*RevertPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PermissionService_ServiceDesc is the grpc.ServiceDesc for PermissionService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,7 +819,73 @@ var PermissionService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddPolicyIfNotExists",
 			Handler:    _PermissionService_AddPolicyIfNotExists_Handler,
 		},
+		{
+			MethodName: "AssignRole",
+			Handler:    _PermissionService_AssignRole_Handler,
+		},
+		{
+			MethodName: "UnassignRole",
+			Handler:    _PermissionService_UnassignRole_Handler,
+		},
+		{
+			MethodName: "ListRoleMembers",
+			Handler:    _PermissionService_ListRoleMembers_Handler,
+		},
+		{
+			MethodName: "BatchCheckPermission",
+			Handler:    _PermissionService_BatchCheckPermission_Handler,
+		},
+		{
+			MethodName: "ListPermissionsForSubject",
+			Handler:    _PermissionService_ListPermissionsForSubject_Handler,
+		},
+		{
+			MethodName: "ListSubjectsForResource",
+			Handler:    _PermissionService_ListSubjectsForResource_Handler,
+		},
+		{
+			MethodName: "GetImplicitRolesForUser",
+			Handler:    _PermissionService_GetImplicitRolesForUser_Handler,
+		},
+		{
+			MethodName: "ExplainPermission",
+			Handler:    _PermissionService_ExplainPermission_Handler,
+		},
+		{
+			MethodName: "GetPolicySnapshot",
+			Handler:    _PermissionService_GetPolicySnapshot_Handler,
+		},
+		{
+			MethodName: "ListPolicies",
+			Handler:    _PermissionService_ListPolicies_Handler,
+		},
+		{
+			MethodName: "ReplacePolicies",
+			Handler:    _PermissionService_ReplacePolicies_Handler,
+		},
+		{
+			MethodName: "ListRoles",
+			Handler:    _PermissionService_ListRoles_Handler,
+		},
+		{
+			MethodName: "SyncPolicies",
+			Handler:    _PermissionService_SyncPolicies_Handler,
+		},
+		{
+			MethodName: "ListPolicyChanges",
+			Handler:    _PermissionService_ListPolicyChanges_Handler,
+		},
+		{
+			MethodName: "RevertPolicies",
+			Handler:    _PermissionService_RevertPolicies_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchPolicies",
+			Handler:       _PermissionService_WatchPolicies_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/permission/permission_service.proto",
 }
@@ -0,0 +1,181 @@
+package database
+
+import (
+	"log"
+
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+// MigrateLegacyPolicies moves the flat ACL rows written for every user onto
+// role assignments: each user still holding per-user "users read" or
+// "stream" rows gets a grouping rule for its User.Role, those rows, now
+// granted by the member role, are dropped, and so are the "*" rows that never
+// matched anybody. Only rows in the legacy "sub, obj, act" layout are looked
+// at, so once they are gone the migration changes nothing; in particular a
+// user left without roles doesn't get one back on the next start.
+// The adapter must be created before the call so the casbin_rule table exists.
+func MigrateLegacyPolicies(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		legacy := func() *gorm.DB {
+			return tx.Model(&gormadapter.CasbinRule{}).Where("ptype = ? AND v3 = ?", "p", "")
+		}
+		perUser := func(q *gorm.DB) *gorm.DB {
+			return q.Where("((v1 = ? AND v2 = ?) OR v1 = ?)", "users", "read", "stream")
+		}
+
+		var ids []string
+		if err := perUser(legacy()).Distinct("v0").Pluck("v0", &ids).Error; err != nil {
+			return err
+		}
+
+		var rules []gormadapter.CasbinRule
+		var migrated int64
+		if len(ids) > 0 {
+			var users []models.User
+			if err := tx.Select("id", "role").Where("id IN ?", ids).Find(&users).Error; err != nil {
+				return err
+			}
+
+			var grouped []string
+			if err := tx.Model(&gormadapter.CasbinRule{}).Where("ptype = ? AND v0 IN ?", "g", ids).Pluck("v0", &grouped).Error; err != nil {
+				return err
+			}
+			hasRole := make(map[string]struct{}, len(grouped))
+			for _, sub := range grouped {
+				hasRole[sub] = struct{}{}
+			}
+
+			for _, user := range users {
+				id := user.ID.String()
+				if _, ok := hasRole[id]; ok {
+					continue
+				}
+				role := user.Role
+				if role == "" {
+					role = models.RoleMember
+				}
+				rules = append(rules, gormadapter.CasbinRule{Ptype: "g", V0: id, V1: role, V2: models.GlobalDomain})
+			}
+			if len(rules) > 0 {
+				if err := tx.CreateInBatches(rules, 500).Error; err != nil {
+					return err
+				}
+			}
+
+			result := perUser(legacy()).Where("v0 IN ?", ids).Delete(&gormadapter.CasbinRule{})
+			if result.Error != nil {
+				return result.Error
+			}
+			migrated = result.RowsAffected
+		}
+
+		result := legacy().Where("v0 = ?", "*").Delete(&gormadapter.CasbinRule{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		log.Printf("🔄 Casbin RBAC migration: %d role assignments added, %d per-user rows and %d wildcard rows removed",
+			len(rules), migrated, result.RowsAffected)
+		return nil
+	})
+}
+
+// MigrateDomainPolicies rewrites rows stored for the "sub, obj, act" model into
+// the domain-aware layout: policies become (sub, *, obj, act) and role
+// assignments (user, role, *), so everything granted before organizations
+// existed stays platform-wide. Rows already carrying a domain are untouched.
+func MigrateDomainPolicies(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		policies := tx.Model(&gormadapter.CasbinRule{}).
+			Where("ptype = ? AND v3 = ?", "p", "").
+			Updates(map[string]any{
+				"v1": models.GlobalDomain,
+				"v2": gorm.Expr("v1"),
+				"v3": gorm.Expr("v2"),
+			})
+		if policies.Error != nil {
+			return policies.Error
+		}
+
+		groupings := tx.Model(&gormadapter.CasbinRule{}).
+			Where("ptype = ? AND v2 = ?", "g", "").
+			Update("v2", models.GlobalDomain)
+		if groupings.Error != nil {
+			return groupings.Error
+		}
+
+		log.Printf("🔄 Casbin domain migration: %d policies and %d role assignments moved to the global domain",
+			policies.RowsAffected, groupings.RowsAffected)
+		return nil
+	})
+}
+
+// OwnerPolicies are the pattern rules that replace the users/<id> rows
+// previously written for every user on signup.
+var OwnerPolicies = [][]string{
+	{models.SelfSubject, models.GlobalDomain, "users/:id", "read"},
+	{models.SelfSubject, models.GlobalDomain, "users/:id", "write"},
+	{models.SelfSubject, models.GlobalDomain, "users/:id", "delete"},
+}
+
+// MigrateOwnerPolicies drops the per-user users/<id> rows that the "self"
+// owner policies cover and makes sure those policies exist, so every user
+// keeps exactly the access they had. Expects the domain-aware layout.
+func MigrateOwnerPolicies(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var rows []gormadapter.CasbinRule
+		if err := tx.Where("ptype = ? AND v2 LIKE ?", "p", "users/%").Find(&rows).Error; err != nil {
+			return err
+		}
+
+		var ids []uint
+		for _, row := range rows {
+			if isOwnerPolicy([]string{row.V0, row.V1, row.V2, row.V3}) {
+				ids = append(ids, row.ID)
+			}
+		}
+		if len(ids) > 0 {
+			if err := tx.Delete(&gormadapter.CasbinRule{}, ids).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, p := range OwnerPolicies {
+			rule := gormadapter.CasbinRule{Ptype: "p", V0: p[0], V1: p[1], V2: p[2], V3: p[3]}
+			if err := tx.Where(&rule).FirstOrCreate(&rule).Error; err != nil {
+				return err
+			}
+		}
+
+		log.Printf("🔄 Casbin owner migration: %d per-user rows collapsed into %d owner policies",
+			len(ids), len(OwnerPolicies))
+		return nil
+	})
+}
+
+// CollapseOwnerPolicies splits policies (sub, dom, obj, act) into the ones
+// still needed and the per-user rows that OwnerPolicies make redundant.
+func CollapseOwnerPolicies(policies [][]string) (kept, collapsed [][]string) {
+	for _, p := range policies {
+		if isOwnerPolicy(p) {
+			collapsed = append(collapsed, p)
+		} else {
+			kept = append(kept, p)
+		}
+	}
+	return kept, collapsed
+}
+
+func isOwnerPolicy(p []string) bool {
+	if len(p) < 4 || p[0] == "" || p[1] != models.GlobalDomain || p[2] != "users/"+p[0] {
+		return false
+	}
+	for _, owner := range OwnerPolicies {
+		if owner[3] == p[3] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/config"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"github.com/mrhumster/web-server-gin/tests/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEnforcer(t *testing.T, policies [][]string) *casbin.Enforcer {
+	t.Helper()
+	e, err := casbin.NewEnforcer(filepath.Join(config.GetRootDir(), "config", "model.conf"))
+	require.NoError(t, err)
+	for _, p := range policies {
+		_, err := e.AddPolicy(p[0], p[1], p[2], p[3])
+		require.NoError(t, err)
+	}
+	_, err = e.AddRoleForUserInDomain(models.RoleAdmin, models.RoleMember, models.GlobalDomain)
+	require.NoError(t, err)
+	_, err = e.AddRoleForUserInDomain("u3", models.RoleAdmin, models.GlobalDomain)
+	require.NoError(t, err)
+	return e
+}
+
+func TestCollapseOwnerPolicies(t *testing.T) {
+	users := []string{"u1", "u2", "u3"}
+	shared := [][]string{
+		{models.RoleMember, models.GlobalDomain, "users", "read"},
+		{models.RoleAdmin, models.GlobalDomain, "users/*", "delete"},
+		{"u2", models.GlobalDomain, "users/u1", "read"},
+		{"u1", "org1", "users/u1", "read"},
+	}
+	legacy := append([][]string{}, shared...)
+	for _, u := range users {
+		for _, act := range []string{"read", "write", "delete"} {
+			legacy = append(legacy, []string{u, models.GlobalDomain, "users/" + u, act})
+		}
+	}
+
+	kept, collapsed := CollapseOwnerPolicies(legacy)
+	assert.Len(t, collapsed, len(users)*3)
+	assert.ElementsMatch(t, shared, kept)
+
+	before := newTestEnforcer(t, legacy)
+	after := newTestEnforcer(t, append(kept, OwnerPolicies...))
+
+	subjects := append(users, "stranger")
+	for _, sub := range subjects {
+		for _, dom := range []string{models.GlobalDomain, "org1"} {
+			for _, obj := range []string{"users", "users/u1", "users/u2", "users/u3", "users/u1/sessions"} {
+				for _, act := range []string{"read", "write", "delete"} {
+					want, err := before.Enforce(sub, dom, obj, act)
+					require.NoError(t, err)
+					got, err := after.Enforce(sub, dom, obj, act)
+					require.NoError(t, err)
+					assert.Equal(t, want, got, fmt.Sprintf("%s %s %s %s", sub, dom, obj, act))
+				}
+			}
+		}
+	}
+}
+
+func TestMigrateLegacyPolicies_RunsOnce(t *testing.T) {
+	db := testutils.SetupTestDB()
+	defer testutils.CleanTestDatabase()
+	_, err := gormadapter.NewAdapterByDB(db)
+	require.NoError(t, err)
+
+	legacy := models.User{Email: "legacy@test.local", PasswordHash: "#", Role: models.RoleMember}
+	legacy.ID = uuid.New()
+	// Had its roles unassigned after the migration; must stay without one.
+	revoked := models.User{Email: "revoked@test.local", PasswordHash: "#", Role: models.RoleAdmin}
+	revoked.ID = uuid.New()
+	require.NoError(t, db.Create(&[]models.User{legacy, revoked}).Error)
+	require.NoError(t, db.Create(&[]gormadapter.CasbinRule{
+		{Ptype: "p", V0: legacy.ID.String(), V1: "users", V2: "read"},
+		{Ptype: "p", V0: legacy.ID.String(), V1: "stream", V2: "write"},
+		{Ptype: "p", V0: "*", V1: "users", V2: "read"},
+		{Ptype: "p", V0: "*", V1: models.GlobalDomain, V2: "health", V3: "read"},
+	}).Error)
+
+	for range 2 {
+		require.NoError(t, MigrateLegacyPolicies(db))
+	}
+
+	var rules []gormadapter.CasbinRule
+	require.NoError(t, db.Order("ptype").Find(&rules).Error)
+	var got [][]string
+	for _, r := range rules {
+		got = append(got, []string{r.Ptype, r.V0, r.V1, r.V2, r.V3})
+	}
+	assert.ElementsMatch(t, [][]string{
+		{"g", legacy.ID.String(), models.RoleMember, models.GlobalDomain, ""},
+		{"p", "*", models.GlobalDomain, "health", "read"},
+	}, got)
+}
@@ -28,6 +28,6 @@ func SetupDatabase(cfg *config.Config) *gorm.DB {
 	sqlDb.SetConnMaxIdleTime(30 * time.Minute)
 	log.Printf("🔌  Creating uuid-ossp extension...")
 	db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"")
-	db.AutoMigrate(&models.User{})
+	db.AutoMigrate(&models.User{}, &models.Organization{}, &models.Membership{}, &models.PolicyChange{}, &models.PolicyOperation{})
 	return db
 }
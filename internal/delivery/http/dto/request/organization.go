@@ -0,0 +1,21 @@
+package request
+
+type OrganizationRequest struct {
+	Name string `json:"name" binding:"required,min=2,max=100"`
+	Slug string `json:"slug" binding:"required,min=2,max=64"`
+}
+
+type OrgMemberRequest struct {
+	UserID string `json:"user_id" binding:"required,uuid"`
+	Role   string `json:"role" binding:"required,oneof=org_admin org_member"`
+}
+
+type OrgMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=org_admin org_member"`
+}
+
+// SwitchOrganizationRequest selects the organization carried by the next
+// access token. An empty OrgID switches back to the platform scope.
+type SwitchOrganizationRequest struct {
+	OrgID string `json:"org_id" binding:"omitempty,uuid"`
+}
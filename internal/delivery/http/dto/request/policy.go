@@ -0,0 +1,74 @@
+package request
+
+import (
+	"time"
+
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+)
+
+type PolicyRequest struct {
+	Subject   string `json:"subject" form:"subject" binding:"required"`
+	Domain    string `json:"domain" form:"domain"`
+	Resource  string `json:"resource" form:"resource" binding:"required"`
+	Action    string `json:"action" form:"action" binding:"required"`
+	Condition string `json:"condition,omitempty" form:"condition"`
+}
+
+// PolicyFilterRequest is read from the query string. Empty fields match any
+// value.
+type PolicyFilterRequest struct {
+	Subject  string `form:"subject"`
+	Domain   string `form:"domain"`
+	Resource string `form:"resource"`
+	Action   string `form:"action"`
+}
+
+// PolicyHistoryRequest filters the policy change history. Since and Until
+// are RFC 3339 times.
+type PolicyHistoryRequest struct {
+	Actor   string    `form:"actor"`
+	Service string    `form:"service"`
+	Subject string    `form:"subject"`
+	Domain  string    `form:"domain"`
+	Since   time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until   time.Time `form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type RevertPoliciesRequest struct {
+	Revision uint64 `json:"revision" binding:"required"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+type ReplacePoliciesRequest struct {
+	Policies []PolicyRequest `json:"policies" binding:"dive"`
+}
+
+func (r PolicyRequest) ToModel() auth.Permission {
+	return auth.Permission{
+		Subject:   r.Subject,
+		Domain:    r.Domain,
+		Resource:  r.Resource,
+		Action:    r.Action,
+		Condition: r.Condition,
+	}
+}
+
+func (r PolicyFilterRequest) ToModel() auth.PolicyFilter {
+	return auth.PolicyFilter{
+		Subject:  r.Subject,
+		Domain:   r.Domain,
+		Resource: r.Resource,
+		Action:   r.Action,
+	}
+}
+
+func (r PolicyHistoryRequest) ToModel() auth.PolicyChangeFilter {
+	return auth.PolicyChangeFilter{
+		Actor:   r.Actor,
+		Service: r.Service,
+		Subject: r.Subject,
+		Domain:  r.Domain,
+		Since:   r.Since,
+		Until:   r.Until,
+	}
+}
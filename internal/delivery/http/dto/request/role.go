@@ -0,0 +1,5 @@
+package request
+
+type RoleMemberRequest struct {
+	UserID string `json:"user_id" binding:"required,uuid"`
+}
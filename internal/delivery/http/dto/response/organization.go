@@ -0,0 +1,43 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+)
+
+type OrganizationResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OrganizationsListResponse struct {
+	Organizations []OrganizationResponse `json:"organizations"`
+}
+
+type OrgMemberResponse struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OrgMembersResponse struct {
+	OrganizationID uuid.UUID           `json:"organization_id"`
+	Members        []OrgMemberResponse `json:"members"`
+}
+
+func (o *OrganizationResponse) FillInTheModel(m *models.Organization) {
+	o.ID = m.ID
+	o.Name = m.Name
+	o.Slug = m.Slug
+	o.CreatedAt = m.CreatedAt
+}
+
+func (o *OrgMemberResponse) FillInTheModel(m *models.Membership) {
+	o.UserID = m.UserID
+	o.Role = m.Role
+	o.CreatedAt = m.CreatedAt
+}
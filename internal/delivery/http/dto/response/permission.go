@@ -0,0 +1,72 @@
+package response
+
+import (
+	"time"
+
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+)
+
+type PermissionResponse struct {
+	Subject   string `json:"subject"`
+	Domain    string `json:"domain"`
+	Resource  string `json:"resource"`
+	Action    string `json:"action"`
+	Condition string `json:"condition,omitempty"`
+}
+
+type PermissionsResponse struct {
+	Roles         []string             `json:"roles"`
+	Permissions   []PermissionResponse `json:"permissions"`
+	NextPageToken string               `json:"next_page_token,omitempty"`
+}
+
+func (p *PermissionResponse) FillInTheModel(m auth.Permission) {
+	p.Subject = m.Subject
+	p.Domain = m.Domain
+	p.Resource = m.Resource
+	p.Action = m.Action
+	p.Condition = m.Condition
+}
+
+type PoliciesResponse struct {
+	Policies      []PermissionResponse `json:"policies"`
+	NextPageToken string               `json:"next_page_token,omitempty"`
+}
+
+type ReplacePoliciesResponse struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+}
+
+// PolicyChangeResponse is a recorded policy change. Rules start with their
+// type: p, p2 or g.
+type PolicyChangeResponse struct {
+	Revision  uint64    `json:"revision"`
+	ChangedAt time.Time `json:"changed_at"`
+	Service   string    `json:"service"`
+	Actor     string    `json:"actor,omitempty"`
+	Type      string    `json:"type"`
+	Before    []string  `json:"before,omitempty"`
+	After     []string  `json:"after,omitempty"`
+}
+
+func (p *PolicyChangeResponse) FillInTheModel(m auth.PolicyChange) {
+	p.Revision = m.Revision
+	p.ChangedAt = m.ChangedAt
+	p.Service = m.Service
+	p.Actor = m.Actor
+	p.Type = m.Type
+	p.Before = m.Before
+	p.After = m.After
+}
+
+type PolicyChangesResponse struct {
+	Changes       []PolicyChangeResponse `json:"changes"`
+	NextPageToken string                 `json:"next_page_token,omitempty"`
+}
+
+// PolicyPlanResponse lists the rules a change adds and removes.
+type PolicyPlanResponse struct {
+	Added   [][]string `json:"added"`
+	Removed [][]string `json:"removed"`
+}
@@ -0,0 +1,16 @@
+package response
+
+type RoleMembersResponse struct {
+	Role    string   `json:"role"`
+	Members []string `json:"members"`
+}
+
+type RolesResponse struct {
+	Roles []string `json:"roles"`
+}
+
+type RoleResponse struct {
+	Role     string               `json:"role"`
+	Policies []PermissionResponse `json:"policies"`
+	Members  []string             `json:"members"`
+}
@@ -14,11 +14,14 @@ type UserResponse struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// UsersListReponse is a page of the users the caller may read. Pages are
+// taken from all users before filtering, so one may hold fewer than Limit
+// users, even none, and HasMore tells whether a later page exists.
 type UsersListReponse struct {
-	Users []UserResponse `json:"users"`
-	Total int64          `json:"total"`
-	Page  int64          `json:"page"`
-	Limit int64          `json:"limit"`
+	Users   []UserResponse `json:"users"`
+	HasMore bool           `json:"has_more"`
+	Page    int64          `json:"page"`
+	Limit   int64          `json:"limit"`
 }
 
 func (u *UserResponse) FillInTheModel(m *models.User) {
@@ -0,0 +1,158 @@
+// Package gateway serves gRPC methods as JSON over HTTP, transcoding requests
+// by the rules of config/gateway.yaml and the message descriptors of the
+// generated protos, the way grpc-gateway does.
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	// The services served over the gateway, and the error details their
+	// statuses carry, must be registered to be found by name.
+	_ "github.com/mrhumster/web-server-gin/gen/go/auth"
+	_ "github.com/mrhumster/web-server-gin/gen/go/permission"
+	_ "github.com/mrhumster/web-server-gin/gen/go/user"
+	_ "google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// Gateway calls the methods bound by its rules over a gRPC connection.
+type Gateway struct {
+	conn     grpc.ClientConnInterface
+	bindings []*binding
+}
+
+// binding is a rule resolved against the method it selects.
+type binding struct {
+	rule       Rule
+	httpMethod string
+	// path is the rule's template, e.g. /v1/users/{id}; ginPath the same
+	// route as Gin matches it.
+	path    string
+	ginPath string
+	params  []pathParam
+	// idParam is the Gin parameter of the rule's Authorize.ID.
+	idParam string
+	method  protoreflect.MethodDescriptor
+	input   protoreflect.MessageType
+	output  protoreflect.MessageType
+}
+
+// pathParam fills field from the Gin parameter name.
+type pathParam struct {
+	name  string
+	field protoreflect.FieldDescriptor
+}
+
+// fullMethod is the method's name on the wire, e.g.
+// /user.UserService/GetUser.
+func (b *binding) fullMethod() string {
+	return fmt.Sprintf("/%s/%s", b.method.Parent().FullName(), b.method.Name())
+}
+
+// New resolves rules against the registered protos. Client-streaming methods
+// can't be bound. conn has to pass the acting user of the request context on
+// as x-actor, as connections from auth.Dial do, so the gRPC side checks the
+// user rather than the server's own identity.
+func New(conn grpc.ClientConnInterface, rules []Rule) (*Gateway, error) {
+	g := &Gateway{conn: conn}
+	for _, r := range rules {
+		b, err := resolve(r)
+		if err != nil {
+			return nil, err
+		}
+		g.bindings = append(g.bindings, b)
+	}
+	return g, nil
+}
+
+func resolve(r Rule) (*binding, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(r.Selector))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidRule, r.Selector, err)
+	}
+	method, ok := d.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a method", ErrInvalidRule, r.Selector)
+	}
+	if method.IsStreamingClient() {
+		return nil, fmt.Errorf("%w: %s streams requests", ErrInvalidRule, r.Selector)
+	}
+	b := &binding{rule: r, method: method}
+	if b.input, err = protoregistry.GlobalTypes.FindMessageByName(method.Input().FullName()); err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidRule, r.Selector, err)
+	}
+	if b.output, err = protoregistry.GlobalTypes.FindMessageByName(method.Output().FullName()); err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidRule, r.Selector, err)
+	}
+	b.httpMethod, b.path = r.Route()
+	if err := b.parsePath(); err != nil {
+		return nil, err
+	}
+	if p := r.Authorize; p != nil && p.ID != "" && b.idParam == "" {
+		return nil, fmt.Errorf("%w: %s authorize id %q is not a field of the path", ErrInvalidRule, r.Selector, p.ID)
+	}
+	return b, nil
+}
+
+// parsePath turns the {field} segments of the template into Gin parameters.
+// They are named by position, so templates naming different fields at the
+// same place don't conflict in Gin's router.
+func (b *binding) parsePath() error {
+	segments := strings.Split(b.path, "/")
+	for i, s := range segments {
+		name, ok := strings.CutPrefix(s, "{")
+		if !ok {
+			if strings.ContainsAny(s, "{}:*") {
+				return fmt.Errorf("%w: %s path segment %q", ErrInvalidRule, b.rule.Selector, s)
+			}
+			continue
+		}
+		name, ok = strings.CutSuffix(name, "}")
+		if !ok {
+			return fmt.Errorf("%w: %s path segment %q", ErrInvalidRule, b.rule.Selector, s)
+		}
+		field := b.input.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if field == nil || field.IsList() || field.IsMap() || field.Kind() == protoreflect.MessageKind {
+			return fmt.Errorf("%w: %s path field %q is not a scalar field of %s",
+				ErrInvalidRule, b.rule.Selector, name, b.input.Descriptor().FullName())
+		}
+		param := fmt.Sprintf("p%d", i)
+		b.params = append(b.params, pathParam{name: param, field: field})
+		if p := b.rule.Authorize; p != nil && p.ID == name {
+			b.idParam = param
+		}
+		segments[i] = ":" + param
+	}
+	b.ginPath = strings.Join(segments, "/")
+	return nil
+}
+
+// Register adds a route per rule to r. Routes that are not public go through
+// authenticate, which must make the request context carry the acting user;
+// rules with a permission then go through authorize, given the Gin parameter
+// holding the resource ID, or "" when the check is on obj itself.
+func (g *Gateway) Register(r gin.IRoutes, authenticate gin.HandlerFunc, authorize func(obj, act, idParam string) gin.HandlerFunc) {
+	for _, b := range g.bindings {
+		var handlers []gin.HandlerFunc
+		if !b.rule.Public {
+			handlers = append(handlers, authenticate)
+		}
+		if p := b.rule.Authorize; p != nil {
+			handlers = append(handlers, authorize(p.Resource, p.Action, b.idParam))
+		}
+		if b.method.IsStreamingServer() {
+			handlers = append(handlers, g.serverStream(b))
+		} else {
+			handlers = append(handlers, g.unary(b))
+		}
+		r.Handle(b.httpMethod, b.ginPath, handlers...)
+	}
+}
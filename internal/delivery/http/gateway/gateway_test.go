@@ -0,0 +1,384 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	stringadapter "github.com/casbin/casbin/v2/persist/string-adapter"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/config"
+	authpb "github.com/mrhumster/web-server-gin/gen/go/auth"
+	"github.com/mrhumster/web-server-gin/gen/go/permission"
+	"github.com/mrhumster/web-server-gin/gen/go/user"
+	"github.com/mrhumster/web-server-gin/internal/delivery/http/openapi"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+	"github.com/mrhumster/web-server-gin/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+type fakeUsers struct {
+	user.UnimplementedUserServiceServer
+	actor string
+}
+
+func (s *fakeUsers) GetUser(ctx context.Context, req *user.GetUserRequest) (*user.User, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(auth.ActorMetadataKey)) > 0 {
+		s.actor = md.Get(auth.ActorMetadataKey)[0]
+	}
+	if req.GetId() == "missing" || req.GetEmail() == "missing@example.com" {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return &user.User{Id: req.GetId(), Email: req.GetEmail(), Role: "member"}, nil
+}
+
+func (s *fakeUsers) CreateUser(_ context.Context, req *user.CreateUserRequest) (*user.User, error) {
+	return &user.User{Id: "new", Email: req.GetEmail(), Role: req.GetRole()}, nil
+}
+
+type fakePermissions struct {
+	permission.UnimplementedPermissionServiceServer
+	listed  *permission.ListPoliciesRequest
+	watcher string
+}
+
+func (s *fakePermissions) ListRoleMembers(_ context.Context, req *permission.ListRoleMembersRequest) (*permission.ListRoleMembersResponse, error) {
+	return &permission.ListRoleMembersResponse{UserIds: []string{alice.String()}}, nil
+}
+
+func (s *fakePermissions) ListPolicies(_ context.Context, req *permission.ListPoliciesRequest) (*permission.ListPoliciesResponse, error) {
+	s.listed = req
+	return &permission.ListPoliciesResponse{}, nil
+}
+
+func (s *fakePermissions) WatchPolicies(_ *permission.WatchPoliciesRequest, stream permission.PermissionService_WatchPoliciesServer) error {
+	if md, ok := metadata.FromIncomingContext(stream.Context()); ok && len(md.Get(auth.ActorMetadataKey)) > 0 {
+		s.watcher = md.Get(auth.ActorMetadataKey)[0]
+	}
+	for i := range uint64(2) {
+		if err := stream.Send(&permission.PolicyEvent{Epoch: "e", Revision: i + 1, Type: "add"}); err != nil {
+			return err
+		}
+	}
+	return status.Error(codes.Unavailable, "shutting down")
+}
+
+// alice is an admin by the baseline policies, bob a member.
+var (
+	alice = uuid.MustParse("6f1c3a52-9d2e-4c7b-8a41-2b5e0d9f7c13")
+	bob   = uuid.MustParse("0b7d4e29-5a18-4f63-9c2a-e1d8b6f3a475")
+)
+
+// newEnforcer checks against config/policies.yaml in process.
+func newEnforcer(t *testing.T) auth.PermissionClient {
+	t.Helper()
+	rules, err := auth.ParsePolicies(config.BaselinePolicies, auth.PolicyFormatYAML)
+	require.NoError(t, err)
+	rules = append(rules,
+		[]string{"g", alice.String(), models.RoleAdmin, auth.GlobalDomain},
+		[]string{"g", bob.String(), models.RoleMember, auth.GlobalDomain})
+	lines := make([]string, len(rules))
+	for i, rule := range rules {
+		lines[i] = strings.Join(rule, ", ")
+	}
+	client, err := auth.NewLocalPermissionClient(auth.LocalOptions{Adapter: stringadapter.NewAdapter(strings.Join(lines, "\n"))})
+	require.NoError(t, err)
+	return client
+}
+
+func startGateway(t *testing.T, rules []Rule, users *fakeUsers, permissions *fakePermissions) *gin.Engine {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	user.RegisterUserServiceServer(server, users)
+	permission.RegisterPermissionServiceServer(server, permissions)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := auth.Dial(lis.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	gw, err := New(conn, rules)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	// The bearer token is the user's ID.
+	authenticate := func(c *gin.Context) {
+		id, err := uuid.Parse(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+		if err != nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Set("user", id)
+		c.Request = c.Request.WithContext(auth.WithActor(c.Request.Context(), id.String()))
+	}
+	enforcer := newEnforcer(t)
+	gw.Register(r, authenticate, func(obj, act, idParam string) gin.HandlerFunc {
+		return middleware.Authorize(enforcer, obj, act, middleware.WithResourceParam(idParam))
+	})
+	return r
+}
+
+func serve(r http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	return serveAs(r, alice, method, target, body)
+}
+
+func serveAs(r http.Handler, id uuid.UUID, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+id.String())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+var testRules = []Rule{
+	{Selector: "user.UserService.GetUser", Get: "/v1/users/{id}"},
+	{Selector: "user.UserService.GetUser", Get: "/v1/users/lookup"},
+	{Selector: "user.UserService.CreateUser", Post: "/v1/users", Body: "*"},
+	{Selector: "permission.PermissionService.ListPolicies", Get: "/v1/policies",
+		Authorize: &Permission{Resource: "policies", Action: "read"}},
+	{Selector: "permission.PermissionService.WatchPolicies", Get: "/v1/policies/watch"},
+	{Selector: "permission.PermissionService.ListRoleMembers", Get: "/v1/roles/{role}/members",
+		Authorize: &Permission{Resource: "roles", Action: "read", ID: "role"}},
+	{Selector: "permission.PermissionService.GetPolicySnapshot", Get: "/v1/policies/snapshot", Public: true},
+}
+
+func TestGateway_Unary(t *testing.T) {
+	users := &fakeUsers{}
+	r := startGateway(t, testRules, users, &fakePermissions{})
+
+	w := serve(r, http.MethodGet, "/v1/users/42", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "42", got["id"])
+	assert.Equal(t, false, got["disabled"], "unset fields are written")
+	assert.Contains(t, got, "created_at", "fields keep their proto names")
+	assert.Equal(t, alice.String(), users.actor, "the acting user is passed on")
+
+	w = serve(r, http.MethodGet, "/v1/users/lookup?email=bob@example.com", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"email":"bob@example.com"`)
+
+	w = serve(r, http.MethodPost, "/v1/users", `{"email":"carol@example.com","role":"admin"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"role":"admin"`)
+}
+
+func TestGateway_Errors(t *testing.T) {
+	r := startGateway(t, testRules, &fakeUsers{}, &fakePermissions{})
+
+	w := serve(r, http.MethodGet, "/v1/users/missing", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var st struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &st))
+	assert.Equal(t, int(codes.NotFound), st.Code)
+	assert.Equal(t, "user not found", st.Message)
+
+	w = serve(r, http.MethodPost, "/v1/users", `{"email":`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(r, http.MethodGet, "/v1/users/lookup?nope=1", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `unknown query parameter \"nope\"`)
+
+	w = serve(r, http.MethodGet, "/v1/policies/snapshot", "")
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
+func TestGateway_Middleware(t *testing.T) {
+	permissions := &fakePermissions{}
+	r := startGateway(t, testRules, &fakeUsers{}, permissions)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/policies", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Nil(t, permissions.listed)
+
+	// Public routes skip authentication.
+	req = httptest.NewRequest(http.MethodGet, "/v1/policies/snapshot", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+
+	w = serveAs(r, bob, http.MethodGet, "/v1/policies", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Nil(t, permissions.listed)
+
+	w = serve(r, http.MethodGet, "/v1/policies?filter.subject=alice&page_size=10", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotNil(t, permissions.listed)
+	assert.Equal(t, "alice", permissions.listed.GetFilter().GetSubject())
+	assert.Equal(t, int32(10), permissions.listed.GetPageSize())
+
+	// The role is the ID of the resource, checked against roles/*.
+	w = serve(r, http.MethodGet, "/v1/roles/admin/members", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), alice.String())
+	w = serveAs(r, bob, http.MethodGet, "/v1/roles/admin/members", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestGateway_ServerStream(t *testing.T) {
+	permissions := &fakePermissions{}
+	r := startGateway(t, testRules, &fakeUsers{}, permissions)
+
+	w := serve(r, http.MethodGet, "/v1/policies/watch", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, alice.String(), permissions.watcher, "the acting user is passed on")
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	var lines []map[string]json.RawMessage
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var line map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 3)
+	assert.JSONEq(t, `{"epoch":"e","revision":"1","type":"add","rules":[]}`, string(lines[0]["result"]))
+	assert.Contains(t, lines[1], "result")
+	assert.Contains(t, string(lines[2]["error"]), "shutting down")
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]byte(`
+rules:
+  - selector: user.UserService.GetUser
+    get: /v1/users/{id}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{{Selector: "user.UserService.GetUser", Get: "/v1/users/{id}"}}, rules)
+
+	for name, data := range map[string]string{
+		"unknown key":     "rules:\n  - selector: a.B.C\n    get: /x\n    verb: x\n",
+		"no route":        "rules:\n  - selector: a.B.C\n",
+		"two routes":      "rules:\n  - selector: a.B.C\n    get: /x\n    post: /x\n",
+		"relative path":   "rules:\n  - selector: a.B.C\n    get: x\n",
+		"body field":      "rules:\n  - selector: a.B.C\n    post: /x\n    body: name\n",
+		"get with body":   "rules:\n  - selector: a.B.C\n    get: /x\n    body: \"*\"\n",
+		"public and auth": "rules:\n  - selector: a.B.C\n    get: /x\n    public: true\n    authorize: {resource: r, action: a}\n",
+	} {
+		_, err := ParseRules([]byte(data))
+		assert.ErrorIs(t, err, ErrInvalidRule, name)
+	}
+}
+
+func TestNew_InvalidRules(t *testing.T) {
+	for name, r := range map[string]Rule{
+		"unknown method":    {Selector: "user.UserService.Nope", Get: "/x"},
+		"not a method":      {Selector: "user.User", Get: "/x"},
+		"unknown field":     {Selector: "user.UserService.GetUser", Get: "/v1/users/{nope}"},
+		"repeated field":    {Selector: "user.UserService.BatchGetUsers", Get: "/v1/users/{ids}"},
+		"broken segment":    {Selector: "user.UserService.GetUser", Get: "/v1/users/{id"},
+		"wildcard template": {Selector: "user.UserService.GetUser", Get: "/v1/users/*"},
+		"authorize id": {Selector: "user.UserService.GetUser", Get: "/v1/users/{id}",
+			Authorize: &Permission{Resource: "users", Action: "read", ID: "user_id"}},
+	} {
+		_, err := New(nil, []Rule{r})
+		assert.ErrorIs(t, err, ErrInvalidRule, name)
+	}
+}
+
+// Every method of the served services must have a rule, so none is left off
+// the gateway by accident.
+func TestGatewayRules(t *testing.T) {
+	rules, err := ParseRules(config.GatewayRules)
+	require.NoError(t, err)
+	gw, err := New(nil, rules)
+	require.NoError(t, err)
+
+	bound := make(map[protoreflect.FullName]bool)
+	for _, b := range gw.bindings {
+		bound[b.method.FullName()] = true
+	}
+	for _, name := range []string{
+		authpb.AuthService_ServiceDesc.ServiceName,
+		permission.PermissionService_ServiceDesc.ServiceName,
+		user.UserService_ServiceDesc.ServiceName,
+	} {
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+		require.NoError(t, err)
+		methods := d.(protoreflect.ServiceDescriptor).Methods()
+		for i := 0; i < methods.Len(); i++ {
+			assert.True(t, bound[methods.Get(i).FullName()], "%s has no gateway rule", methods.Get(i).FullName())
+		}
+	}
+
+	// Gin panics on conflicting routes.
+	assert.NotPanics(t, func() {
+		gw.Register(gin.New(), func(*gin.Context) {}, func(string, string, string) gin.HandlerFunc { return func(*gin.Context) {} })
+	})
+}
+
+func TestGateway_OpenAPI(t *testing.T) {
+	rules, err := ParseRules(config.GatewayRules)
+	require.NoError(t, err)
+	gw, err := New(nil, rules)
+	require.NoError(t, err)
+
+	doc := &openapi.Document{OpenAPI: openapi.Version}
+	gw.OpenAPI(doc)
+	for _, b := range gw.bindings {
+		require.Contains(t, doc.Paths, b.path)
+		assert.Contains(t, *doc.Paths[b.path], strings.ToLower(b.httpMethod), b.path)
+	}
+	assert.Equal(t, "UserService_GetUser_2", (*doc.Paths["/v1/users/lookup"])["get"].OperationID)
+	assert.Nil(t, (*doc.Paths["/v1/jwks"])["get"].Security, "public routes need no token")
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	var refs []string
+	collectRefs(t, data, &refs)
+	require.NotEmpty(t, refs)
+	for _, ref := range refs {
+		name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+		if assert.True(t, ok, ref) {
+			assert.Contains(t, doc.Components.Schemas, name)
+		}
+	}
+}
+
+func collectRefs(t *testing.T, data []byte, refs *[]string) {
+	t.Helper()
+	var v any
+	require.NoError(t, json.Unmarshal(data, &v))
+	var walk func(any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, e := range v {
+				if s, ok := e.(string); ok && k == "$ref" {
+					*refs = append(*refs, s)
+				}
+				walk(e)
+			}
+		case []any:
+			for _, e := range v {
+				walk(e)
+			}
+		}
+	}
+	walk(v)
+}
@@ -0,0 +1,180 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mrhumster/web-server-gin/internal/delivery/http/openapi"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// statusSchema is the schema name of the errors written by writeError.
+const statusSchema = "google.rpc.Status"
+
+// OpenAPI describes the routes of g in doc: a path per rule and a schema per
+// message they use. Paths are the rule templates, which OpenAPI writes the
+// same way.
+func (g *Gateway) OpenAPI(doc *openapi.Document) {
+	if doc.Paths == nil {
+		doc.Paths = make(map[string]*openapi.PathItem)
+	}
+	if doc.Components.Schemas == nil {
+		doc.Components.Schemas = make(map[string]*openapi.Schema)
+	}
+	if doc.Components.SecuritySchemes == nil {
+		doc.Components.SecuritySchemes = make(map[string]*openapi.SecurityScheme)
+	}
+	doc.Components.SecuritySchemes[openapi.BearerAuth] = &openapi.SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT"}
+	doc.Components.Schemas[statusSchema] = &openapi.Schema{
+		Type: openapi.Type{"object"},
+		Properties: map[string]*openapi.Schema{
+			"code":    {Type: openapi.Type{"integer"}, Format: "int32"},
+			"message": {Type: openapi.Type{"string"}},
+			"details": {Type: openapi.Type{"array"}, Items: &openapi.Schema{Type: openapi.Type{"object"}}},
+		},
+	}
+
+	ids := make(map[string]int)
+	for _, b := range g.bindings {
+		item := doc.Paths[b.path]
+		if item == nil {
+			item = &openapi.PathItem{}
+			doc.Paths[b.path] = item
+		}
+		id := fmt.Sprintf("%s_%s", b.method.Parent().Name(), b.method.Name())
+		if ids[id]++; ids[id] > 1 {
+			id = fmt.Sprintf("%s_%d", id, ids[id])
+		}
+		(*item)[strings.ToLower(b.httpMethod)] = b.operation(id, doc.Components.Schemas)
+	}
+}
+
+func (b *binding) operation(id string, schemas map[string]*openapi.Schema) *openapi.Operation {
+	op := &openapi.Operation{
+		OperationID: id,
+		Summary:     b.rule.Selector,
+		Tags:        []string{string(b.method.Parent().FullName())},
+		Responses: map[string]*openapi.Response{
+			"default": {Description: "Error", Content: openapi.JSONContent(openapi.Ref(statusSchema))},
+		},
+	}
+	if !b.rule.Public {
+		op.Security = []openapi.SecurityRequirement{{openapi.BearerAuth: {}}}
+	}
+	inPath := make(map[protoreflect.Name]bool)
+	for _, p := range b.params {
+		inPath[p.field.Name()] = true
+		op.Parameters = append(op.Parameters, &openapi.Parameter{
+			Name:     string(p.field.Name()),
+			In:       "path",
+			Required: true,
+			Schema:   fieldSchema(p.field, schemas),
+		})
+	}
+	input := b.input.Descriptor()
+	if b.rule.Body == "*" {
+		op.RequestBody = &openapi.RequestBody{Required: true, Content: openapi.JSONContent(messageSchema(input, schemas))}
+	} else {
+		op.Parameters = append(op.Parameters, queryParameters("", input, inPath, schemas)...)
+	}
+	output := messageSchema(b.method.Output(), schemas)
+	if b.method.IsStreamingServer() {
+		op.Responses["200"] = &openapi.Response{
+			Description: "A stream of JSON lines, each holding a result or, last, an error",
+			Content: map[string]*openapi.MediaType{"application/x-ndjson": {Schema: &openapi.Schema{
+				Type: openapi.Type{"object"},
+				Properties: map[string]*openapi.Schema{
+					"result": output,
+					"error":  openapi.Ref(statusSchema),
+				},
+			}}},
+		}
+	} else {
+		op.Responses["200"] = &openapi.Response{Description: "OK", Content: openapi.JSONContent(output)}
+	}
+	return op
+}
+
+// queryParameters lists the fields of md that can be set from the query
+// string, nested ones by their dotted path.
+func queryParameters(prefix string, md protoreflect.MessageDescriptor, skip map[protoreflect.Name]bool, schemas map[string]*openapi.Schema) []*openapi.Parameter {
+	var params []*openapi.Parameter
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if skip[fd.Name()] || fd.IsMap() {
+			continue
+		}
+		name := prefix + string(fd.Name())
+		if fd.Kind() == protoreflect.MessageKind {
+			if !fd.IsList() {
+				params = append(params, queryParameters(name+".", fd.Message(), nil, schemas)...)
+			}
+			continue
+		}
+		params = append(params, &openapi.Parameter{Name: name, In: "query", Schema: fieldSchema(fd, schemas)})
+	}
+	return params
+}
+
+// messageSchema returns a reference to the schema of md, adding it and the
+// messages it uses to schemas.
+func messageSchema(md protoreflect.MessageDescriptor, schemas map[string]*openapi.Schema) *openapi.Schema {
+	name := string(md.FullName())
+	if _, ok := schemas[name]; ok {
+		return openapi.Ref(name)
+	}
+	s := &openapi.Schema{Type: openapi.Type{"object"}, Properties: make(map[string]*openapi.Schema)}
+	// Added before its fields, so recursive messages end.
+	schemas[name] = s
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		s.Properties[string(fd.Name())] = fieldSchema(fd, schemas)
+	}
+	return openapi.Ref(name)
+}
+
+func fieldSchema(fd protoreflect.FieldDescriptor, schemas map[string]*openapi.Schema) *openapi.Schema {
+	if fd.IsMap() {
+		return &openapi.Schema{Type: openapi.Type{"object"}, AdditionalProperties: singularSchema(fd.MapValue(), schemas)}
+	}
+	if fd.IsList() {
+		return &openapi.Schema{Type: openapi.Type{"array"}, Items: singularSchema(fd, schemas)}
+	}
+	return singularSchema(fd, schemas)
+}
+
+// singularSchema is the schema of one value of fd as protojson writes it.
+func singularSchema(fd protoreflect.FieldDescriptor, schemas map[string]*openapi.Schema) *openapi.Schema {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return &openapi.Schema{Type: openapi.Type{"boolean"}}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &openapi.Schema{Type: openapi.Type{"integer"}, Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &openapi.Schema{Type: openapi.Type{"integer"}, Format: "uint32"}
+	// protojson writes 64-bit integers as strings and reads either.
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return &openapi.Schema{Type: openapi.Type{"string", "integer"}, Format: "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &openapi.Schema{Type: openapi.Type{"string", "integer"}, Format: "uint64"}
+	case protoreflect.FloatKind:
+		return &openapi.Schema{Type: openapi.Type{"number"}, Format: "float"}
+	case protoreflect.DoubleKind:
+		return &openapi.Schema{Type: openapi.Type{"number"}, Format: "double"}
+	case protoreflect.BytesKind:
+		return &openapi.Schema{Type: openapi.Type{"string"}, Format: "byte"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		return &openapi.Schema{Type: openapi.Type{"string"}, Enum: names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageSchema(fd.Message(), schemas)
+	default:
+		return &openapi.Schema{Type: openapi.Type{"string"}}
+	}
+}
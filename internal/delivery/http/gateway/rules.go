@@ -0,0 +1,103 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// ErrInvalidRule means a gateway rule can't be served.
+var ErrInvalidRule = errors.New("invalid gateway rule")
+
+// Rule binds a gRPC method to an HTTP route, like google.api.HttpRule. Exactly
+// one of Get, Post, Put, Patch and Delete holds the path template.
+type Rule struct {
+	// Selector is the full name of the method, e.g.
+	// permission.PermissionService.CheckPermission.
+	Selector string `yaml:"selector"`
+	Get      string `yaml:"get,omitempty"`
+	Post     string `yaml:"post,omitempty"`
+	Put      string `yaml:"put,omitempty"`
+	Patch    string `yaml:"patch,omitempty"`
+	Delete   string `yaml:"delete,omitempty"`
+	// Body is "*" when the request message is read from the JSON body, empty
+	// when it is read from the query string.
+	Body string `yaml:"body,omitempty"`
+	// Authorize is checked for the acting user before the call.
+	Authorize *Permission `yaml:"authorize,omitempty"`
+	// Public routes need no access token.
+	Public bool `yaml:"public,omitempty"`
+}
+
+// Permission is an action on a Casbin object.
+type Permission struct {
+	Resource string `yaml:"resource"`
+	Action   string `yaml:"action"`
+	// ID names a path field whose value is appended to Resource, e.g. role
+	// for roles/{role}. Without it the check is on Resource itself.
+	ID string `yaml:"id,omitempty"`
+}
+
+// ParseRules reads a rules file: a list of rules under "rules".
+func ParseRules(data []byte) ([]Rule, error) {
+	var f struct {
+		Rules []Rule `yaml:"rules"`
+	}
+	if err := yaml.UnmarshalWithOptions(data, &f, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRule, err)
+	}
+	for i, r := range f.Rules {
+		if err := r.validate(); err != nil {
+			return nil, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+	}
+	return f.Rules, nil
+}
+
+// Route returns the HTTP method and path template of the rule.
+func (r Rule) Route() (method, path string) {
+	for _, b := range []struct{ method, path string }{
+		{http.MethodGet, r.Get},
+		{http.MethodPost, r.Post},
+		{http.MethodPut, r.Put},
+		{http.MethodPatch, r.Patch},
+		{http.MethodDelete, r.Delete},
+	} {
+		if b.path != "" {
+			if method != "" {
+				return "", ""
+			}
+			method, path = b.method, b.path
+		}
+	}
+	return method, path
+}
+
+func (r Rule) validate() error {
+	if r.Selector == "" {
+		return fmt.Errorf("%w: selector must be set", ErrInvalidRule)
+	}
+	method, path := r.Route()
+	if method == "" {
+		return fmt.Errorf("%w: %s needs exactly one of get, post, put, patch and delete", ErrInvalidRule, r.Selector)
+	}
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("%w: %s path %q must start with /", ErrInvalidRule, r.Selector, path)
+	}
+	if r.Body != "" && r.Body != "*" {
+		return fmt.Errorf("%w: %s body must be empty or \"*\"", ErrInvalidRule, r.Selector)
+	}
+	if r.Body == "*" && (method == http.MethodGet || method == http.MethodDelete) {
+		return fmt.Errorf("%w: %s %s can't have a body", ErrInvalidRule, r.Selector, method)
+	}
+	if r.Authorize != nil && (r.Authorize.Resource == "" || r.Authorize.Action == "") {
+		return fmt.Errorf("%w: %s authorize needs resource and action", ErrInvalidRule, r.Selector)
+	}
+	if r.Authorize != nil && r.Public {
+		return fmt.Errorf("%w: %s can't be public and authorized", ErrInvalidRule, r.Selector)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Messages are written with their proto field names, like the JSON of the
+// REST routes, and with every field, so clients see false and empty values.
+var (
+	marshal   = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+	unmarshal = protojson.UnmarshalOptions{}
+)
+
+func (g *Gateway) unary(b *binding) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, err := b.request(c)
+		if err != nil {
+			writeError(c, err)
+			return
+		}
+		resp := b.output.New().Interface()
+		if err := g.conn.Invoke(c.Request.Context(), b.fullMethod(), req, resp); err != nil {
+			writeError(c, err)
+			return
+		}
+		data, err := marshal.Marshal(resp)
+		if err != nil {
+			writeError(c, status.Errorf(codes.Internal, "encode response: %v", err))
+			return
+		}
+		c.Data(http.StatusOK, "application/json", data)
+	}
+}
+
+// serverStream writes each message of the stream as a line of JSON,
+// {"result": ...}, flushing as they come. An error after the first message
+// ends the stream with an {"error": ...} line.
+func (g *Gateway) serverStream(b *binding) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, err := b.request(c)
+		if err != nil {
+			writeError(c, err)
+			return
+		}
+		desc := &grpc.StreamDesc{StreamName: string(b.method.Name()), ServerStreams: true}
+		stream, err := g.conn.NewStream(c.Request.Context(), desc, b.fullMethod())
+		if err != nil {
+			writeError(c, err)
+			return
+		}
+		if err := stream.SendMsg(req); err != nil {
+			writeError(c, err)
+			return
+		}
+		if err := stream.CloseSend(); err != nil {
+			writeError(c, err)
+			return
+		}
+		started := false
+		for {
+			msg := b.output.New().Interface()
+			err := stream.RecvMsg(msg)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				if !started {
+					writeError(c, err)
+					return
+				}
+				data, _ := marshal.Marshal(status.Convert(err).Proto())
+				writeLine(c, "error", data)
+				return
+			}
+			data, err := marshal.Marshal(msg)
+			if err != nil {
+				writeError(c, status.Errorf(codes.Internal, "encode response: %v", err))
+				return
+			}
+			if !started {
+				c.Header("Content-Type", "application/x-ndjson")
+				c.Status(http.StatusOK)
+				started = true
+			}
+			writeLine(c, "result", data)
+		}
+	}
+}
+
+func writeLine(c *gin.Context, key string, data []byte) {
+	fmt.Fprintf(c.Writer, "{%q:%s}\n", key, data)
+	c.Writer.Flush()
+}
+
+// writeError writes err as a google.rpc.Status in JSON, with the HTTP status
+// of its code.
+func writeError(c *gin.Context, err error) {
+	st := status.Convert(err)
+	data, mErr := marshal.Marshal(st.Proto())
+	if mErr != nil {
+		data, _ = json.Marshal(map[string]any{"code": st.Code(), "message": st.Message()})
+	}
+	c.Data(HTTPStatus(st.Code()), "application/json", data)
+	c.Abort()
+}
+
+// HTTPStatus maps a gRPC code onto the HTTP status grpc-gateway uses for it.
+func HTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// request builds the request message from the body or query string, then
+// sets the fields named in the path.
+func (b *binding) request(c *gin.Context) (proto.Message, error) {
+	msg := b.input.New().Interface()
+	if b.rule.Body == "*" {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "read body: %v", err)
+		}
+		if len(bytes.TrimSpace(data)) > 0 {
+			if err := unmarshal.Unmarshal(data, msg); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "body: %v", err)
+			}
+		}
+	}
+
+	fields := make(map[string]any)
+	if b.rule.Body == "" {
+		for key, values := range c.Request.URL.Query() {
+			if err := setQueryField(fields, b.input.Descriptor(), key, values); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+	}
+	for _, p := range b.params {
+		fields[string(p.field.Name())] = c.Param(p.name)
+	}
+	if len(fields) == 0 {
+		return msg, nil
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "parameters: %v", err)
+	}
+	params := b.input.New().Interface()
+	if err := unmarshal.Unmarshal(data, params); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "parameters: %v", err)
+	}
+	proto.Merge(msg, params)
+	return msg, nil
+}
+
+// setQueryField puts the query parameter key, a field name or a dotted path
+// into message fields, into fields as protojson reads it.
+func setQueryField(fields map[string]any, md protoreflect.MessageDescriptor, key string, values []string) error {
+	name, rest, nested := strings.Cut(key, ".")
+	fd := md.Fields().ByName(protoreflect.Name(name))
+	if fd == nil {
+		fd = md.Fields().ByJSONName(name)
+	}
+	if fd == nil {
+		return fmt.Errorf("unknown query parameter %q", key)
+	}
+	if fd.IsMap() || (fd.IsList() && fd.Kind() == protoreflect.MessageKind) {
+		return fmt.Errorf("query parameter %q can't set %s", key, fd.FullName())
+	}
+	if nested {
+		if fd.Kind() != protoreflect.MessageKind {
+			return fmt.Errorf("unknown query parameter %q", key)
+		}
+		sub, _ := fields[string(fd.Name())].(map[string]any)
+		if sub == nil {
+			sub = make(map[string]any)
+			fields[string(fd.Name())] = sub
+		}
+		return setQueryField(sub, fd.Message(), rest, values)
+	}
+	if fd.Kind() == protoreflect.MessageKind {
+		return fmt.Errorf("query parameter %q names a message", key)
+	}
+	if fd.IsList() {
+		list := make([]any, len(values))
+		for i, v := range values {
+			value, err := queryValue(fd, v)
+			if err != nil {
+				return fmt.Errorf("query parameter %q: %w", key, err)
+			}
+			list[i] = value
+		}
+		fields[string(fd.Name())] = list
+		return nil
+	}
+	value, err := queryValue(fd, values[len(values)-1])
+	if err != nil {
+		return fmt.Errorf("query parameter %q: %w", key, err)
+	}
+	fields[string(fd.Name())] = value
+	return nil
+}
+
+// queryValue converts v for protojson, which reads numbers and enum names
+// from strings but booleans only as such.
+func queryValue(fd protoreflect.FieldDescriptor, v string) (any, error) {
+	if fd.Kind() == protoreflect.BoolKind {
+		return strconv.ParseBool(v)
+	}
+	return v, nil
+}
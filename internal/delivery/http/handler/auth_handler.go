@@ -3,7 +3,6 @@ package handler
 import (
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,18 +13,20 @@ import (
 )
 
 type AuthHandler struct {
-	UserService  *service.UserService
-	TokenService *service.TokenService
-	JwtSecret    string
-	Domain       string
+	UserService         *service.UserService
+	TokenService        *service.TokenService
+	OrganizationService *service.OrganizationService
+	JwtSecret           string
+	Domain              string
 }
 
-func NewAuthHandler(userService *service.UserService, tokenService *service.TokenService, jwtSecret, domain string) *AuthHandler {
+func NewAuthHandler(userService *service.UserService, tokenService *service.TokenService, orgService *service.OrganizationService, jwtSecret, domain string) *AuthHandler {
 	return &AuthHandler{
-		UserService:  userService,
-		TokenService: tokenService,
-		JwtSecret:    jwtSecret,
-		Domain:       domain,
+		UserService:         userService,
+		TokenService:        tokenService,
+		OrganizationService: orgService,
+		JwtSecret:           jwtSecret,
+		Domain:              domain,
 	}
 }
 
@@ -97,7 +98,12 @@ func (a *AuthHandler) Refresh(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse("token revoke"))
 		return
 	}
-	tokenPair, err := a.TokenService.GenerateToken(u)
+	// Keep the active organization only while the user is still a member of it.
+	orgID := claims.OrgID
+	if orgID != "" && !a.isMember(c, orgID, userID) {
+		orgID = ""
+	}
+	tokenPair, err := a.TokenService.GenerateTokenForOrganization(u, orgID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse("failed to generate token"))
 		return
@@ -122,6 +128,55 @@ func (a *AuthHandler) Refresh(c *gin.Context) {
 	})
 }
 
+// SwitchOrganization reissues the token pair scoped to the requested
+// organization, which middleware.Authorize then uses as the Casbin domain.
+func (a *AuthHandler) SwitchOrganization(c *gin.Context) {
+	var req request.SwitchOrganizationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	userUUID := c.MustGet("user").(uuid.UUID)
+	if req.OrgID != "" && !a.isMember(c, req.OrgID, userUUID) {
+		c.AbortWithStatusJSON(http.StatusForbidden, response.ErrorResponse("not a member of the organization"))
+		return
+	}
+	u, err := a.UserService.ReadUser(c, userUUID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse("user not found"))
+		return
+	}
+	tokenPair, err := a.TokenService.GenerateTokenForOrganization(u, req.OrgID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.ErrorResponse("failed to generate token"))
+		return
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(
+		"refresh_token",
+		tokenPair.RefreshToken,
+		int(a.TokenService.GetRefreshExpiry().Seconds()),
+		"/",
+		a.Domain,
+		true,
+		true,
+	)
+
+	c.JSON(http.StatusOK, response.LoginResponse{
+		AccessToken: tokenPair.AccessToken,
+		ExpiresIn:   tokenPair.ExpiresIn,
+		TokenType:   tokenPair.TokenType,
+	})
+}
+
+func (a *AuthHandler) isMember(c *gin.Context, orgID string, userID uuid.UUID) bool {
+	id, err := uuid.Parse(orgID)
+	if err != nil {
+		return false
+	}
+	_, err = a.OrganizationService.ReadMembership(c, id, userID)
+	return err == nil
+}
+
 func (a *AuthHandler) Logout(c *gin.Context) {
 	c.SetCookie("refresh_token", "", -1, "/", a.Domain, true, true)
 	c.JSON(http.StatusOK, response.SuccessResponse("Logged out successfully"))
@@ -129,16 +184,12 @@ func (a *AuthHandler) Logout(c *gin.Context) {
 
 func (a *AuthHandler) LogoutAll(c *gin.Context) {
 	userUUID := c.MustGet("user").(uuid.UUID)
-	if err := a.UserService.UpdateTokenVersion(c, &userUUID, generateNewTokenVersion()); err != nil {
+	if err := a.UserService.UpdateTokenVersion(c, &userUUID, service.NewTokenVersion()); err != nil {
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse("failed to logout"))
 		return
 	}
 
 	c.SetCookie("refresh_token", "", -1, "/", a.Domain, true, true)
 
-	c.JSON(http.StatusOK, response.ErrorResponse("logged out from all devices"))
-}
-
-func generateNewTokenVersion() string {
-	return "v" + time.Now().Format("20060102150405")
+	c.JSON(http.StatusOK, response.SuccessResponse("logged out from all devices"))
 }
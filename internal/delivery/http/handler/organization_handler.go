@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/internal/delivery/http/dto/request"
+	"github.com/mrhumster/web-server-gin/internal/delivery/http/dto/response"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"github.com/mrhumster/web-server-gin/internal/service"
+)
+
+type OrganizationHandler struct {
+	service *service.OrganizationService
+}
+
+func NewOrganizationHandler(service *service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{service: service}
+}
+
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var req request.OrganizationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	userUUID := c.MustGet("user").(uuid.UUID)
+	org, err := h.service.CreateOrganization(c, models.Organization{Name: req.Name, Slug: req.Slug}, userUUID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	var resp response.OrganizationResponse
+	resp.FillInTheModel(org)
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	userUUID := c.MustGet("user").(uuid.UUID)
+	orgs, err := h.service.ListOrganizations(c, userUUID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	resp := response.OrganizationsListResponse{Organizations: make([]response.OrganizationResponse, len(orgs))}
+	for i := range orgs {
+		resp.Organizations[i].FillInTheModel(&orgs[i])
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *OrganizationHandler) ReadOrganization(c *gin.Context) {
+	orgID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	org, err := h.service.ReadOrganization(c, orgID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	var resp response.OrganizationResponse
+	resp.FillInTheModel(org)
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	orgID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	members, err := h.service.ListMembers(c, orgID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	resp := response.OrgMembersResponse{
+		OrganizationID: orgID,
+		Members:        make([]response.OrgMemberResponse, len(members)),
+	}
+	for i := range members {
+		resp.Members[i].FillInTheModel(&members[i])
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	orgID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var req request.OrgMemberRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	if err := h.service.AddMember(c, orgID, userID, req.Role); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"organization_id": orgID, "user_id": userID, "role": req.Role})
+}
+
+func (h *OrganizationHandler) UpdateMember(c *gin.Context) {
+	orgID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := parseUUIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+	var req request.OrgMemberRoleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if err := h.service.UpdateMemberRole(c, orgID, userID, req.Role); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"organization_id": orgID, "user_id": userID, "role": req.Role})
+}
+
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	orgID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := parseUUIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+	if err := h.service.RemoveMember(c, orgID, userID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *OrganizationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidOrgRole):
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(err.Error()))
+	case errors.Is(err, service.ErrOrganizationNotFound),
+		errors.Is(err, service.ErrMembershipNotFound),
+		errors.Is(err, service.ErrUserNotFount):
+		c.JSON(http.StatusNotFound, response.ErrorResponse(err.Error()))
+	case errors.Is(err, service.ErrOrganizationExists),
+		errors.Is(err, service.ErrMembershipAlreadyExists),
+		errors.Is(err, service.ErrLastOrganizationAdmin):
+		c.JSON(http.StatusConflict, response.ErrorResponse(err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse(err.Error()))
+	}
+}
+
+func bindJSON(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		bindError(c, err)
+		return false
+	}
+	return true
+}
+
+func bindQuery(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindQuery(obj); err != nil {
+		bindError(c, err)
+		return false
+	}
+	return true
+}
+
+func bindError(c *gin.Context, err error) {
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		errors := make(map[string]string)
+		for _, fieldError := range validationErrors {
+			errors[fieldError.Field()] = getErrorMessage(fieldError)
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+func parseUUIDParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return uuid.Nil, false
+	}
+	return id, true
+}
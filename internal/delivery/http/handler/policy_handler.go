@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrhumster/web-server-gin/internal/delivery/http/dto/request"
+	"github.com/mrhumster/web-server-gin/internal/delivery/http/dto/response"
+	"github.com/mrhumster/web-server-gin/internal/service"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+)
+
+type PolicyHandler struct {
+	service *service.PolicyService
+}
+
+func NewPolicyHandler(service *service.PolicyService) *PolicyHandler {
+	return &PolicyHandler{service: service}
+}
+
+func (h *PolicyHandler) ListPolicies(c *gin.Context) {
+	var filter request.PolicyFilterRequest
+	if !bindQuery(c, &filter) {
+		return
+	}
+	var pageSize int64
+	if sizeStr := c.Query("page_size"); sizeStr != "" {
+		var err error
+		if pageSize, err = strconv.ParseInt(sizeStr, 10, 32); err != nil || pageSize < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "page_size is incorrect"})
+			return
+		}
+	}
+	policies, next, err := h.service.List(c, filter.ToModel(), int32(pageSize), c.Query("page_token"))
+	if err != nil {
+		handlePolicyError(c, err)
+		return
+	}
+	resp := response.PoliciesResponse{
+		Policies:      make([]response.PermissionResponse, len(policies)),
+		NextPageToken: next,
+	}
+	for i, p := range policies {
+		resp.Policies[i].FillInTheModel(p)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *PolicyHandler) CreatePolicy(c *gin.Context) {
+	var req request.PolicyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	policy, err := h.service.Create(c, req.ToModel())
+	if err != nil {
+		handlePolicyError(c, err)
+		return
+	}
+	var resp response.PermissionResponse
+	resp.FillInTheModel(policy)
+	c.JSON(http.StatusCreated, resp)
+}
+
+// DeletePolicy removes the policy given in the query string, since policies
+// have no id of their own.
+func (h *PolicyHandler) DeletePolicy(c *gin.Context) {
+	var req request.PolicyRequest
+	if !bindQuery(c, &req) {
+		return
+	}
+	if err := h.service.Delete(c, req.ToModel()); err != nil {
+		handlePolicyError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ReplacePolicies makes the request body the only policies matching the
+// filter in the query string; without a filter it replaces every policy.
+func (h *PolicyHandler) ReplacePolicies(c *gin.Context) {
+	var filter request.PolicyFilterRequest
+	if !bindQuery(c, &filter) {
+		return
+	}
+	var req request.ReplacePoliciesRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	policies := make([]auth.Permission, len(req.Policies))
+	for i, p := range req.Policies {
+		policies[i] = p.ToModel()
+	}
+	added, removed, err := h.service.Replace(c, filter.ToModel(), policies)
+	if err != nil {
+		handlePolicyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.ReplacePoliciesResponse{Added: added, Removed: removed})
+}
+
+// ListPolicyChanges pages through the recorded policy changes, newest first.
+func (h *PolicyHandler) ListPolicyChanges(c *gin.Context) {
+	var filter request.PolicyHistoryRequest
+	if !bindQuery(c, &filter) {
+		return
+	}
+	var pageSize int64
+	if sizeStr := c.Query("page_size"); sizeStr != "" {
+		var err error
+		if pageSize, err = strconv.ParseInt(sizeStr, 10, 32); err != nil || pageSize < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "page_size is incorrect"})
+			return
+		}
+	}
+	changes, next, err := h.service.History(c, filter.ToModel(), int32(pageSize), c.Query("page_token"))
+	if err != nil {
+		handlePolicyError(c, err)
+		return
+	}
+	resp := response.PolicyChangesResponse{
+		Changes:       make([]response.PolicyChangeResponse, len(changes)),
+		NextPageToken: next,
+	}
+	for i, ch := range changes {
+		resp.Changes[i].FillInTheModel(ch)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevertPolicies puts the policies back the way they were at a revision of
+// the history. With dry_run it only reports what would change.
+func (h *PolicyHandler) RevertPolicies(c *gin.Context) {
+	var req request.RevertPoliciesRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	plan, err := h.service.Revert(c, req.Revision, req.DryRun)
+	if err != nil {
+		handlePolicyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.PolicyPlanResponse{Added: plan.Added, Removed: plan.Removed})
+}
+
+// handlePolicyError maps policy service and permission client errors. The
+// permission service rejects invalid conditions and policies outside the
+// filter as invalid requests.
+func handlePolicyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrPolicyIncomplete), errors.Is(err, service.ErrRoleEmpty):
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(err.Error()))
+	case errors.Is(err, auth.ErrInvalidRequest):
+		var authErr *auth.Error
+		if errors.As(err, &authErr) && len(authErr.Violations) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": authErr.Violations})
+			return
+		}
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(err.Error()))
+	case errors.Is(err, service.ErrPolicyNotFound):
+		c.JSON(http.StatusNotFound, response.ErrorResponse(err.Error()))
+	case errors.Is(err, service.ErrPolicyExists):
+		c.JSON(http.StatusConflict, response.ErrorResponse(err.Error()))
+	case auth.IsUnavailable(err):
+		c.JSON(http.StatusServiceUnavailable, response.ErrorResponse("Authorization service unavailable"))
+	default:
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse(err.Error()))
+	}
+}
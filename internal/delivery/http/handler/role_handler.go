@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/internal/delivery/http/dto/request"
+	"github.com/mrhumster/web-server-gin/internal/delivery/http/dto/response"
+	"github.com/mrhumster/web-server-gin/internal/service"
+)
+
+type RoleHandler struct {
+	service *service.RoleService
+}
+
+func NewRoleHandler(service *service.RoleService) *RoleHandler {
+	return &RoleHandler{service: service}
+}
+
+func (h *RoleHandler) ListMembers(c *gin.Context) {
+	role := c.Param("id")
+	members, err := h.service.ListMembers(c, role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse(err.Error()))
+		return
+	}
+	if members == nil {
+		members = []string{}
+	}
+	c.JSON(http.StatusOK, response.RoleMembersResponse{
+		Role:    role,
+		Members: members,
+	})
+}
+
+func (h *RoleHandler) AssignMember(c *gin.Context) {
+	var req request.RoleMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			errors := make(map[string]string)
+			for _, fieldError := range validationErrors {
+				errors[fieldError.Field()] = getErrorMessage(fieldError)
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	role := c.Param("id")
+	if _, err := h.service.AssignRole(c, role, userID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"role": role, "user_id": userID})
+}
+
+func (h *RoleHandler) UnassignMember(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	role := c.Param("id")
+	if _, err := h.service.UnassignRole(c, role, userID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RoleHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrRoleEmpty), errors.Is(err, service.ErrRoleReserved):
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(err.Error()))
+	case errors.Is(err, service.ErrUserNotFount):
+		c.JSON(http.StatusNotFound, response.ErrorResponse(err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse(err.Error()))
+	}
+}
+
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.service.ListRoles(c, c.Query("domain"))
+	if err != nil {
+		handlePolicyError(c, err)
+		return
+	}
+	if roles == nil {
+		roles = []string{}
+	}
+	c.JSON(http.StatusOK, response.RolesResponse{Roles: roles})
+}
+
+func (h *RoleHandler) ReadRole(c *gin.Context) {
+	role, err := h.service.ReadRole(c, c.Param("id"))
+	if err != nil {
+		handlePolicyError(c, err)
+		return
+	}
+	resp := response.RoleResponse{
+		Role:     role.Role,
+		Policies: make([]response.PermissionResponse, len(role.Policies)),
+		Members:  role.Members,
+	}
+	for i, p := range role.Policies {
+		resp.Policies[i].FillInTheModel(p)
+	}
+	if resp.Members == nil {
+		resp.Members = []string{}
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/mrhumster/web-server-gin/internal/delivery/http/dto/response"
 	"github.com/mrhumster/web-server-gin/internal/domain/models"
 	"github.com/mrhumster/web-server-gin/internal/service"
+	"github.com/mrhumster/web-server-gin/pkg/middleware"
 )
 
 type UserHandler struct {
@@ -124,6 +125,10 @@ func (h *UserHandler) Delete(c *gin.Context) {
 	c.JSON(http.StatusNoContent, id)
 }
 
+// maxUsersPerPage caps the limit of ReadUsers, which checks every user of a
+// page in one batch.
+const maxUsersPerPage = 100
+
 func (h *UserHandler) ReadUsers(c *gin.Context) {
 	page := int64(1)
 	limit := int64(10)
@@ -147,10 +152,17 @@ func (h *UserHandler) ReadUsers(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "limit is incorrect"})
 		return
 	}
+	limit = min(limit, maxUsersPerPage)
 
 	users, total, err := h.service.ReadUserList(c, limit, page)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err.Error())
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(err.Error()))
+		return
+	}
+	userUUID := c.MustGet("user").(uuid.UUID)
+	users, err = h.service.FilterReadable(c, userUUID, middleware.Tenant(c), users)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse("failed to check permissions"))
 		return
 	}
 	var usersReponse []response.UserResponse
@@ -160,10 +172,10 @@ func (h *UserHandler) ReadUsers(c *gin.Context) {
 		usersReponse = append(usersReponse, u)
 	}
 	c.JSON(http.StatusOK, response.UsersListReponse{
-		Users: usersReponse,
-		Total: total,
-		Page:  page,
-		Limit: limit,
+		Users:   usersReponse,
+		HasMore: page*limit < total,
+		Page:    page,
+		Limit:   limit,
 	})
 }
 
@@ -171,10 +183,40 @@ func (h *UserHandler) GetAuthUser(c *gin.Context) {
 	userUUID := c.MustGet("user").(uuid.UUID)
 	user, err := h.service.ReadUser(c, userUUID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.ErrorResponse(err.Error()))
 		return
 	}
 	var resp response.UserResponse
 	resp.FillInTheModel(user)
 	c.JSON(http.StatusOK, resp)
 }
+
+func (h *UserHandler) GetAuthPermissions(c *gin.Context) {
+	userUUID := c.MustGet("user").(uuid.UUID)
+	var pageSize int64
+	if sizeStr := c.Query("page_size"); sizeStr != "" {
+		var err error
+		if pageSize, err = strconv.ParseInt(sizeStr, 10, 32); err != nil || pageSize < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "page_size is incorrect"})
+			return
+		}
+	}
+
+	roles, perms, next, err := h.service.ListPermissions(c, userUUID, middleware.Tenant(c), int32(pageSize), c.Query("page_token"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse(err.Error()))
+		return
+	}
+	resp := response.PermissionsResponse{
+		Roles:         roles,
+		Permissions:   make([]response.PermissionResponse, len(perms)),
+		NextPageToken: next,
+	}
+	if resp.Roles == nil {
+		resp.Roles = []string{}
+	}
+	for i, p := range perms {
+		resp.Permissions[i].FillInTheModel(p)
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -2,6 +2,7 @@ package handler_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -14,6 +15,8 @@ import (
 	"github.com/mrhumster/web-server-gin/internal/delivery/http/dto/request"
 	"github.com/mrhumster/web-server-gin/internal/delivery/http/dto/response"
 	"github.com/mrhumster/web-server-gin/internal/delivery/http/routes"
+	"github.com/mrhumster/web-server-gin/internal/repository"
+	"github.com/mrhumster/web-server-gin/internal/service"
 	"github.com/mrhumster/web-server-gin/pkg/auth"
 	"github.com/mrhumster/web-server-gin/tests/testutils"
 	"github.com/stretchr/testify/assert"
@@ -31,7 +34,9 @@ func setupTest() (*gin.Engine, *gorm.DB) {
 	if err != nil {
 		panic("⚠️ SetupTest gRPC client error")
 	}
-	router := routes.SetupRoutes(db, "test", permGRPCClient)
+	outbox := service.NewPolicyOutbox(repository.NewGormPolicyOutboxRepository(db), permGRPCClient)
+	go outbox.Run(context.Background())
+	router := routes.SetupRoutes(db, "test", permGRPCClient, routes.WithPolicyOutbox(outbox))
 	return router, db
 }
 
@@ -220,11 +225,11 @@ func TestUserHandler_ReadUsers(t *testing.T) {
 	router.ServeHTTP(resp, req)
 	var respMap map[string]any
 	json.Unmarshal(resp.Body.Bytes(), &respMap)
-	assert.Contains(t, respMap, "total")
+	assert.Contains(t, respMap, "has_more")
 	assert.Contains(t, respMap, "page")
 	assert.Contains(t, respMap, "limit")
 	assert.Contains(t, respMap, "users")
-	assert.Equal(t, float64(total), respMap["total"])
+	assert.Equal(t, true, respMap["has_more"])
 	assert.Equal(t, page, respMap["page"])
 	users, ok := respMap["users"].([]interface{})
 	assert.True(t, ok, "users should be an array")
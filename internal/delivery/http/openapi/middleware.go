@@ -0,0 +1,322 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Find returns the path and operation of d that describe the Gin route
+// method route, e.g. GET /auth/users/:id. Parameters match by position, not
+// name.
+func (d *Document) Find(method, route string) (string, *Operation) {
+	segments := strings.Split(route, "/")
+	for path, item := range d.Paths {
+		op := (*item)[strings.ToLower(method)]
+		if op != nil && matchPath(strings.Split(path, "/"), segments) {
+			return path, op
+		}
+	}
+	return "", nil
+}
+
+func matchPath(path, route []string) bool {
+	if len(path) != len(route) {
+		return false
+	}
+	for i, s := range path {
+		param := strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}")
+		if param != strings.HasPrefix(route[i], ":") || !param && s != route[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateOption configures Validate.
+type ValidateOption func(*validateOptions)
+
+type validateOptions struct {
+	responses bool
+}
+
+// ValidateResponses also checks what the handlers answer, replacing a
+// response the document doesn't describe with a 500 listing the violations.
+// Responses are held back until the handler returns, unless it flushes them.
+// Meant for tests.
+func ValidateResponses() ValidateOption {
+	return func(o *validateOptions) {
+		o.responses = true
+	}
+}
+
+// Validate checks the path, query and JSON body of requests against the
+// operation of doc their route matches and answers 400 with the violations,
+// as {"errors": {...}}, when they don't match. Routes doc doesn't describe are
+// let through. doc must not change once requests are served.
+func Validate(doc *Document, opts ...ValidateOption) gin.HandlerFunc {
+	var o validateOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var routes sync.Map
+	return func(c *gin.Context) {
+		key := c.Request.Method + " " + c.FullPath()
+		cached, ok := routes.Load(key)
+		if !ok {
+			path, op := doc.Find(c.Request.Method, c.FullPath())
+			cached, _ = routes.LoadOrStore(key, route{path: path, op: op})
+		}
+		r := cached.(route)
+		if r.op == nil {
+			c.Next()
+			return
+		}
+		if errs := doc.validateRequest(c, r); len(errs) > 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"errors": errs})
+			return
+		}
+		if !o.responses {
+			c.Next()
+			return
+		}
+		w := &responseRecorder{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		defer func() { c.Writer = w.ResponseWriter }()
+		c.Next()
+		if w.streaming {
+			return
+		}
+		if errs := doc.validateResponse(r.op, w); len(errs) > 0 {
+			slog.Error("Response doesn't match the OpenAPI document",
+				"method", c.Request.Method, "path", r.path, "status", w.status, "violations", errs)
+			w.ResponseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.ResponseWriter.WriteHeader(http.StatusInternalServerError)
+			data, _ := json.Marshal(gin.H{"error": "response doesn't match the OpenAPI document", "errors": errs})
+			w.ResponseWriter.Write(data)
+			return
+		}
+		w.flush()
+	}
+}
+
+// route is an operation with the path it is described under.
+type route struct {
+	path string
+	op   *Operation
+}
+
+func (d *Document) validateRequest(c *gin.Context, r route) Violations {
+	errs := make(Violations)
+	segments := strings.Split(r.path, "/")
+	routeSegments := strings.Split(c.FullPath(), "/")
+	query := c.Request.URL.Query()
+	for _, p := range r.op.Parameters {
+		var values []string
+		switch p.In {
+		case "path":
+			for i, s := range segments {
+				if s == "{"+p.Name+"}" {
+					values = []string{c.Param(strings.TrimPrefix(routeSegments[i], ":"))}
+				}
+			}
+		case "query":
+			values = query[p.Name]
+		default:
+			continue
+		}
+		location := p.In + "." + p.Name
+		if len(values) == 0 {
+			if p.Required {
+				errs[location] = "is required"
+			}
+			continue
+		}
+		v, err := parameterValue(d, p.Schema, values)
+		if err != nil {
+			errs[location] = err.Error()
+			continue
+		}
+		d.validate(p.Schema, v, location, errs)
+	}
+
+	body := r.op.RequestBody
+	if body == nil || body.Content["application/json"] == nil {
+		return errs
+	}
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		errs["body"] = err.Error()
+		return errs
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+	if len(bytes.TrimSpace(data)) == 0 {
+		if body.Required {
+			errs["body"] = "is required"
+		}
+		return errs
+	}
+	v, err := decode(data)
+	if err != nil {
+		errs["body"] = fmt.Sprintf("invalid JSON: %v", err)
+		return errs
+	}
+	d.validate(body.Content["application/json"].Schema, v, "body", errs)
+	return errs
+}
+
+// parameterValue reads the query or path values of a parameter as the type
+// of its schema.
+func parameterValue(d *Document, s *Schema, values []string) (any, error) {
+	if s.Ref != "" {
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	if s == nil || len(s.Type) == 0 {
+		return values[len(values)-1], nil
+	}
+	switch s.Type[0] {
+	case "array":
+		list := make([]any, len(values))
+		for i, v := range values {
+			var err error
+			if s.Items == nil {
+				list[i] = v
+			} else if list[i], err = parameterValue(d, s.Items, []string{v}); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case "integer", "number":
+		v := values[len(values)-1]
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return json.Number(v), nil
+	case "boolean":
+		b, err := strconv.ParseBool(values[len(values)-1])
+		if err != nil {
+			return nil, fmt.Errorf("must be true or false")
+		}
+		return b, nil
+	default:
+		return values[len(values)-1], nil
+	}
+}
+
+func (d *Document) validateResponse(op *Operation, w *responseRecorder) Violations {
+	resp := op.Responses[strconv.Itoa(w.status)]
+	if resp == nil {
+		resp = op.Responses["default"]
+	}
+	if resp == nil {
+		return Violations{"response": fmt.Sprintf("status %d is not described", w.status)}
+	}
+	if w.body.Len() == 0 {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	content := resp.Content[mediaType]
+	if content == nil {
+		return Violations{"response": fmt.Sprintf("%s body of status %d is not described", mediaType, w.status)}
+	}
+	if mediaType != "application/json" || content.Schema == nil {
+		return nil
+	}
+	v, err := decode(w.body.Bytes())
+	if err != nil {
+		return Violations{"response": fmt.Sprintf("invalid JSON: %v", err)}
+	}
+	return d.Validate(content.Schema, v, "response")
+}
+
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("data after the value")
+	}
+	return v, nil
+}
+
+// responseRecorder holds a response back until it has been checked. A flush
+// sends what it holds and streams the rest unchecked.
+type responseRecorder struct {
+	gin.ResponseWriter
+	status    int
+	written   bool
+	streaming bool
+	body      bytes.Buffer
+}
+
+func (w *responseRecorder) WriteHeader(code int) {
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *responseRecorder) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.written = true
+	if w.streaming {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *responseRecorder) Status() int {
+	return w.status
+}
+
+func (w *responseRecorder) Size() int {
+	if w.streaming {
+		return w.ResponseWriter.Size()
+	}
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *responseRecorder) Written() bool {
+	return w.written
+}
+
+func (w *responseRecorder) Flush() {
+	if !w.streaming {
+		w.flush()
+		w.streaming = true
+	}
+	w.ResponseWriter.Flush()
+}
+
+// flush sends the held status and body.
+func (w *responseRecorder) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.written {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+	if w.body.Len() > 0 {
+		w.ResponseWriter.Write(w.body.Bytes())
+		w.body.Reset()
+	}
+}
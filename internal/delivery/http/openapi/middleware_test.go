@@ -0,0 +1,148 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testItem struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func newTestRouter(t *testing.T, opts ...ValidateOption) *gin.Engine {
+	t.Helper()
+	doc := NewDocument("test", "1")
+	r := NewReflector(&doc.Components)
+	one := 1.0
+	doc.Paths["/items/{id}"] = &PathItem{
+		"get": {
+			OperationID: "get_item",
+			Parameters: []*Parameter{
+				{Name: "id", In: "path", Required: true, Schema: &Schema{Type: Type{"string"}, Format: "uuid"}},
+				{Name: "limit", In: "query", Schema: &Schema{Type: Type{"integer"}, Minimum: &one}},
+				{Name: "verbose", In: "query", Schema: &Schema{Type: Type{"boolean"}}},
+			},
+			Responses: map[string]*Response{
+				"200":     {Description: "OK", Content: JSONContent(r.Schema(testItem{}))},
+				"204":     {Description: "No Content"},
+				"default": {Description: "Error", Content: JSONContent(&Schema{Type: Type{"object"}})},
+			},
+		},
+		"put": {
+			OperationID: "put_item",
+			Parameters:  []*Parameter{{Name: "id", In: "path", Required: true, Schema: &Schema{Type: Type{"string"}}}},
+			RequestBody: &RequestBody{Required: true, Content: JSONContent(r.Schema(testRequest{}))},
+			Responses:   map[string]*Response{"200": {Description: "OK"}},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Validate(doc, opts...))
+	router.GET("/items/:item", func(c *gin.Context) {
+		switch c.Query("reply") {
+		case "invalid":
+			c.JSON(http.StatusOK, gin.H{"id": 1})
+		case "undescribed":
+			c.String(http.StatusOK, "text")
+		case "empty":
+			c.Status(http.StatusNoContent)
+		case "stream":
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Writer.WriteString("{}\n")
+			c.Writer.Flush()
+			c.Writer.WriteString("[]\n")
+		case "error":
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		default:
+			c.JSON(http.StatusOK, testItem{ID: c.Param("item"), Name: "item"})
+		}
+	})
+	router.PUT("/items/:item", func(c *gin.Context) {
+		var req testRequest
+		require.NoError(t, c.ShouldBindJSON(&req), "the body is still readable")
+		c.Status(http.StatusOK)
+	})
+	router.GET("/other", func(c *gin.Context) {
+		c.String(http.StatusOK, "not described")
+	})
+	return router
+}
+
+const itemID = "7c4b8b4e-4f3a-4b8e-9d0a-3c1f2e5d6a7b"
+
+func do(r http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return w
+}
+
+func violations(t *testing.T, w *httptest.ResponseRecorder) Violations {
+	t.Helper()
+	var resp struct {
+		Errors Violations `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+	return resp.Errors
+}
+
+func TestValidate_Requests(t *testing.T) {
+	r := newTestRouter(t)
+
+	assert.Equal(t, http.StatusOK, do(r, http.MethodGet, "/items/"+itemID+"?limit=5&verbose=true", "").Code)
+	assert.Equal(t, http.StatusOK, do(r, http.MethodGet, "/other?limit=x", "").Code, "undescribed routes are let through")
+
+	w := do(r, http.MethodGet, "/items/42?limit=0&verbose=maybe", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, Violations{
+		"path.id":       "must be a UUID",
+		"query.limit":   "must be at least 1",
+		"query.verbose": "must be true or false",
+	}, violations(t, w))
+
+	w = do(r, http.MethodPut, "/items/1", `{"email":"a@example.com","role":"admin","name":"ab"}`)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = do(r, http.MethodPut, "/items/1", "")
+	assert.Equal(t, Violations{"body": "is required"}, violations(t, w))
+
+	w = do(r, http.MethodPut, "/items/1", `{"email":"a@example.com","role":"admin","name":"ab"} {}`)
+	assert.Equal(t, Violations{"body": "invalid JSON: data after the value"}, violations(t, w))
+}
+
+func TestValidate_Responses(t *testing.T) {
+	r := newTestRouter(t, ValidateResponses())
+
+	w := do(r, http.MethodGet, "/items/"+itemID, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":"`+itemID+`","name":"item"}`, w.Body.String())
+
+	w = do(r, http.MethodGet, "/items/"+itemID+"?reply=error", "")
+	assert.Equal(t, http.StatusNotFound, w.Code, "errors match the default response")
+
+	w = do(r, http.MethodGet, "/items/"+itemID+"?reply=empty", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = do(r, http.MethodGet, "/items/"+itemID+"?reply=invalid", "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, Violations{
+		"response.id":   "must be string, not integer",
+		"response.name": "is required",
+	}, violations(t, w))
+
+	w = do(r, http.MethodGet, "/items/"+itemID+"?reply=undescribed", "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, Violations{"response": "text/plain body of status 200 is not described"}, violations(t, w))
+
+	w = do(r, http.MethodGet, "/items/"+itemID+"?reply=stream", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "{}\n[]\n", w.Body.String(), "flushed responses are streamed unchecked")
+}
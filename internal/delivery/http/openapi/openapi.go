@@ -0,0 +1,134 @@
+// Package openapi models the parts of an OpenAPI 3.1 document the HTTP server
+// describes itself with.
+package openapi
+
+import "encoding/json"
+
+// Version is the OpenAPI version of the documents built here.
+const Version = "3.1.0"
+
+// BearerAuth is the security scheme of operations that need an access token.
+const BearerAuth = "bearerAuth"
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// NewDocument returns an empty document with the BearerAuth scheme.
+func NewDocument(title, version string) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: title, Version: version},
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+			SecuritySchemes: map[string]*SecurityScheme{
+				BearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// SecurityRequirement names the schemes an operation accepts.
+type SecurityRequirement map[string][]string
+
+// PathItem holds the operations of one path by lower-case HTTP method.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name string `json:"name"`
+	// In is path, query, header or cookie.
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// JSONContent is the content map of a JSON body of schema.
+func JSONContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: schema}}
+}
+
+// Schema is a JSON Schema as OpenAPI 3.1 embeds it.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 Type               `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// Ref is a schema referring to the component schema name.
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// Type is one JSON type or, as OpenAPI 3.1 allows, several.
+type Type []string
+
+func (t Type) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *Type) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = Type{one}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
@@ -0,0 +1,224 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	timeType    = reflect.TypeFor[time.Time]()
+	uuidType    = reflect.TypeFor[uuid.UUID]()
+	rawJSONType = reflect.TypeFor[json.RawMessage]()
+)
+
+// Reflector builds schemas of Go types as encoding/json writes them and gin's
+// binding tags constrain them. Named structs become component schemas.
+type Reflector struct {
+	schemas map[string]*Schema
+	types   map[string]reflect.Type
+}
+
+// NewReflector adds the schemas it builds to c.
+func NewReflector(c *Components) *Reflector {
+	if c.Schemas == nil {
+		c.Schemas = make(map[string]*Schema)
+	}
+	return &Reflector{schemas: c.Schemas, types: make(map[string]reflect.Type)}
+}
+
+// Schema returns the schema of the type of v.
+//
+// Fields are required when their binding tag requires them or, in structs
+// without binding tags, which are the responses, when encoding/json always
+// writes them.
+func (r *Reflector) Schema(v any) *Schema {
+	return r.schema(reflect.TypeOf(v))
+}
+
+// Parameters returns a parameter in in for each field of the struct v with a
+// form tag, the way gin binds them.
+func (r *Reflector) Parameters(v any, in string) []*Parameter {
+	t := reflect.TypeOf(v)
+	var params []*Parameter
+	for _, f := range reflect.VisibleFields(t) {
+		name, _, _ := strings.Cut(f.Tag.Get("form"), ",")
+		if name == "" || name == "-" || !f.IsExported() {
+			continue
+		}
+		b := parseBinding(f.Tag.Get("binding"))
+		s := r.schema(f.Type)
+		b.constrain(s)
+		params = append(params, &Parameter{Name: name, In: in, Required: b.required, Schema: s})
+	}
+	return params
+}
+
+func (r *Reflector) schema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: Type{"string"}, Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: Type{"string"}, Format: "uuid"}
+	case rawJSONType:
+		return &Schema{}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(r.schema(t.Elem()))
+	case reflect.Bool:
+		return &Schema{Type: Type{"boolean"}}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: Type{"integer"}, Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: Type{"integer"}, Format: "int64"}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: Type{"integer"}, Format: "uint32"}
+	case reflect.Uint, reflect.Uint64:
+		return &Schema{Type: Type{"integer"}, Format: "uint64"}
+	case reflect.Float32:
+		return &Schema{Type: Type{"number"}, Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: Type{"number"}, Format: "double"}
+	case reflect.String:
+		return &Schema{Type: Type{"string"}}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: Type{"string"}, Format: "byte"}
+		}
+		return nullable(&Schema{Type: Type{"array"}, Items: r.schema(t.Elem())})
+	case reflect.Array:
+		return &Schema{Type: Type{"array"}, Items: r.schema(t.Elem())}
+	case reflect.Map:
+		return nullable(&Schema{Type: Type{"object"}, AdditionalProperties: r.schema(t.Elem())})
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.object(t)
+		}
+		return r.named(t)
+	default:
+		return &Schema{}
+	}
+}
+
+// named returns a reference to the component schema of t, named after the
+// type, capitalized, or, when two packages use the name, after both.
+func (r *Reflector) named(t reflect.Type) *Schema {
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	if other, ok := r.types[name]; ok && other != t {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+	}
+	if _, ok := r.schemas[name]; !ok {
+		r.types[name] = t
+		// Added before its fields, so recursive types end.
+		r.schemas[name] = &Schema{}
+		*r.schemas[name] = *r.object(t)
+	}
+	return Ref(name)
+}
+
+func (r *Reflector) object(t reflect.Type) *Schema {
+	s := &Schema{Type: Type{"object"}, Properties: make(map[string]*Schema)}
+	fields := reflect.VisibleFields(t)
+	bound := false
+	for _, f := range fields {
+		if _, ok := f.Tag.Lookup("binding"); ok {
+			bound = true
+		}
+	}
+	for _, f := range fields {
+		if !f.IsExported() || f.Anonymous && f.Tag.Get("json") == "" && f.Type.Kind() == reflect.Struct {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		b := parseBinding(f.Tag.Get("binding"))
+		fs := r.schema(f.Type)
+		b.constrain(fs)
+		s.Properties[name] = fs
+		omitted := strings.Contains(","+opts+",", ",omitempty,") || strings.Contains(","+opts+",", ",omitzero,")
+		if b.required || !bound && !omitted {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s
+}
+
+func nullable(s *Schema) *Schema {
+	if s.Ref != "" {
+		return s
+	}
+	s.Type = append(s.Type, "null")
+	return s
+}
+
+// binding is what the schema can tell of a gin binding tag.
+type binding struct {
+	required  bool
+	omitempty bool
+	format    string
+	enum      []string
+	min, max  *int
+}
+
+func parseBinding(tag string) binding {
+	var b binding
+	for rule := range strings.SplitSeq(tag, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			b.required = true
+		case "omitempty":
+			b.omitempty = true
+		case "email", "uuid":
+			b.format = name
+		case "oneof":
+			b.enum = strings.Fields(arg)
+		case "min", "max":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				panic(fmt.Sprintf("openapi: binding %q: %v", tag, err))
+			}
+			if name == "min" {
+				b.min = &n
+			} else {
+				b.max = &n
+			}
+		}
+	}
+	return b
+}
+
+// constrain adds the rules of b to the schema of a field. Formats and enums
+// are left out of optional fields, which may be empty.
+func (b binding) constrain(s *Schema) {
+	if s.Ref != "" || len(s.Type) == 0 {
+		return
+	}
+	switch s.Type[0] {
+	case "string":
+		if !b.omitempty {
+			if b.format != "" {
+				s.Format = b.format
+			}
+			s.Enum = b.enum
+		}
+		s.MinLength, s.MaxLength = b.min, b.max
+	case "integer", "number":
+		if b.min != nil {
+			min := float64(*b.min)
+			s.Minimum = &min
+		}
+	}
+}
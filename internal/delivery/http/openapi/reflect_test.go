@@ -0,0 +1,99 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequest struct {
+	Email  string   `json:"email" binding:"required,email"`
+	Role   string   `json:"role" binding:"required,oneof=admin member"`
+	OrgID  string   `json:"org_id" binding:"omitempty,uuid"`
+	Name   string   `json:"name" binding:"min=2,max=10"`
+	Tags   []string `json:"tags"`
+	DryRun bool     `json:"dry_run"`
+}
+
+type testResponse struct {
+	ID        uuid.UUID         `json:"id"`
+	CreatedAt time.Time         `json:"created_at"`
+	Parent    *testResponse     `json:"parent,omitempty"`
+	Labels    map[string]string `json:"labels"`
+	Count     uint64            `json:"count"`
+	internal  string
+}
+
+func TestReflector_Schema(t *testing.T) {
+	var c Components
+	r := NewReflector(&c)
+
+	assert.Equal(t, Ref("TestRequest"), r.Schema(testRequest{}))
+	req := c.Schemas["TestRequest"]
+	require.NotNil(t, req)
+	assert.Equal(t, []string{"email", "role"}, req.Required, "a struct with binding tags requires what they require")
+	assert.Equal(t, "email", req.Properties["email"].Format)
+	assert.Equal(t, []string{"admin", "member"}, req.Properties["role"].Enum)
+	assert.Empty(t, req.Properties["org_id"].Format, "optional fields may be empty")
+	assert.Equal(t, 2, *req.Properties["name"].MinLength)
+	assert.Equal(t, 10, *req.Properties["name"].MaxLength)
+	assert.Equal(t, Type{"array", "null"}, req.Properties["tags"].Type)
+
+	r.Schema(testResponse{})
+	resp := c.Schemas["TestResponse"]
+	require.NotNil(t, resp)
+	assert.Equal(t, []string{"id", "created_at", "labels", "count"}, resp.Required, "responses require what is always written")
+	assert.Equal(t, "uuid", resp.Properties["id"].Format)
+	assert.Equal(t, "date-time", resp.Properties["created_at"].Format)
+	assert.Equal(t, Ref("TestResponse"), resp.Properties["parent"])
+	assert.Equal(t, Type{"object", "null"}, resp.Properties["labels"].Type)
+	assert.NotContains(t, resp.Properties, "internal")
+
+	data, err := json.Marshal(resp.Properties["id"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"string","format":"uuid"}`, string(data))
+}
+
+func TestReflector_Parameters(t *testing.T) {
+	var c Components
+	params := NewReflector(&c).Parameters(struct {
+		Subject string    `form:"subject" binding:"required"`
+		Since   time.Time `form:"since"`
+		Skipped string
+	}{}, "query")
+	require.Len(t, params, 2)
+	assert.Equal(t, &Parameter{Name: "subject", In: "query", Required: true, Schema: &Schema{Type: Type{"string"}}}, params[0])
+	assert.Equal(t, "date-time", params[1].Schema.Format)
+}
+
+func TestDocument_Validate(t *testing.T) {
+	doc := NewDocument("test", "1")
+	s := NewReflector(&doc.Components).Schema(testRequest{})
+
+	value := func(data string) any {
+		v, err := decode([]byte(data))
+		require.NoError(t, err)
+		return v
+	}
+	assert.Empty(t, doc.Validate(s, value(`{"email":"a@example.com","role":"admin","name":"ab","tags":null}`), "body"))
+	assert.Equal(t, Violations{
+		"body.email":   "must be a valid email",
+		"body.role":    "must be one of admin, member",
+		"body.name":    "must be at most 10 characters",
+		"body.tags[1]": "must be string, not integer",
+		"body.dry_run": "must be boolean, not string",
+	}, doc.Validate(s, value(`{"email":"nope","role":"owner","name":"abcdefghijk","tags":["a",1],"dry_run":"yes"}`), "body"))
+	assert.Equal(t, Violations{"body.email": "is required", "body.role": "is required"}, doc.Validate(s, value(`{}`), "body"))
+	assert.Equal(t, Violations{"body": "must be object, not array"}, doc.Validate(s, value(`[]`), "body"))
+	assert.Equal(t, Violations{"body": "unknown schema #/components/schemas/Nope"}, doc.Validate(Ref("Nope"), value(`{}`), "body"))
+
+	int64s := &Schema{Type: Type{"string", "integer"}, Format: "int64"}
+	assert.Empty(t, doc.Validate(int64s, value(`"12"`), "v"))
+	assert.Empty(t, doc.Validate(int64s, value(`12`), "v"))
+	assert.NotEmpty(t, doc.Validate(int64s, value(`"x"`), "v"))
+	assert.NotEmpty(t, doc.Validate(&Schema{Type: Type{"integer"}}, value(`1.5`), "v"))
+}
@@ -0,0 +1,37 @@
+package openapi
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// swaggerUIVersion is the swagger-ui-dist release the page loads.
+const swaggerUIVersion = "5.17.14"
+
+// SwaggerUI serves a Swagger UI page for the document at specURL. The page
+// loads Swagger UI from unpkg.com.
+func SwaggerUI(title, specURL string) gin.HandlerFunc {
+	base := "https://unpkg.com/swagger-ui-dist@" + swaggerUIVersion
+	page := fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%s</title>
+  <link rel="stylesheet" href="%s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="%s/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: %q, dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`, html.EscapeString(title), base, base, specURL)
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+	}
+}
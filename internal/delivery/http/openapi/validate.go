@@ -0,0 +1,146 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/mail"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Violations maps the location of each invalid part of a value, e.g.
+// body.email or query.page_size, to what is wrong with it.
+type Violations map[string]string
+
+// Validate checks v, a value decoded by encoding/json with UseNumber, against
+// s, resolving references in d. Violations are reported under path.
+func (d *Document) Validate(s *Schema, v any, path string) Violations {
+	errs := make(Violations)
+	d.validate(s, v, path, errs)
+	return errs
+}
+
+func (d *Document) validate(s *Schema, v any, path string, errs Violations) {
+	if s.Ref != "" {
+		ref, ok := d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if !ok {
+			errs[path] = fmt.Sprintf("unknown schema %s", s.Ref)
+			return
+		}
+		s = ref
+	}
+	t := jsonType(v)
+	if len(s.Type) > 0 && !slices.Contains(s.Type, t) && !(t == "integer" && slices.Contains(s.Type, "number")) {
+		errs[path] = fmt.Sprintf("must be %s, not %s", strings.Join(s.Type, " or "), t)
+		return
+	}
+	switch v := v.(type) {
+	case string:
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, v) {
+			errs[path] = fmt.Sprintf("must be one of %s", strings.Join(s.Enum, ", "))
+			return
+		}
+		n := utf8.RuneCountInString(v)
+		if s.MinLength != nil && n < *s.MinLength {
+			errs[path] = fmt.Sprintf("must be at least %d characters", *s.MinLength)
+			return
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			errs[path] = fmt.Sprintf("must be at most %d characters", *s.MaxLength)
+			return
+		}
+		if err := checkFormat(s.Format, v); err != nil {
+			errs[path] = err.Error()
+		}
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			errs[path] = err.Error()
+			return
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			errs[path] = fmt.Sprintf("must be at least %v", *s.Minimum)
+		}
+	case []any:
+		if s.Items == nil {
+			return
+		}
+		for i, e := range v {
+			d.validate(s.Items, e, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				errs[path+"."+name] = "is required"
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			if p, ok := s.Properties[name]; ok {
+				d.validate(p, v[name], path+"."+name, errs)
+			} else if s.AdditionalProperties != nil {
+				d.validate(s.AdditionalProperties, v[name], path+"."+name, errs)
+			}
+		}
+	}
+}
+
+func jsonType(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		if _, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// checkFormat asserts the formats the documents use on strings. 64-bit
+// integers may be written as strings, as protojson does.
+func checkFormat(format, v string) error {
+	var err error
+	switch format {
+	case "uuid":
+		if len(v) != 36 {
+			return fmt.Errorf("must be a UUID")
+		}
+		_, err = uuid.Parse(v)
+	case "email":
+		var addr *mail.Address
+		if addr, err = mail.ParseAddress(v); err == nil && addr.Address != v {
+			err = fmt.Errorf("has a display name")
+		}
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, v)
+	case "int64":
+		_, err = strconv.ParseInt(v, 10, 64)
+	case "uint64":
+		_, err = strconv.ParseUint(v, 10, 64)
+	default:
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("must be a valid %s", format)
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/internal/delivery/http/dto/request"
+	"github.com/mrhumster/web-server-gin/internal/delivery/http/dto/response"
+	"github.com/mrhumster/web-server-gin/internal/delivery/http/openapi"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+)
+
+// apiRoute describes a route of SetupRoutes in the OpenAPI document. body
+// and the values of responses are Go values of the JSON written, or text; a
+// nil response has no body.
+type apiRoute struct {
+	method, path string
+	tag          string
+	summary      string
+	public       bool
+	params       []*openapi.Parameter
+	// query is a struct whose form tags name query parameters.
+	query     any
+	body      any
+	responses map[int]any
+}
+
+// text is a response body of the media type that isn't JSON.
+type text string
+
+type createdUser struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type orgMember struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	Role           string    `json:"role"`
+}
+
+type roleMember struct {
+	Role   string    `json:"role"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type health struct {
+	Status          string                    `json:"status"`
+	Error           string                    `json:"error,omitempty"`
+	PermissionCache *auth.CacheStats          `json:"permission_cache,omitempty"`
+	PolicyOutbox    *models.PolicyOutboxStats `json:"policy_outbox,omitempty"`
+}
+
+// errorResponse is written by handlers and middleware on failure: error, or
+// errors by field for invalid requests.
+type errorResponse struct {
+	Error  string            `json:"error,omitempty"`
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+func pathParam(name string, s *openapi.Schema) *openapi.Parameter {
+	return &openapi.Parameter{Name: name, In: "path", Required: true, Schema: s}
+}
+
+func queryParam(name, description string, s *openapi.Schema) *openapi.Parameter {
+	return &openapi.Parameter{Name: name, In: "query", Description: description, Schema: s}
+}
+
+func uuidSchema() *openapi.Schema {
+	return &openapi.Schema{Type: openapi.Type{"string"}, Format: "uuid"}
+}
+
+func stringSchema() *openapi.Schema {
+	return &openapi.Schema{Type: openapi.Type{"string"}}
+}
+
+func pageSize() *openapi.Parameter {
+	one := 1.0
+	return queryParam("page_size", "", &openapi.Schema{Type: openapi.Type{"integer"}, Format: "int32", Minimum: &one})
+}
+
+func pageToken() *openapi.Parameter {
+	return queryParam("page_token", "next_page_token of the previous page", stringSchema())
+}
+
+var apiRoutes = []apiRoute{
+	{method: http.MethodPost, path: "/auth/login", tag: "auth", public: true,
+		summary: "Log in; the refresh token is set as the refresh_token cookie",
+		body:    request.LoginRequest{}, responses: map[int]any{http.StatusOK: response.LoginResponse{}}},
+	{method: http.MethodPost, path: "/auth/users", tag: "users", public: true, summary: "Sign up",
+		body: request.UserRequest{}, responses: map[int]any{http.StatusCreated: createdUser{}}},
+	{method: http.MethodPost, path: "/auth/refresh", tag: "auth", public: true,
+		summary:   "Exchange the refresh_token cookie for a new token pair",
+		params:    []*openapi.Parameter{{Name: "refresh_token", In: "cookie", Required: true, Schema: stringSchema()}},
+		responses: map[int]any{http.StatusOK: response.LoginResponse{}}},
+	{method: http.MethodGet, path: "/auth/public-key", tag: "auth", public: true,
+		summary: "PEM public key of the access tokens", responses: map[int]any{http.StatusOK: text("application/x-pem-file")}},
+	{method: http.MethodGet, path: "/auth/health", tag: "health", public: true, summary: "Health check",
+		responses: map[int]any{http.StatusOK: health{}, http.StatusServiceUnavailable: health{}}},
+	{method: http.MethodGet, path: "/openapi.json", tag: "docs", public: true, summary: "This document",
+		responses: map[int]any{http.StatusOK: map[string]any{}}},
+	{method: http.MethodGet, path: "/docs", tag: "docs", public: true, summary: "Swagger UI",
+		responses: map[int]any{http.StatusOK: text("text/html")}},
+
+	{method: http.MethodGet, path: "/auth/who", tag: "auth", summary: "The authenticated user",
+		responses: map[int]any{http.StatusOK: response.UserResponse{}}},
+	{method: http.MethodGet, path: "/auth/who/permissions", tag: "auth",
+		summary:   "Roles and permissions of the authenticated user",
+		params:    []*openapi.Parameter{pageSize(), pageToken()},
+		responses: map[int]any{http.StatusOK: response.PermissionsResponse{}}},
+	{method: http.MethodPost, path: "/auth/logout", tag: "auth", summary: "Clear the refresh token cookie",
+		responses: map[int]any{http.StatusOK: response.Success{}}},
+	{method: http.MethodPost, path: "/auth/logout-all", tag: "auth", summary: "Revoke every token of the user",
+		responses: map[int]any{http.StatusOK: response.Success{}}},
+	{method: http.MethodPost, path: "/auth/switch-org", tag: "auth",
+		summary: "Reissue the token pair for an organization; an empty org_id switches to the platform",
+		body:    request.SwitchOrganizationRequest{}, responses: map[int]any{http.StatusOK: response.LoginResponse{}}},
+
+	{method: http.MethodGet, path: "/auth/users", tag: "users", summary: "List the users the caller may read",
+		params: []*openapi.Parameter{
+			queryParam("page", "1 when unset", &openapi.Schema{Type: openapi.Type{"integer"}, Format: "int64"}),
+			queryParam("limit", "10 when unset, at most 100", &openapi.Schema{Type: openapi.Type{"integer"}, Format: "int64"}),
+		},
+		responses: map[int]any{http.StatusOK: response.UsersListReponse{}}},
+	{method: http.MethodGet, path: "/auth/users/{id}", tag: "users", summary: "Read a user",
+		params:    []*openapi.Parameter{pathParam("id", uuidSchema())},
+		responses: map[int]any{http.StatusOK: response.UserResponse{}}},
+	{method: http.MethodPatch, path: "/auth/users/{id}", tag: "users", summary: "Update a user",
+		params: []*openapi.Parameter{pathParam("id", uuidSchema())},
+		body:   request.UpdateUserRequest{}, responses: map[int]any{http.StatusOK: response.UserResponse{}}},
+	{method: http.MethodDelete, path: "/auth/users/{id}", tag: "users", summary: "Delete a user",
+		params:    []*openapi.Parameter{pathParam("id", uuidSchema())},
+		responses: map[int]any{http.StatusNoContent: nil}},
+
+	{method: http.MethodGet, path: "/auth/orgs", tag: "organizations", summary: "Organizations of the user",
+		responses: map[int]any{http.StatusOK: response.OrganizationsListResponse{}}},
+	{method: http.MethodPost, path: "/auth/orgs", tag: "organizations",
+		summary: "Create an organization; the caller becomes its admin",
+		body:    request.OrganizationRequest{}, responses: map[int]any{http.StatusCreated: response.OrganizationResponse{}}},
+	{method: http.MethodGet, path: "/auth/orgs/{id}", tag: "organizations", summary: "Read an organization",
+		params:    []*openapi.Parameter{pathParam("id", uuidSchema())},
+		responses: map[int]any{http.StatusOK: response.OrganizationResponse{}}},
+	{method: http.MethodGet, path: "/auth/orgs/{id}/members", tag: "organizations", summary: "List members",
+		params:    []*openapi.Parameter{pathParam("id", uuidSchema())},
+		responses: map[int]any{http.StatusOK: response.OrgMembersResponse{}}},
+	{method: http.MethodPost, path: "/auth/orgs/{id}/members", tag: "organizations", summary: "Add a member",
+		params: []*openapi.Parameter{pathParam("id", uuidSchema())},
+		body:   request.OrgMemberRequest{}, responses: map[int]any{http.StatusCreated: orgMember{}}},
+	{method: http.MethodPatch, path: "/auth/orgs/{id}/members/{user_id}", tag: "organizations",
+		summary: "Change the role of a member",
+		params:  []*openapi.Parameter{pathParam("id", uuidSchema()), pathParam("user_id", uuidSchema())},
+		body:    request.OrgMemberRoleRequest{}, responses: map[int]any{http.StatusOK: orgMember{}}},
+	{method: http.MethodDelete, path: "/auth/orgs/{id}/members/{user_id}", tag: "organizations",
+		summary:   "Remove a member",
+		params:    []*openapi.Parameter{pathParam("id", uuidSchema()), pathParam("user_id", uuidSchema())},
+		responses: map[int]any{http.StatusNoContent: nil}},
+
+	{method: http.MethodGet, path: "/admin/roles", tag: "roles", summary: "List roles",
+		params:    []*openapi.Parameter{queryParam("domain", "", stringSchema())},
+		responses: map[int]any{http.StatusOK: response.RolesResponse{}}},
+	{method: http.MethodGet, path: "/admin/roles/{id}", tag: "roles", summary: "Policies and members of a role",
+		params:    []*openapi.Parameter{pathParam("id", stringSchema())},
+		responses: map[int]any{http.StatusOK: response.RoleResponse{}}},
+	{method: http.MethodGet, path: "/admin/roles/{id}/members", tag: "roles", summary: "List members of a role",
+		params:    []*openapi.Parameter{pathParam("id", stringSchema())},
+		responses: map[int]any{http.StatusOK: response.RoleMembersResponse{}}},
+	{method: http.MethodPost, path: "/admin/roles/{id}/members", tag: "roles", summary: "Assign a role",
+		params: []*openapi.Parameter{pathParam("id", stringSchema())},
+		body:   request.RoleMemberRequest{}, responses: map[int]any{http.StatusCreated: roleMember{}}},
+	{method: http.MethodDelete, path: "/admin/roles/{id}/members/{user_id}", tag: "roles",
+		summary:   "Unassign a role",
+		params:    []*openapi.Parameter{pathParam("id", stringSchema()), pathParam("user_id", uuidSchema())},
+		responses: map[int]any{http.StatusNoContent: nil}},
+
+	{method: http.MethodGet, path: "/admin/policies", tag: "policies", summary: "List policies matching the filter",
+		query: request.PolicyFilterRequest{}, params: []*openapi.Parameter{pageSize(), pageToken()},
+		responses: map[int]any{http.StatusOK: response.PoliciesResponse{}}},
+	{method: http.MethodPost, path: "/admin/policies", tag: "policies", summary: "Create a policy",
+		body: request.PolicyRequest{}, responses: map[int]any{http.StatusCreated: response.PermissionResponse{}}},
+	{method: http.MethodPut, path: "/admin/policies", tag: "policies",
+		summary: "Replace the policies matching the filter, or every policy without one",
+		query:   request.PolicyFilterRequest{},
+		body:    request.ReplacePoliciesRequest{}, responses: map[int]any{http.StatusOK: response.ReplacePoliciesResponse{}}},
+	{method: http.MethodDelete, path: "/admin/policies", tag: "policies", summary: "Delete the policy in the query",
+		query: request.PolicyRequest{}, responses: map[int]any{http.StatusNoContent: nil}},
+	{method: http.MethodGet, path: "/admin/policies/history", tag: "policies",
+		summary: "Recorded policy changes, newest first",
+		query:   request.PolicyHistoryRequest{}, params: []*openapi.Parameter{pageSize(), pageToken()},
+		responses: map[int]any{http.StatusOK: response.PolicyChangesResponse{}}},
+	{method: http.MethodPost, path: "/admin/policies/revert", tag: "policies",
+		summary: "Revert the policies to a revision of the history",
+		body:    request.RevertPoliciesRequest{}, responses: map[int]any{http.StatusOK: response.PolicyPlanResponse{}}},
+}
+
+// describeRoutes adds apiRoutes to doc. Every response may instead be an
+// error, as errorResponse.
+func describeRoutes(doc *openapi.Document) {
+	r := openapi.NewReflector(&doc.Components)
+	failure := &openapi.Response{Description: "Error", Content: openapi.JSONContent(r.Schema(errorResponse{}))}
+	for _, route := range apiRoutes {
+		op := &openapi.Operation{
+			OperationID: operationID(route.method, route.path),
+			Summary:     route.summary,
+			Tags:        []string{route.tag},
+			Parameters:  route.params,
+			Responses:   map[string]*openapi.Response{"default": failure},
+		}
+		if route.query != nil {
+			op.Parameters = append(r.Parameters(route.query, "query"), op.Parameters...)
+		}
+		if !route.public {
+			op.Security = []openapi.SecurityRequirement{{openapi.BearerAuth: {}}}
+		}
+		if route.body != nil {
+			op.RequestBody = &openapi.RequestBody{Required: true, Content: openapi.JSONContent(r.Schema(route.body))}
+		}
+		for status, body := range route.responses {
+			resp := &openapi.Response{Description: http.StatusText(status)}
+			switch body := body.(type) {
+			case nil:
+			case text:
+				resp.Content = map[string]*openapi.MediaType{string(body): {Schema: stringSchema()}}
+			default:
+				resp.Content = openapi.JSONContent(r.Schema(body))
+			}
+			op.Responses[fmt.Sprint(status)] = resp
+		}
+		item := doc.Paths[route.path]
+		if item == nil {
+			item = &openapi.PathItem{}
+			doc.Paths[route.path] = item
+		}
+		(*item)[strings.ToLower(route.method)] = op
+	}
+}
+
+// operationID names an operation after its route, e.g. GET /auth/users/{id}
+// is get_auth_users_id.
+func operationID(method, path string) string {
+	return strings.ToLower(method) + operationIDReplacer.Replace(path)
+}
+
+var operationIDReplacer = strings.NewReplacer("{", "", "}", "", "/", "_", "-", "_", ".", "_")
@@ -0,0 +1,175 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrhumster/web-server-gin/internal/delivery/http/openapi"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+	authmock "github.com/mrhumster/web-server-gin/pkg/auth/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// setupOfflineRoutes builds the routes on a database that is never connected
+// to, for tests that don't get past the middleware.
+func setupOfflineRoutes(t *testing.T, opts ...Option) *gin.Engine {
+	t.Helper()
+	db, err := gorm.Open(postgres.Open("host=127.0.0.1 port=1 connect_timeout=1"), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+	client := authmock.NewMockPermissionClient(gomock.NewController(t))
+	client.EXPECT().AddPolicyInDomain(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	client.EXPECT().AddConditionalPolicy(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	client.EXPECT().AssignRoleInDomain(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	return SetupRoutes(db, "test", client, opts...)
+}
+
+func readDocument(t *testing.T, r *gin.Engine) *openapi.Document {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var doc openapi.Document
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	return &doc
+}
+
+// Every route must be described, so adding one without a spec entry fails
+// here.
+func TestOpenAPI_DescribesEveryRoute(t *testing.T) {
+	conn, err := auth.Dial("127.0.0.1:1")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	for name, opts := range map[string][]Option{
+		"rest":    nil,
+		"gateway": {WithGateway(conn)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := setupOfflineRoutes(t, opts...)
+			doc := readDocument(t, r)
+			for _, route := range r.Routes() {
+				path, op := doc.Find(route.Method, route.Path)
+				if !assert.NotNil(t, op, "%s %s is not in the OpenAPI document", route.Method, route.Path) {
+					continue
+				}
+				// Every {param} of the path is declared.
+				for _, segment := range strings.Split(path, "/") {
+					name, ok := strings.CutPrefix(segment, "{")
+					if !ok {
+						continue
+					}
+					name = strings.TrimSuffix(name, "}")
+					assert.True(t, hasParameter(op, name, "path"), "%s %s: path parameter %s", route.Method, path, name)
+				}
+			}
+		})
+	}
+}
+
+func hasParameter(op *openapi.Operation, name, in string) bool {
+	for _, p := range op.Parameters {
+		if p.Name == name && p.In == in {
+			return true
+		}
+	}
+	return false
+}
+
+func TestOpenAPI_References(t *testing.T) {
+	doc := openapi.NewDocument("test", "1")
+	describeRoutes(doc)
+	ids := make(map[string]bool)
+	for path, item := range doc.Paths {
+		for method, op := range *item {
+			assert.False(t, ids[op.OperationID], "duplicate operationId %s", op.OperationID)
+			ids[op.OperationID] = true
+			for status, resp := range op.Responses {
+				for _, content := range resp.Content {
+					assertResolves(t, doc, content.Schema, method+" "+path+" "+status)
+				}
+			}
+			if op.RequestBody != nil {
+				assertResolves(t, doc, op.RequestBody.Content["application/json"].Schema, method+" "+path+" body")
+			}
+		}
+	}
+	login := (*doc.Paths["/auth/login"])["post"]
+	assert.Nil(t, login.Security)
+	body := doc.Components.Schemas["LoginRequest"]
+	require.NotNil(t, body)
+	assert.ElementsMatch(t, []string{"email", "password"}, body.Required)
+	assert.Equal(t, "email", body.Properties["email"].Format)
+}
+
+func assertResolves(t *testing.T, doc *openapi.Document, s *openapi.Schema, where string) {
+	t.Helper()
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		if assert.Contains(t, doc.Components.Schemas, name, where) {
+			for _, p := range doc.Components.Schemas[name].Properties {
+				if p.Ref != s.Ref {
+					assertResolves(t, doc, p, where)
+				}
+			}
+		}
+		return
+	}
+	assertResolves(t, doc, s.Items, where)
+	assertResolves(t, doc, s.AdditionalProperties, where)
+	for _, p := range s.Properties {
+		assertResolves(t, doc, p, where)
+	}
+}
+
+func TestOpenAPI_ValidatesRequests(t *testing.T) {
+	r := setupOfflineRoutes(t)
+
+	for name, tc := range map[string]struct {
+		method, target, body string
+		field                string
+	}{
+		"invalid email":    {http.MethodPost, "/auth/login", `{"email":"nope","password":"secret"}`, "body.email"},
+		"missing password": {http.MethodPost, "/auth/users", `{"email":"a@example.com"}`, "body.password"},
+		"wrong type":       {http.MethodPost, "/admin/policies/revert", `{"revision":"1"}`, "body.revision"},
+		"invalid JSON":     {http.MethodPost, "/auth/login", `{"email"`, "body"},
+		"path parameter":   {http.MethodGet, "/auth/users/42", "", "path.id"},
+		"query parameter":  {http.MethodGet, "/admin/policies?page_size=0", "", "query.page_size"},
+		"enum":             {http.MethodPatch, "/auth/orgs/7c4b8b4e-4f3a-4b8e-9d0a-3c1f2e5d6a7b/members/7c4b8b4e-4f3a-4b8e-9d0a-3c1f2e5d6a7b", `{"role":"owner"}`, "body.role"},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, name)
+		var resp struct {
+			Errors map[string]string `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), name)
+		assert.Contains(t, resp.Errors, tc.field, name)
+	}
+
+	// Valid requests reach the route, here its authentication.
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/users/7c4b8b4e-4f3a-4b8e-9d0a-3c1f2e5d6a7b", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Responses are checked too in test mode.
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/public-key", nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "PUBLIC KEY")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"/openapi.json"`)
+}
@@ -5,20 +5,59 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/mrhumster/web-server-gin/config"
+	"github.com/mrhumster/web-server-gin/internal/delivery/http/gateway"
 	"github.com/mrhumster/web-server-gin/internal/delivery/http/handler"
+	"github.com/mrhumster/web-server-gin/internal/delivery/http/openapi"
 	"github.com/mrhumster/web-server-gin/internal/repository"
 	"github.com/mrhumster/web-server-gin/internal/service"
 	"github.com/mrhumster/web-server-gin/pkg/auth"
 	"github.com/mrhumster/web-server-gin/pkg/middleware"
+	"google.golang.org/grpc"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(db *gorm.DB, mode string, permissionClient auth.PermissionClient) *gin.Engine {
+// cacheStatsReporter is implemented by auth.CachingPermissionClient.
+type cacheStatsReporter interface {
+	Stats() auth.CacheStats
+}
+
+// Option configures SetupRoutes.
+type Option func(*options)
+
+type options struct {
+	gateway      grpc.ClientConnInterface
+	policyOutbox *service.PolicyOutbox
+}
+
+// WithGateway serves the gRPC methods bound in config/gateway.yaml as JSON
+// under /v1, calling them over conn, and describes them in /openapi.json.
+func WithGateway(conn grpc.ClientConnInterface) Option {
+	return func(o *options) {
+		o.gateway = conn
+	}
+}
+
+// WithPolicyOutbox wakes outbox when the routes store policy operations and
+// reports it in the health check. The caller runs it; without it operations
+// wait for an outbox run elsewhere.
+func WithPolicyOutbox(outbox *service.PolicyOutbox) Option {
+	return func(o *options) {
+		o.policyOutbox = outbox
+	}
+}
+
+func SetupRoutes(db *gorm.DB, mode string, permissionClient auth.PermissionClient, opts ...Option) *gin.Engine {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	// MODE
 	if mode == "test" {
 		gin.SetMode(gin.TestMode)
@@ -29,6 +68,9 @@ func SetupRoutes(db *gorm.DB, mode string, permissionClient auth.PermissionClien
 
 	// GIN ROUTE
 	r := gin.New()
+	// Handlers pass the gin context on as a context.Context; this makes it
+	// carry the request's cancellation and the actor set by AuthMiddleware.
+	r.ContextWithFallback = true
 
 	r.Use(middleware.StructuredLog())
 	r.Use(gin.Recovery())
@@ -48,6 +90,15 @@ func SetupRoutes(db *gorm.DB, mode string, permissionClient auth.PermissionClien
 		AllowCredentials: true,
 	}))
 
+	// OPENAPI
+	doc := openapi.NewDocument("web-server-gin", "1.0.0")
+	describeRoutes(doc)
+	var validateOpts []openapi.ValidateOption
+	if mode == "test" {
+		validateOpts = append(validateOpts, openapi.ValidateResponses())
+	}
+	r.Use(openapi.Validate(doc, validateOpts...))
+
 	// CONFIGURATION
 	cfg, _ := config.LoadConfig()
 	if mode == "test" || mode == "debug" {
@@ -56,9 +107,23 @@ func SetupRoutes(db *gorm.DB, mode string, permissionClient auth.PermissionClien
 
 	// REPOSITORIES
 	userRepo := repository.NewGormUserRepository(db)
+	orgRepo := repository.NewGormOrganizationRepository(db)
 
 	// SERVICES
 	userService := service.NewUserService(userRepo, permissionClient)
+	userService.SetPolicyOutbox(o.policyOutbox)
+	if cfg.Server.ProvisioningFile != "" {
+		templates, err := auth.ReadProvisioningTemplates(cfg.Server.ProvisioningFile)
+		if err != nil {
+			fmt.Printf("⚠️ SetupRoutes: %v", err)
+			panic("Error load provisioning templates")
+		}
+		userService.SetProvisioningTemplates(templates)
+	}
+	roleService := service.NewRoleService(userService, permissionClient)
+	orgService := service.NewOrganizationService(orgRepo, userRepo)
+	orgService.SetPolicyOutbox(o.policyOutbox)
+	policyService := service.NewPolicyService(permissionClient)
 	tokenService, err := service.NewTokenService(&cfg.JWT)
 	if err != nil {
 		fmt.Printf("⚠️ SetupRoutes: %v", err)
@@ -67,45 +132,21 @@ func SetupRoutes(db *gorm.DB, mode string, permissionClient auth.PermissionClien
 
 	// HANDLERS
 	userHandler := handler.NewUserHandler(userService)
-	authHandler := handler.NewAuthHandler(userService, tokenService, cfg.Server.JwtSecret, cfg.Server.Domain)
+	authHandler := handler.NewAuthHandler(userService, tokenService, orgService, cfg.Server.JwtSecret, cfg.Server.Domain)
 	commonHandler := handler.NewCommonHandler(tokenService)
+	roleHandler := handler.NewRoleHandler(roleService)
+	orgHandler := handler.NewOrganizationHandler(orgService)
+	policyHandler := handler.NewPolicyHandler(policyService)
 
 	// PERMISSIONS
+	seedPolicies(permissionClient)
 
-	type permission struct {
-		sub string
-		obj string
-		act string
-	}
-
-	policies := []struct {
-		params permission
-		desc   string
-	}{
-		{permission{"*", "users", "read"}, "users read policy"},
-		{permission{"*", "stream", "read"}, "stream read policy"},
-		{permission{"*", "stream", "write"}, "stream write policy"},
-	}
-
-	for _, policy := range policies {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-
-		_, err := permissionClient.AddPolicyIfNotExists(ctx,
-			policy.params.sub,
-			policy.params.obj,
-			policy.params.act,
-		)
-
-		cancel()
-
-		if err != nil {
-			log.Printf("⚠️ Failed to add %s: %v", policy.desc, err)
-		} else {
-			log.Printf("✅ Successfully added %s", policy.desc)
-		}
+	// ROUTE
+	failPolicy := middleware.WithFailPolicy(newFailPolicy(cfg.Client))
+	authorize := func(obj, act string) gin.HandlerFunc {
+		return middleware.Authorize(permissionClient, obj, act, failPolicy)
 	}
 
-	// ROUTE
 	r.POST("/auth/login", authHandler.Login)
 	r.POST("/auth/users", userHandler.CreateUser)
 	r.POST("/auth/refresh", authHandler.Refresh)
@@ -113,14 +154,56 @@ func SetupRoutes(db *gorm.DB, mode string, permissionClient auth.PermissionClien
 	auth := r.Group("/auth/", middleware.AuthMiddleware(tokenService))
 	{
 		auth.GET("/who", userHandler.GetAuthUser)
+		auth.GET("/who/permissions", userHandler.GetAuthPermissions)
 		auth.POST("/logout", authHandler.Logout)
 		auth.POST("/logout-all", authHandler.LogoutAll)
-		auth.GET("/users", middleware.Authorize(permissionClient, "users", "read"), userHandler.ReadUsers)
-		auth.GET("/users/:id", middleware.Authorize(permissionClient, "users", "read"), userHandler.ReadUser)
-		auth.PATCH("/users/:id", middleware.Authorize(permissionClient, "users", "write"), userHandler.Update)
-		auth.DELETE("/users/:id", middleware.Authorize(permissionClient, "users", "delete"), userHandler.Delete)
+		auth.GET("/users", authorize("users", "read"), userHandler.ReadUsers)
+		auth.GET("/users/:id", authorize("users", "read"), userHandler.ReadUser)
+		auth.PATCH("/users/:id", authorize("users", "write"), userHandler.Update)
+		auth.DELETE("/users/:id", authorize("users", "delete"), userHandler.Delete)
+		auth.POST("/switch-org", authHandler.SwitchOrganization)
+		auth.GET("/orgs", orgHandler.ListOrganizations)
+		auth.POST("/orgs", authorize("orgs", "create"), orgHandler.CreateOrganization)
+		auth.GET("/orgs/:id", authorize("orgs", "read"), orgHandler.ReadOrganization)
+		auth.GET("/orgs/:id/members", authorize("orgs", "read"), orgHandler.ListMembers)
+		auth.POST("/orgs/:id/members", authorize("orgs", "write"), orgHandler.AddMember)
+		auth.PATCH("/orgs/:id/members/:user_id", authorize("orgs", "write"), orgHandler.UpdateMember)
+		auth.DELETE("/orgs/:id/members/:user_id", authorize("orgs", "write"), orgHandler.RemoveMember)
 	}
 
+	admin := r.Group("/admin/", middleware.AuthMiddleware(tokenService))
+	{
+		admin.GET("/roles/:id/members", authorize("roles", "read"), roleHandler.ListMembers)
+		admin.POST("/roles/:id/members", authorize("roles", "write"), roleHandler.AssignMember)
+		admin.DELETE("/roles/:id/members/:user_id", authorize("roles", "write"), roleHandler.UnassignMember)
+		admin.GET("/roles", authorize("policies", "read"), roleHandler.ListRoles)
+		admin.GET("/roles/:id", authorize("policies", "read"), roleHandler.ReadRole)
+		admin.GET("/policies", authorize("policies", "read"), policyHandler.ListPolicies)
+		admin.POST("/policies", authorize("policies", "write"), policyHandler.CreatePolicy)
+		admin.PUT("/policies", authorize("policies", "write"), policyHandler.ReplacePolicies)
+		admin.DELETE("/policies", authorize("policies", "write"), policyHandler.DeletePolicy)
+		admin.GET("/policies/history", authorize("policies", "read"), policyHandler.ListPolicyChanges)
+		admin.POST("/policies/revert", authorize("policies", "write"), policyHandler.RevertPolicies)
+	}
+
+	// GATEWAY
+	if o.gateway != nil {
+		gw, err := newGateway(o.gateway)
+		if err != nil {
+			fmt.Printf("⚠️ SetupRoutes: %v", err)
+			panic("Error load gateway rules")
+		}
+		gw.Register(r, middleware.AuthMiddleware(tokenService), func(obj, act, idParam string) gin.HandlerFunc {
+			return middleware.Authorize(permissionClient, obj, act, failPolicy, middleware.WithResourceParam(idParam))
+		})
+		gw.OpenAPI(doc)
+	}
+
+	r.GET("/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, doc)
+	})
+	r.GET("/docs", openapi.SwaggerUI(doc.Info.Title, "/openapi.json"))
+
 	r.GET("/auth/public-key", commonHandler.GetPublicKey)
 	r.GET("/auth/health", func(c *gin.Context) {
 		if _, err := db.DB(); err != nil {
@@ -128,7 +211,74 @@ func SetupRoutes(db *gorm.DB, mode string, permissionClient auth.PermissionClien
 			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "down", "error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"status": "up"})
+		resp := gin.H{"status": "up"}
+		if cached, ok := permissionClient.(cacheStatsReporter); ok {
+			resp["permission_cache"] = cached.Stats()
+		}
+		if o.policyOutbox != nil {
+			if stats, err := o.policyOutbox.Stats(c); err == nil {
+				resp["policy_outbox"] = stats
+			} else {
+				log.Println("⚠️ Policy outbox stats: ", err.Error())
+			}
+		}
+		c.JSON(http.StatusOK, resp)
 	})
 	return r
 }
+
+func newGateway(conn grpc.ClientConnInterface) (*gateway.Gateway, error) {
+	rules, err := gateway.ParseRules(config.GatewayRules)
+	if err != nil {
+		return nil, err
+	}
+	return gateway.New(conn, rules)
+}
+
+// newFailPolicy decides per action whether requests go through while the
+// permission service is down.
+func newFailPolicy(cfg config.PermissionClient) middleware.FailPolicy {
+	p := middleware.FailPolicy{
+		Default: middleware.FailMode(cfg.FailMode),
+		Actions: make(map[string]middleware.FailMode),
+	}
+	for _, act := range cfg.FailOpen {
+		p.Actions[act] = middleware.FailOpen
+	}
+	for _, act := range cfg.FailClosed {
+		p.Actions[act] = middleware.FailClosed
+	}
+	return p
+}
+
+// seedPolicies adds the baseline policies of config/policies.yaml that are
+// missing. Nothing is removed; `policy sync` does that, `policy import` adds
+// only, like here.
+func seedPolicies(permissionClient auth.PermissionClient) {
+	rules, err := auth.ParsePolicies(config.BaselinePolicies, auth.PolicyFormatYAML)
+	if err != nil {
+		log.Printf("⚠️ Failed to read baseline policies: %v", err)
+		return
+	}
+	for _, rule := range rules {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+
+		var err error
+		switch rule[0] {
+		case "p":
+			_, err = permissionClient.AddPolicyInDomain(ctx, rule[1], rule[2], rule[3], rule[4])
+		case "p2":
+			_, err = permissionClient.AddConditionalPolicy(ctx, rule[1], rule[2], rule[3], rule[4], rule[5])
+		case "g":
+			_, err = permissionClient.AssignRoleInDomain(ctx, rule[1], rule[2], rule[3])
+		}
+
+		cancel()
+
+		if err != nil {
+			log.Printf("⚠️ Failed to add %s: %v", strings.Join(rule, ", "), err)
+		} else {
+			log.Printf("✅ Successfully added %s", strings.Join(rule, ", "))
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+)
+
+// GlobalDomain is auth.GlobalDomain, the Casbin domain for platform-wide
+// roles and policies.
+const GlobalDomain = auth.GlobalDomain
+
+const (
+	OrgRoleAdmin  = "org_admin"
+	OrgRoleMember = "org_member"
+)
+
+type Organization struct {
+	BaseModel
+	Name string `gorm:"not null" json:"name"`
+	Slug string `gorm:"uniqueIndex;not null" json:"slug"`
+}
+
+type Membership struct {
+	BaseModel
+	UserID         uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_membership_org_user" json:"user_id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_membership_org_user" json:"organization_id"`
+	Role           string    `gorm:"not null" json:"role"`
+}
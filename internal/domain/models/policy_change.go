@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Policy change operations.
+const (
+	PolicyChangeAdd    = "add"
+	PolicyChangeRemove = "remove"
+)
+
+// PolicyChange records one rule added to or removed from the policy set. The
+// ID is the revision: changes are numbered in the order they took effect.
+// Rows are only ever appended.
+type PolicyChange struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement"`
+	CreatedAt time.Time `gorm:"not null;index"`
+	// Service is the authenticated caller of the permission service and Actor
+	// the user it acted for, when it said so.
+	Service string `gorm:"not null;index"`
+	Actor   string `gorm:"index"`
+	Op      string `gorm:"not null"`
+	PType   string `gorm:"column:ptype;not null"`
+	// Subject and Domain are copied out of the rule for filtering.
+	Subject string `gorm:"index"`
+	Domain  string `gorm:"index"`
+	// Before is the rule removed and After the rule added; the other is empty.
+	Before []string `gorm:"serializer:json"`
+	After  []string `gorm:"serializer:json"`
+}
+
+// PolicyChangeFilter selects policy changes. Zero fields match everything.
+type PolicyChangeFilter struct {
+	Actor   string
+	Service string
+	Subject string
+	Domain  string
+	Since   time.Time
+	Until   time.Time
+	// BeforeRevision pages backwards: only older changes are returned.
+	BeforeRevision uint64
+}
+
+// Rule returns the rule the change added or removed.
+func (c PolicyChange) Rule() []string {
+	if c.Op == PolicyChangeRemove {
+		return c.Before
+	}
+	return c.After
+}
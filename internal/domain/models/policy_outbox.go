@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PolicyOperationRevokeRoles revokes every role a subject holds in a
+// domain, whichever were assigned to it.
+const PolicyOperationRevokeRoles = "revoke_roles"
+
+// PolicyOperation is a policy change waiting in the outbox. It is written in
+// the same transaction as the data it belongs to, e.g. a new user, and
+// applied to the permission service afterwards until it succeeds.
+type PolicyOperation struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement"`
+	CreatedAt time.Time `gorm:"not null"`
+	// Op is PolicyChangeAdd or PolicyChangeRemove, and Rule has the values of
+	// a PType rule; or PolicyOperationRevokeRoles, and Rule is the subject and
+	// the domain to revoke its roles in.
+	Op    string   `gorm:"not null"`
+	PType string   `gorm:"column:ptype;not null"`
+	Rule  []string `gorm:"serializer:json;not null"`
+	// Subject is Rule[0]. Operations on a subject are applied in order.
+	Subject string `gorm:"not null;index"`
+	// Actor is the user the change is made for, recorded in the history.
+	Actor         string
+	Attempts      int       `gorm:"not null;default:0"`
+	NextAttemptAt time.Time `gorm:"not null;index"`
+	LastError     string
+	// FailedAt is set when the operation can never be applied, e.g. its rule
+	// is malformed. Failed operations stay for inspection and are skipped.
+	FailedAt *time.Time `gorm:"index"`
+}
+
+func (PolicyOperation) TableName() string {
+	return "policy_outbox"
+}
+
+func (op *PolicyOperation) BeforeCreate(tx *gorm.DB) error {
+	now := time.Now()
+	op.CreatedAt = now
+	if op.NextAttemptAt.IsZero() {
+		op.NextAttemptAt = now
+	}
+	if op.Subject == "" && len(op.Rule) > 0 {
+		op.Subject = op.Rule[0]
+	}
+	return nil
+}
+
+// PolicyOutboxStats describes the operations still waiting in the outbox.
+type PolicyOutboxStats struct {
+	Pending int64 `json:"pending"`
+	// Failing operations have been tried at least once.
+	Failing int64 `json:"failing"`
+	// Failed operations are given up on and need an operator.
+	Failed    int64      `json:"failed"`
+	OldestAt  *time.Time `json:"oldest_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
@@ -14,11 +14,26 @@ type TokenPair struct {
 type AccessClaims struct {
 	UserID string `json:"user_id"`
 	Role   string `json:"role"`
+	OrgID  string `json:"org_id,omitempty"`
+	// TokenVersion is the user's token version at issue; tokens issued
+	// before it changed are revoked.
+	TokenVersion string `json:"token_version,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type RefreshClaims struct {
 	UserID       string `json:"user_id"`
 	TokenVersion string `json:"token_version"`
+	OrgID        string `json:"org_id,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ServiceAudience is the audience of service tokens. User access tokens carry
+// none, so they are never accepted in place of a service identity.
+const ServiceAudience = "permission-service"
+
+// ServiceClaims identify a backend service calling the permission gRPC API.
+// The service name is the token subject.
+type ServiceClaims struct {
 	jwt.RegisteredClaims
 }
@@ -7,12 +7,24 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// SelfSubject is the policy subject that stands for the resource owner: a
+// "self" policy on users/:id matches when :id equals the requesting user.
+const SelfSubject = "self"
+
 type User struct {
 	BaseModel
 	Email        string `gorm:"uniqueIndex;not null" json:"email"`
 	PasswordHash string `gorm:"not null" json:"-"`
 	Role         string `gorm:"" json:"role"`
 	TokenVersion string `gorm:"default:'v1'"`
+	// Disabled users can't sign in; their tokens are revoked when they are
+	// disabled.
+	Disabled bool `gorm:"not null;default:false" json:"disabled"`
 }
 
 func (User) TableName() string {
@@ -0,0 +1,117 @@
+// Package lifecycle starts the servers of the process and stops them, and the
+// resources they use, in order when the process is asked to stop or one of
+// them fails.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Component is a part of the process. Serve, when set, blocks until the
+// component stops; Shutdown stops it within the deadline of ctx.
+type Component struct {
+	Name     string
+	Serve    func() error
+	Shutdown func(ctx context.Context) error
+}
+
+// Manager serves components and shuts them down in the reverse of the order
+// they were added, so a server stops before what it depends on.
+type Manager struct {
+	components []Component
+	timeout    time.Duration
+	// stopped gets the error of the first component to stop serving.
+	stopped chan error
+}
+
+// NewManager gives shutdown timeout in total.
+func NewManager(timeout time.Duration) *Manager {
+	return &Manager{timeout: timeout, stopped: make(chan error, 1)}
+}
+
+// Start adds a component and starts serving it, so the components added next
+// can use it while they are set up.
+func (m *Manager) Start(c Component) {
+	m.components = append(m.components, c)
+	if c.Serve == nil {
+		return
+	}
+	go func() {
+		slog.Info("🚀 Starting", "component", c.Name)
+		err := c.Serve()
+		if err == nil {
+			err = fmt.Errorf("%s stopped", c.Name)
+		} else {
+			err = fmt.Errorf("%s: %w", c.Name, err)
+		}
+		select {
+		case m.stopped <- err:
+		default:
+		}
+	}()
+}
+
+// OnShutdown adds a resource to release on shutdown.
+func (m *Manager) OnShutdown(name string, shutdown func(ctx context.Context) error) {
+	m.Start(Component{Name: name, Shutdown: shutdown})
+}
+
+// Wait blocks until ctx is done or a component stops serving, then shuts
+// all down. It returns the error a component stopped with, joined with the
+// shutdown errors.
+func (m *Manager) Wait(ctx context.Context) error {
+	var serveErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("🟡 Shutting down")
+	case serveErr = <-m.stopped:
+		slog.Error("🔴 Component stopped, shutting down", "error", serveErr)
+	}
+	return errors.Join(serveErr, m.Shutdown())
+}
+
+// Shutdown stops the components in reverse order within the timeout.
+func (m *Manager) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(m.components) - 1; i >= 0; i-- {
+		c := m.components[i]
+		if c.Shutdown == nil {
+			continue
+		}
+		if err := c.Shutdown(ctx); err != nil {
+			slog.Error("🔴 Shutdown failed", "component", c.Name, "error", err)
+			errs = append(errs, fmt.Errorf("shutdown %s: %w", c.Name, err))
+			continue
+		}
+		slog.Info("🟢 Stopped", "component", c.Name)
+	}
+	return errors.Join(errs...)
+}
+
+// GracefulStop waits for in-flight calls of a gRPC server, or anything with
+// the same methods, to finish and forces it to stop when ctx is done first.
+func GracefulStop(ctx context.Context, s interface {
+	GracefulStop()
+	Stop()
+}) error {
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.Stop()
+		<-done
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,64 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_ShutdownInReverseOrder(t *testing.T) {
+	m := NewManager(time.Second)
+	var order []string
+	stop := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+	m.OnShutdown("database", stop("database", nil))
+	m.OnShutdown("cache", stop("cache", errors.New("flush failed")))
+	served := make(chan struct{})
+	m.Start(Component{
+		Name:     "http",
+		Serve:    func() error { <-served; return nil },
+		Shutdown: stop("http", nil),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := m.Wait(ctx)
+	close(served)
+	assert.Equal(t, []string{"http", "cache", "database"}, order)
+	assert.ErrorContains(t, err, "shutdown cache: flush failed")
+}
+
+func TestManager_ServeErrorShutsDown(t *testing.T) {
+	m := NewManager(time.Second)
+	shutdown := false
+	m.OnShutdown("database", func(context.Context) error {
+		shutdown = true
+		return nil
+	})
+	m.Start(Component{Name: "grpc", Serve: func() error { return errors.New("address in use") }})
+
+	err := m.Wait(context.Background())
+	assert.ErrorContains(t, err, "grpc: address in use")
+	assert.True(t, shutdown)
+}
+
+type stuckServer struct {
+	stop chan struct{}
+}
+
+func (s *stuckServer) GracefulStop() { <-s.stop }
+func (s *stuckServer) Stop()         { close(s.stop) }
+
+func TestGracefulStop_ForcesStopAfterDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := GracefulStop(ctx, &stuckServer{stop: make(chan struct{})})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,268 @@
+package permission
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/mrhumster/web-server-gin/config"
+	authpb "github.com/mrhumster/web-server-gin/gen/go/auth"
+	"github.com/mrhumster/web-server-gin/gen/go/permission"
+	"github.com/mrhumster/web-server-gin/gen/go/user"
+	"github.com/mrhumster/web-server-gin/internal/service"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionalphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceTokenValidator resolves a service JWT to the caller's name.
+type ServiceTokenValidator interface {
+	ValidateServiceToken(token string) (string, error)
+}
+
+// readMethods only evaluate or list policies. Every other method, including
+// ones added later, changes state and needs a policy admin.
+var readMethods = map[string]bool{
+	permission.PermissionService_CheckPermission_FullMethodName:           true,
+	permission.PermissionService_BatchCheckPermission_FullMethodName:      true,
+	permission.PermissionService_ExplainPermission_FullMethodName:         true,
+	permission.PermissionService_ListRoleMembers_FullMethodName:           true,
+	permission.PermissionService_ListPermissionsForSubject_FullMethodName: true,
+	permission.PermissionService_ListSubjectsForResource_FullMethodName:   true,
+	permission.PermissionService_GetImplicitRolesForUser_FullMethodName:   true,
+	permission.PermissionService_GetPolicySnapshot_FullMethodName:         true,
+	permission.PermissionService_WatchPolicies_FullMethodName:             true,
+	permission.PermissionService_ListPolicies_FullMethodName:              true,
+	permission.PermissionService_ListRoles_FullMethodName:                 true,
+	permission.PermissionService_ListPolicyChanges_FullMethodName:         true,
+	user.UserService_GetUser_FullMethodName:                               true,
+	user.UserService_BatchGetUsers_FullMethodName:                         true,
+	user.UserService_ListUsers_FullMethodName:                             true,
+	user.UserService_SearchUsers_FullMethodName:                           true,
+	authpb.AuthService_ValidateToken_FullMethodName:                       true,
+	authpb.AuthService_GetJWKS_FullMethodName:                             true,
+	// Readers may only issue tokens for themselves; the server checks.
+	authpb.AuthService_IssueServiceToken_FullMethodName: true,
+	// Reflection only describes the services.
+	reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName:      true,
+	reflectionalphapb.ServerReflection_ServerReflectionInfo_FullMethodName: true,
+}
+
+// public reports whether method may be called without credentials: health
+// checks, which probes make with none.
+func public(method string) bool {
+	return strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
+
+type callerKey struct{}
+
+// Caller returns the authenticated caller of the current RPC.
+func Caller(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(callerKey{}).(string)
+	return name, ok
+}
+
+// Authorizer decides which callers may use which methods.
+type Authorizer struct {
+	tokens  ServiceTokenValidator
+	admins  []string
+	readers []string
+}
+
+// NewAuthorizer builds an Authorizer. Admins may call every method; readers
+// only the read methods. An empty reader list lets any authenticated caller
+// read.
+func NewAuthorizer(tokens ServiceTokenValidator, admins, readers []string) *Authorizer {
+	return &Authorizer{tokens: tokens, admins: admins, readers: readers}
+}
+
+// UnaryInterceptor authenticates every call but health checks with the verified client
+// certificate or, failing that, a service JWT in the authorization metadata.
+func (a *Authorizer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if public(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		caller, err := a.authenticate(ctx)
+		if err != nil {
+			slog.Warn("gRPC call rejected", "method", info.FullMethod, "error", err)
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		if !a.allowed(caller, info.FullMethod) {
+			slog.Warn("gRPC call denied", "method", info.FullMethod, "caller", caller)
+			return nil, status.Errorf(codes.PermissionDenied, "%s may not call %s", caller, info.FullMethod)
+		}
+		return handler(withCaller(ctx, caller), req)
+	}
+}
+
+// StreamInterceptor applies the same checks as UnaryInterceptor to streaming
+// calls.
+func (a *Authorizer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if public(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx := ss.Context()
+		caller, err := a.authenticate(ctx)
+		if err != nil {
+			slog.Warn("gRPC call rejected", "method", info.FullMethod, "error", err)
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+		if !a.allowed(caller, info.FullMethod) {
+			slog.Warn("gRPC call denied", "method", info.FullMethod, "caller", caller)
+			return status.Errorf(codes.PermissionDenied, "%s may not call %s", caller, info.FullMethod)
+		}
+		return handler(srv, &callerStream{ServerStream: ss, ctx: withCaller(ctx, caller)})
+	}
+}
+
+// withCaller stores the caller in ctx, and attributes the policy changes made
+// with it to the caller and the user the caller says it acts for.
+func withCaller(ctx context.Context, caller string) context.Context {
+	actor := service.Actor{Service: caller, User: actingUser(ctx)}
+	return service.WithActor(context.WithValue(ctx, callerKey{}, caller), actor)
+}
+
+// actingUser returns the user the caller says it acts for, empty when it
+// acts on its own behalf.
+func actingUser(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if users := md.Get(auth.ActorMetadataKey); len(users) > 0 {
+		return users[0]
+	}
+	return ""
+}
+
+// servicePrefix marks policy subjects naming a calling service, e.g.
+// "service:billing".
+const servicePrefix = "service:"
+
+// callSubject returns who a call is checked for in the policies: the user the
+// caller acts for or, when it acts on its own behalf, the caller as
+// "service:<name>". It is empty when neither is known.
+func callSubject(ctx context.Context) string {
+	if actor := actingUser(ctx); actor != "" {
+		return actor
+	}
+	if caller, ok := Caller(ctx); ok && caller != "" {
+		return servicePrefix + caller
+	}
+	return ""
+}
+
+// actingTenant returns the organization the acting user acts in, as the HTTP
+// routes take it from the access token, or auth.GlobalDomain.
+func actingTenant(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if orgs := md.Get(auth.TenantMetadataKey); len(orgs) > 0 && orgs[0] != "" {
+		return orgs[0]
+	}
+	return auth.GlobalDomain
+}
+
+// callerStream carries the authenticated caller in the stream's context.
+type callerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *callerStream) Context() context.Context { return s.ctx }
+
+func (a *Authorizer) authenticate(ctx context.Context) (string, error) {
+	if name := peerCertificateName(ctx); name != "" {
+		return name, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", fmt.Errorf("missing credentials")
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok || a.tokens == nil {
+		return "", fmt.Errorf("unsupported authorization scheme")
+	}
+	name, err := a.tokens.ValidateServiceToken(token)
+	if err != nil {
+		return "", fmt.Errorf("invalid service token: %w", err)
+	}
+	return name, nil
+}
+
+// admin reports whether caller is a policy admin.
+func (a *Authorizer) admin(caller string) bool {
+	return slices.Contains(a.admins, caller)
+}
+
+func (a *Authorizer) allowed(caller, method string) bool {
+	if a.admin(caller) {
+		return true
+	}
+	if !readMethods[method] {
+		return false
+	}
+	return len(a.readers) == 0 || slices.Contains(a.readers, caller)
+}
+
+// peerCertificateName returns the common name, or first DNS name, of a client
+// certificate verified during the TLS handshake.
+func peerCertificateName(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	cert := info.State.VerifiedChains[0][0]
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0]
+	}
+	return ""
+}
+
+// ServerCredentials returns TLS credentials for the gRPC server, or nil when
+// no certificate is configured. With a client CA, clients presenting a
+// certificate must chain to it; clients without one fall back to a JWT.
+func ServerCredentials(cfg config.GRPC) (credentials.TransportCredentials, error) {
+	if cfg.CertFile == "" {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load gRPC server certificate: %w", err)
+	}
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read gRPC client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("gRPC client CA %s has no certificates", cfg.ClientCAFile)
+		}
+		tlsCfg.ClientCAs = pool
+		tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
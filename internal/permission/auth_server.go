@@ -0,0 +1,109 @@
+package permission
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	authpb "github.com/mrhumster/web-server-gin/gen/go/auth"
+	"github.com/mrhumster/web-server-gin/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuthGRPCServer serves service.TokenService to other backend services.
+type AuthGRPCServer struct {
+	authpb.UnimplementedAuthServiceServer
+	tokens     *service.TokenService
+	users      *service.UserService
+	authorizer *Authorizer
+}
+
+// NewAuthGRPCServer checks revocation against users and lets the policy
+// admins of authorizer issue tokens for other services.
+func NewAuthGRPCServer(tokens *service.TokenService, users *service.UserService, authorizer *Authorizer) *AuthGRPCServer {
+	return &AuthGRPCServer{tokens: tokens, users: users, authorizer: authorizer}
+}
+
+func (s *AuthGRPCServer) ValidateToken(ctx context.Context, req *authpb.ValidateTokenRequest) (*authpb.ValidateTokenResponse, error) {
+	if violations := required("", "token", req.GetToken()); len(violations) > 0 {
+		return nil, invalidArgument("validate token", violations...)
+	}
+	var claims *authpb.Claims
+	switch req.GetType() {
+	case authpb.ValidateTokenRequest_REFRESH:
+		c, err := s.tokens.ValidateRefreshToken(req.GetToken())
+		if err != nil {
+			return &authpb.ValidateTokenResponse{Error: err.Error()}, nil
+		}
+		claims = claimsMessage(c.RegisteredClaims)
+		claims.UserId, claims.OrgId, claims.TokenVersion = c.UserID, c.OrgID, c.TokenVersion
+	default:
+		c, err := s.tokens.ValidateAccessToken(req.GetToken())
+		if err != nil {
+			return &authpb.ValidateTokenResponse{Error: err.Error()}, nil
+		}
+		claims = claimsMessage(c.RegisteredClaims)
+		claims.UserId, claims.Role, claims.OrgId, claims.TokenVersion = c.UserID, c.Role, c.OrgID, c.TokenVersion
+	}
+
+	userID, err := uuid.Parse(claims.GetUserId())
+	if err != nil {
+		return &authpb.ValidateTokenResponse{Error: "invalid user id in claims", Claims: claims}, nil
+	}
+	reason, err := s.users.TokenRevocation(ctx, userID, claims.GetTokenVersion())
+	if err != nil {
+		return nil, statusError(ctx, "validate token", err)
+	}
+	return &authpb.ValidateTokenResponse{
+		Valid:            reason == "",
+		Revoked:          reason != "",
+		RevocationReason: reason,
+		Claims:           claims,
+	}, nil
+}
+
+func claimsMessage(c jwt.RegisteredClaims) *authpb.Claims {
+	return &authpb.Claims{
+		Issuer:    c.Issuer,
+		IssuedAt:  numericDate(c.IssuedAt),
+		ExpiresAt: numericDate(c.ExpiresAt),
+	}
+}
+
+func numericDate(d *jwt.NumericDate) string {
+	if d == nil {
+		return ""
+	}
+	return d.Format(time.RFC3339)
+}
+
+func (s *AuthGRPCServer) IssueServiceToken(ctx context.Context, req *authpb.IssueServiceTokenRequest) (*authpb.IssueServiceTokenResponse, error) {
+	caller, _ := Caller(ctx)
+	name := req.GetServiceName()
+	if name == "" {
+		name = caller
+	}
+	if violations := required("", "service_name", name); len(violations) > 0 {
+		return nil, invalidArgument("issue service token", violations...)
+	}
+	// A token for the caller grants it nothing new; one for another service
+	// lets the holder act as that service.
+	if name != caller && !s.authorizer.admin(caller) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s may not issue tokens for %s", caller, name)
+	}
+	token, expiresAt, err := s.tokens.GenerateServiceToken(name)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "issue service token: %v", err)
+	}
+	return &authpb.IssueServiceTokenResponse{Token: token, ExpiresAt: expiresAt.Format(time.RFC3339)}, nil
+}
+
+func (s *AuthGRPCServer) GetJWKS(context.Context, *authpb.GetJWKSRequest) (*authpb.JWKS, error) {
+	resp := &authpb.JWKS{}
+	for _, k := range s.tokens.JWKS() {
+		resp.Keys = append(resp.Keys, &authpb.JSONWebKey{Kty: k.Kty, Kid: k.Kid, Use: k.Use, Alg: k.Alg, N: k.N, E: k.E})
+	}
+	return resp, nil
+}
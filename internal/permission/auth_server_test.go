@@ -0,0 +1,99 @@
+package permission
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/config"
+	authpb "github.com/mrhumster/web-server-gin/gen/go/auth"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	repomock "github.com/mrhumster/web-server-gin/internal/repository/mock"
+	"github.com/mrhumster/web-server-gin/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestAuthServer(t *testing.T) (*AuthGRPCServer, *service.TokenService, *repomock.MockUserRepository) {
+	t.Helper()
+	cfg, err := config.TestConfig()
+	require.NoError(t, err)
+	tokens, err := service.NewTokenService(&cfg.JWT)
+	require.NoError(t, err)
+	repo := repomock.NewMockUserRepository(gomock.NewController(t))
+	authorizer := NewAuthorizer(tokens, []string{"web-server-gin"}, nil)
+	return NewAuthGRPCServer(tokens, service.NewUserService(repo, nil), authorizer), tokens, repo
+}
+
+func TestAuthGRPCServer_ValidateToken(t *testing.T) {
+	s, tokens, repo := newTestAuthServer(t)
+	ctx := context.Background()
+	alice := models.User{Role: models.RoleMember, TokenVersion: "v1"}
+	alice.ID = uuid.New()
+	pair, err := tokens.GenerateToken(&alice)
+	require.NoError(t, err)
+
+	repo.EXPECT().ReadUserByID(gomock.Any(), alice.ID).Return(&alice, nil).Times(2)
+	resp, err := s.ValidateToken(ctx, &authpb.ValidateTokenRequest{Token: pair.AccessToken})
+	require.NoError(t, err)
+	assert.True(t, resp.GetValid())
+	assert.False(t, resp.GetRevoked())
+	assert.Equal(t, alice.ID.String(), resp.GetClaims().GetUserId())
+	assert.Equal(t, models.RoleMember, resp.GetClaims().GetRole())
+	assert.NotEmpty(t, resp.GetClaims().GetExpiresAt())
+
+	resp, err = s.ValidateToken(ctx, &authpb.ValidateTokenRequest{Token: pair.RefreshToken, Type: authpb.ValidateTokenRequest_REFRESH})
+	require.NoError(t, err)
+	assert.True(t, resp.GetValid())
+
+	// Signing out everywhere changes the version and revokes both tokens.
+	signedOut := alice
+	signedOut.TokenVersion = "v2"
+	repo.EXPECT().ReadUserByID(gomock.Any(), alice.ID).Return(&signedOut, nil)
+	resp, err = s.ValidateToken(ctx, &authpb.ValidateTokenRequest{Token: pair.AccessToken})
+	require.NoError(t, err)
+	assert.False(t, resp.GetValid())
+	assert.True(t, resp.GetRevoked())
+	assert.Equal(t, "token version changed", resp.GetRevocationReason())
+
+	// A refresh token is not an access token.
+	resp, err = s.ValidateToken(ctx, &authpb.ValidateTokenRequest{Token: pair.RefreshToken})
+	require.NoError(t, err)
+	assert.False(t, resp.GetValid())
+	assert.NotEmpty(t, resp.GetError())
+
+	_, err = s.ValidateToken(ctx, &authpb.ValidateTokenRequest{})
+	assert.Equal(t, []string{"token"}, fieldViolations(t, err))
+}
+
+func TestAuthGRPCServer_IssueServiceToken(t *testing.T) {
+	s, tokens, _ := newTestAuthServer(t)
+
+	resp, err := s.IssueServiceToken(withCaller(context.Background(), "billing"), &authpb.IssueServiceTokenRequest{})
+	require.NoError(t, err)
+	name, err := tokens.ValidateServiceToken(resp.GetToken())
+	require.NoError(t, err)
+	assert.Equal(t, "billing", name)
+
+	_, err = s.IssueServiceToken(withCaller(context.Background(), "billing"), &authpb.IssueServiceTokenRequest{ServiceName: "web-server-gin"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	resp, err = s.IssueServiceToken(withCaller(context.Background(), "web-server-gin"), &authpb.IssueServiceTokenRequest{ServiceName: "reporting"})
+	require.NoError(t, err)
+	name, err = tokens.ValidateServiceToken(resp.GetToken())
+	require.NoError(t, err)
+	assert.Equal(t, "reporting", name)
+}
+
+func TestAuthGRPCServer_GetJWKS(t *testing.T) {
+	s, tokens, _ := newTestAuthServer(t)
+
+	resp, err := s.GetJWKS(context.Background(), &authpb.GetJWKSRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetKeys(), 1)
+	assert.Equal(t, tokens.JWKS()[0].Kid, resp.GetKeys()[0].GetKid())
+	assert.Equal(t, "sig", resp.GetKeys()[0].GetUse())
+}
@@ -0,0 +1,118 @@
+package permission
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"testing"
+
+	authpb "github.com/mrhumster/web-server-gin/gen/go/auth"
+	"github.com/mrhumster/web-server-gin/gen/go/permission"
+	userpb "github.com/mrhumster/web-server-gin/gen/go/user"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+)
+
+type fakeTokens map[string]string
+
+func (f fakeTokens) ValidateServiceToken(token string) (string, error) {
+	if name, ok := f[token]; ok {
+		return name, nil
+	}
+	return "", errors.New("bad token")
+}
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func withClientCert(cn string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}},
+	})
+}
+
+func TestAuthorizer_UnaryInterceptor(t *testing.T) {
+	a := NewAuthorizer(fakeTokens{"app-token": "web-server-gin", "billing-token": "billing"},
+		[]string{"web-server-gin"}, []string{"billing", "reporting"})
+	interceptor := a.UnaryInterceptor()
+
+	call := func(ctx context.Context, method string) (string, codes.Code) {
+		var caller string
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, _ any) (any, error) {
+			caller, _ = Caller(ctx)
+			return nil, nil
+		})
+		return caller, status.Code(err)
+	}
+
+	check := permission.PermissionService_CheckPermission_FullMethodName
+	add := permission.PermissionService_AddPolicy_FullMethodName
+
+	cases := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		caller string
+		code   codes.Code
+	}{
+		{"no credentials", context.Background(), check, "", codes.Unauthenticated},
+		{"bad token", withToken("forged"), check, "", codes.Unauthenticated},
+		{"admin mutates", withToken("app-token"), add, "web-server-gin", codes.OK},
+		{"reader checks", withToken("billing-token"), check, "billing", codes.OK},
+		{"reader can't mutate", withToken("billing-token"), add, "", codes.PermissionDenied},
+		{"client certificate", withClientCert("reporting"), check, "reporting", codes.OK},
+		{"unknown certificate", withClientCert("intruder"), check, "", codes.PermissionDenied},
+		{"health without credentials", context.Background(), healthpb.Health_Check_FullMethodName, "", codes.OK},
+		{"reader gets users", withToken("billing-token"), userpb.UserService_GetUser_FullMethodName, "billing", codes.OK},
+		{"reader can't create users", withToken("billing-token"), userpb.UserService_CreateUser_FullMethodName, "", codes.PermissionDenied},
+		{"reader validates tokens", withToken("billing-token"), authpb.AuthService_ValidateToken_FullMethodName, "billing", codes.OK},
+		{"reader reflects", withToken("billing-token"), reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName, "billing", codes.OK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			caller, code := call(tc.ctx, tc.method)
+			assert.Equal(t, tc.code, code)
+			assert.Equal(t, tc.caller, caller)
+		})
+	}
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s fakeStream) Context() context.Context { return s.ctx }
+
+func TestAuthorizer_StreamInterceptor(t *testing.T) {
+	a := NewAuthorizer(fakeTokens{"billing-token": "billing"}, []string{"web-server-gin"}, nil)
+	interceptor := a.StreamInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: permission.PermissionService_WatchPolicies_FullMethodName}
+
+	var caller string
+	err := interceptor(nil, fakeStream{ctx: withToken("billing-token")}, info, func(_ any, ss grpc.ServerStream) error {
+		caller, _ = Caller(ss.Context())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "billing", caller)
+
+	err = interceptor(nil, fakeStream{ctx: context.Background()}, info, func(any, grpc.ServerStream) error {
+		t.Fatal("handler called without credentials")
+		return nil
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,100 @@
+package permission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/mrhumster/web-server-gin/internal/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// errorDomain identifies this service in ErrorInfo details.
+const errorDomain = "permission.service"
+
+// fieldViolation is a request field that failed validation.
+type fieldViolation struct {
+	field       string
+	description string
+}
+
+// required returns a violation for every named field whose value is empty.
+// Fields are passed as name/value pairs.
+func required(prefix string, fields ...string) []fieldViolation {
+	var violations []fieldViolation
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i+1] == "" {
+			violations = append(violations, fieldViolation{
+				field:       prefix + fields[i],
+				description: "must not be empty",
+			})
+		}
+	}
+	return violations
+}
+
+// invalidArgument builds an InvalidArgument status carrying a BadRequest
+// detail with one entry per violation.
+func invalidArgument(op string, violations ...fieldViolation) error {
+	st := status.New(codes.InvalidArgument, fmt.Sprintf("%s: invalid request", op))
+	br := &errdetails.BadRequest{}
+	for _, v := range violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.field,
+			Description: v.description,
+		})
+	}
+	if detailed, err := st.WithDetails(br); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// statusError maps an error returned by the permission service onto a gRPC
+// status. Anything not recognised as a client mistake is Internal, logged here
+// and reported without its details.
+func statusError(ctx context.Context, op string, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+
+	code, reason := codes.Internal, "ENFORCER_FAILURE"
+	switch {
+	case errors.Is(err, service.ErrInvalidCondition):
+		code, reason = codes.InvalidArgument, "INVALID_CONDITION"
+	case errors.Is(err, errInvalidPageToken):
+		code, reason = codes.InvalidArgument, "INVALID_PAGE_TOKEN"
+	case errors.Is(err, service.ErrPolicyOutsideFilter):
+		code, reason = codes.InvalidArgument, "POLICY_OUTSIDE_FILTER"
+	case errors.Is(err, service.ErrInvalidPolicyRule):
+		code, reason = codes.InvalidArgument, "INVALID_POLICY_RULE"
+	case errors.Is(err, service.ErrUnknownRevision):
+		code, reason = codes.InvalidArgument, "UNKNOWN_REVISION"
+	case errors.Is(err, service.ErrHistoryDisabled):
+		code, reason = codes.FailedPrecondition, "HISTORY_DISABLED"
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		code, reason = codes.NotFound, "USER_NOT_FOUND"
+	case errors.Is(err, service.ErrUserAlreadyExists):
+		code, reason = codes.AlreadyExists, "USER_EXISTS"
+	}
+	msg := fmt.Sprintf("%s: %s", op, err)
+	if code == codes.Internal {
+		// Internal errors can name tables, hosts or queries; the caller only
+		// learns that the call failed.
+		slog.Error(op, "error", err)
+		msg = fmt.Sprintf("%s: internal error", op)
+	}
+
+	st := status.New(code, msg)
+	if detailed, derr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: errorDomain,
+	}); derr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
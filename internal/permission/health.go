@@ -0,0 +1,106 @@
+package permission
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/mrhumster/web-server-gin/gen/go/permission"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HealthCheck is a dependency the permission service needs to serve, like
+// the database or the policy watcher.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+const (
+	healthInterval     = 10 * time.Second
+	healthCheckTimeout = 3 * time.Second
+)
+
+// HealthMonitor runs the health checks periodically and reports the
+// permission service, and the server as a whole, as serving while all pass.
+type HealthMonitor struct {
+	server *health.Server
+	checks []HealthCheck
+
+	mu     sync.Mutex
+	failed map[string]error
+}
+
+func NewHealthMonitor(server *health.Server, checks ...HealthCheck) *HealthMonitor {
+	m := &HealthMonitor{server: server, checks: checks, failed: make(map[string]error)}
+	m.set(healthpb.HealthCheckResponse_NOT_SERVING)
+	return m
+}
+
+// Run checks right away and then every interval until ctx is done.
+func (m *HealthMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(healthInterval)
+	defer ticker.Stop()
+	for {
+		m.CheckNow(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckNow runs every check once and updates the status. It reports whether
+// all passed.
+func (m *HealthMonitor) CheckNow(ctx context.Context) bool {
+	healthy := true
+	for _, c := range m.checks {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := c.Check(checkCtx)
+		cancel()
+		m.record(c.Name, err)
+		if err != nil {
+			healthy = false
+		}
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	if healthy {
+		m.set(healthpb.HealthCheckResponse_SERVING)
+	} else {
+		m.set(healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return healthy
+}
+
+// record logs a check that started or stopped failing.
+func (m *HealthMonitor) record(name string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, wasFailing := m.failed[name]
+	switch {
+	case err != nil && !wasFailing:
+		slog.Error("Health check failed", "check", name, "error", err)
+		m.failed[name] = err
+	case err == nil && wasFailing:
+		slog.Info("Health check recovered", "check", name)
+		delete(m.failed, name)
+	case err != nil:
+		m.failed[name] = err
+	}
+}
+
+// Shutdown reports the server as not serving for good, so probes and load
+// balancers stop sending calls before it stops.
+func (m *HealthMonitor) Shutdown() {
+	m.server.Shutdown()
+}
+
+func (m *HealthMonitor) set(status healthpb.HealthCheckResponse_ServingStatus) {
+	m.server.SetServingStatus("", status)
+	m.server.SetServingStatus(permission.PermissionService_ServiceDesc.ServiceName, status)
+}
@@ -0,0 +1,45 @@
+package permission
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mrhumster/web-server-gin/gen/go/permission"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthMonitor(t *testing.T) {
+	server := health.NewServer()
+	var dbErr error
+	m := NewHealthMonitor(server,
+		HealthCheck{Name: "database", Check: func(context.Context) error { return dbErr }},
+		HealthCheck{Name: "enforcer", Check: func(context.Context) error { return nil }},
+	)
+	status := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := server.Check(t.Context(), &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		return resp.GetStatus()
+	}
+	service := permission.PermissionService_ServiceDesc.ServiceName
+
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(""), "not serving before the first check")
+
+	assert.True(t, m.CheckNow(t.Context()))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status(""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status(service))
+
+	dbErr = errors.New("connection refused")
+	assert.False(t, m.CheckNow(t.Context()))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(service))
+
+	dbErr = nil
+	assert.True(t, m.CheckNow(t.Context()))
+	m.Shutdown()
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(""), "shutdown is final")
+	m.CheckNow(t.Context())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(""))
+}
@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 
+	"github.com/mrhumster/web-server-gin/pkg/auth"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -30,6 +31,121 @@ func (m *PermissionClientMock) AddPolicyIfNotExists(ctx context.Context, userID,
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *PermissionClientMock) AssignRole(ctx context.Context, userID, role string) (bool, error) {
+	args := m.Called(ctx, userID, role)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *PermissionClientMock) UnassignRole(ctx context.Context, userID, role string) (bool, error) {
+	args := m.Called(ctx, userID, role)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *PermissionClientMock) GetRoleMembers(ctx context.Context, role string) ([]string, error) {
+	args := m.Called(ctx, role)
+	members, _ := args.Get(0).([]string)
+	return members, args.Error(1)
+}
+
+func (m *PermissionClientMock) CheckPermissionInDomain(ctx context.Context, userID, domain, resource, action string) (bool, error) {
+	args := m.Called(ctx, userID, domain, resource, action)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *PermissionClientMock) AddPolicyInDomain(ctx context.Context, userID, domain, resource, action string) (bool, error) {
+	args := m.Called(ctx, userID, domain, resource, action)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *PermissionClientMock) AssignRoleInDomain(ctx context.Context, userID, role, domain string) (bool, error) {
+	args := m.Called(ctx, userID, role, domain)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *PermissionClientMock) UnassignRoleInDomain(ctx context.Context, userID, role, domain string) (bool, error) {
+	args := m.Called(ctx, userID, role, domain)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *PermissionClientMock) Check(ctx context.Context, req auth.CheckRequest) (bool, error) {
+	args := m.Called(ctx, req)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *PermissionClientMock) BatchCheckPermission(ctx context.Context, checks []auth.CheckRequest) ([]bool, error) {
+	args := m.Called(ctx, checks)
+	allowed, _ := args.Get(0).([]bool)
+	return allowed, args.Error(1)
+}
+
+func (m *PermissionClientMock) ExplainPermission(ctx context.Context, req auth.CheckRequest) (*auth.Explanation, error) {
+	args := m.Called(ctx, req)
+	ex, _ := args.Get(0).(*auth.Explanation)
+	return ex, args.Error(1)
+}
+
+func (m *PermissionClientMock) ListPermissionsForSubject(ctx context.Context, subject, domain string, pageSize int32, pageToken string) ([]auth.Permission, string, error) {
+	args := m.Called(ctx, subject, domain, pageSize, pageToken)
+	perms, _ := args.Get(0).([]auth.Permission)
+	return perms, args.String(1), args.Error(2)
+}
+
+func (m *PermissionClientMock) ListSubjectsForResource(ctx context.Context, resource, action, domain string, pageSize int32, pageToken string) ([]string, string, error) {
+	args := m.Called(ctx, resource, action, domain, pageSize, pageToken)
+	subjects, _ := args.Get(0).([]string)
+	return subjects, args.String(1), args.Error(2)
+}
+
+func (m *PermissionClientMock) GetImplicitRolesForUser(ctx context.Context, userID, domain string) ([]string, error) {
+	args := m.Called(ctx, userID, domain)
+	roles, _ := args.Get(0).([]string)
+	return roles, args.Error(1)
+}
+
+func (m *PermissionClientMock) AddConditionalPolicy(ctx context.Context, subject, domain, resource, action, condition string) (bool, error) {
+	args := m.Called(ctx, subject, domain, resource, action, condition)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *PermissionClientMock) RemoveConditionalPolicy(ctx context.Context, subject, domain, resource, action, condition string) (bool, error) {
+	args := m.Called(ctx, subject, domain, resource, action, condition)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *PermissionClientMock) RemovePolicyInDomain(ctx context.Context, subject, domain, resource, action string) (bool, error) {
+	args := m.Called(ctx, subject, domain, resource, action)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *PermissionClientMock) ListPolicies(ctx context.Context, filter auth.PolicyFilter, pageSize int32, pageToken string) ([]auth.Permission, string, error) {
+	args := m.Called(ctx, filter, pageSize, pageToken)
+	perms, _ := args.Get(0).([]auth.Permission)
+	return perms, args.String(1), args.Error(2)
+}
+
+func (m *PermissionClientMock) ReplacePolicies(ctx context.Context, filter auth.PolicyFilter, policies []auth.Permission) (int, int, error) {
+	args := m.Called(ctx, filter, policies)
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func (m *PermissionClientMock) ListRoles(ctx context.Context, domain string) ([]string, error) {
+	args := m.Called(ctx, domain)
+	roles, _ := args.Get(0).([]string)
+	return roles, args.Error(1)
+}
+
+func (m *PermissionClientMock) ListPolicyChanges(ctx context.Context, filter auth.PolicyChangeFilter, pageSize int32, pageToken string) ([]auth.PolicyChange, string, error) {
+	args := m.Called(ctx, filter, pageSize, pageToken)
+	changes, _ := args.Get(0).([]auth.PolicyChange)
+	return changes, args.String(1), args.Error(2)
+}
+
+func (m *PermissionClientMock) RevertPolicies(ctx context.Context, revision uint64, dryRun bool) (*auth.PolicyPlan, error) {
+	args := m.Called(ctx, revision, dryRun)
+	plan, _ := args.Get(0).(*auth.PolicyPlan)
+	return plan, args.Error(1)
+}
+
 func (m *PermissionClientMock) Close() error {
 	args := m.Called()
 	return args.Error(0)
@@ -0,0 +1,24 @@
+package permission
+
+import (
+	"github.com/mrhumster/web-server-gin/config"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// KeepaliveOptions ping idle clients every cfg.KeepaliveTime and drop those
+// that don't answer. Clients may ping as often as auth.KeepaliveTime, even
+// without calls in flight; more often and they are disconnected.
+func KeepaliveOptions(cfg config.GRPC) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    cfg.KeepaliveTime,
+			Timeout: cfg.KeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             auth.KeepaliveTime / 2,
+			PermitWithoutStream: true,
+		}),
+	}
+}
@@ -0,0 +1,80 @@
+package permission
+
+import (
+	"encoding/base64"
+	"errors"
+	"math"
+	"strconv"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+var errInvalidPageToken = errors.New("invalid page token")
+
+// pageToken encodes the position a page ended at, an offset or a policy
+// revision, as an opaque token.
+func pageToken(pos uint64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(pos, 10)))
+}
+
+// readPage decodes the token of the previous page, returning position 0 for
+// the first page, and the page size to use for size.
+func readPage(size int32, token string) (pos uint64, limit int, err error) {
+	if token != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(token)
+		if err != nil {
+			return 0, 0, errInvalidPageToken
+		}
+		pos, err = strconv.ParseUint(string(raw), 10, 64)
+		if err != nil {
+			return 0, 0, errInvalidPageToken
+		}
+	}
+	limit = int(size)
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	return pos, min(limit, maxPageSize), nil
+}
+
+// page returns the [start, end) window of a result of length total and the
+// token for the next page, empty on the last one. Tokens are opaque offsets.
+func page(total int, size int32, token string) (start, end int, next string, err error) {
+	start, limit, err := offsetPage(size, token)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	start = min(start, total)
+	end = min(start+limit, total)
+	if end < total {
+		next = pageToken(uint64(end))
+	}
+	return start, end, next, nil
+}
+
+// offsetPage reads a page token of a result paged in the database, the
+// offset the previous page ended at, and returns it with the page size to
+// use.
+func offsetPage(size int32, token string) (offset, limit int, err error) {
+	pos, limit, err := readPage(size, token)
+	if err != nil {
+		return 0, 0, err
+	}
+	if pos > math.MaxInt32 {
+		return 0, 0, errInvalidPageToken
+	}
+	return int(pos), limit, nil
+}
+
+// revisionPage reads a policy history page token, the revision the previous
+// page ended at, and returns it with the page size to use.
+func revisionPage(size int32, token string) (before uint64, limit int, err error) {
+	before, limit, err = readPage(size, token)
+	if err == nil && token != "" && before == 0 {
+		err = errInvalidPageToken
+	}
+	return before, limit, err
+}
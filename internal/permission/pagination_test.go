@@ -0,0 +1,50 @@
+package permission
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPage(t *testing.T) {
+	start, end, next, err := page(5, 2, "")
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 2}, []int{start, end})
+	require.NotEmpty(t, next)
+
+	start, end, next, err = page(5, 2, next)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 4}, []int{start, end})
+
+	start, end, next, err = page(5, 2, next)
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 5}, []int{start, end})
+	assert.Empty(t, next)
+
+	start, end, next, err = page(5, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 5}, []int{start, end})
+	assert.Empty(t, next)
+
+	_, _, _, err = page(5, 2, "not-a-token")
+	assert.ErrorIs(t, err, errInvalidPageToken)
+}
+
+func TestPageTokens(t *testing.T) {
+	offset, limit, err := offsetPage(0, pageToken(40))
+	require.NoError(t, err)
+	assert.Equal(t, []int{40, defaultPageSize}, []int{offset, limit})
+
+	before, limit, err := revisionPage(5000, pageToken(42))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), before)
+	assert.Equal(t, maxPageSize, limit)
+
+	_, _, err = revisionPage(10, pageToken(0))
+	assert.ErrorIs(t, err, errInvalidPageToken, "no revision comes before 0")
+	_, _, err = offsetPage(10, pageToken(1<<40))
+	assert.ErrorIs(t, err, errInvalidPageToken)
+	_, _, err = offsetPage(10, "LTE")
+	assert.ErrorIs(t, err, errInvalidPageToken, "negative offsets are invalid")
+}
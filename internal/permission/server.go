@@ -5,14 +5,21 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"sync"
+	"time"
 
 	"github.com/mrhumster/web-server-gin/gen/go/permission"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
 	"github.com/mrhumster/web-server-gin/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type PermissionGRPCServer struct {
 	permission.UnimplementedPermissionServiceServer
 	permissionServer *service.PermissionService
+	stopping         chan struct{}
+	stopOnce         sync.Once
 }
 
 func NewPermissionGRPCServer(p *service.PermissionService) *PermissionGRPCServer {
@@ -21,52 +28,494 @@ func NewPermissionGRPCServer(p *service.PermissionService) *PermissionGRPCServer
 	}
 	return &PermissionGRPCServer{
 		permissionServer: p,
+		stopping:         make(chan struct{}),
+	}
+}
+
+// EndStreams ends every WatchPolicies stream, so a graceful stop doesn't wait
+// for them. Clients resume on another replica from their last revision.
+func (s *PermissionGRPCServer) EndStreams() {
+	s.stopOnce.Do(func() { close(s.stopping) })
+}
+
+// domainOrGlobal maps requests without a tenant onto the global domain, so
+// clients that predate organizations keep their platform-wide semantics.
+func domainOrGlobal(domain string) string {
+	if domain == "" {
+		return models.GlobalDomain
+	}
+	return domain
+}
+
+func checkViolations(prefix string, req *permission.CheckPermissionRequest) []fieldViolation {
+	return required(prefix,
+		"user_id", req.GetUserId(),
+		"resource", req.GetResource(),
+		"action", req.GetAction())
+}
+
+func checkAttributes(req *permission.CheckPermissionRequest) service.Attributes {
+	return service.Attributes{
+		Subject:  req.GetSubjectAttributes(),
+		Resource: req.GetResourceAttributes(),
+		Env:      req.GetEnvironment(),
 	}
 }
 
 func (s *PermissionGRPCServer) CheckPermission(ctx context.Context, req *permission.CheckPermissionRequest) (*permission.CheckPermissionResponse, error) {
-	allowed, err := s.permissionServer.CheckPermission(req.GetUserId(), req.GetResource(), req.GetAction())
+	if v := checkViolations("", req); len(v) > 0 {
+		return nil, invalidArgument("check permission", v...)
+	}
+	allowed, err := s.permissionServer.CheckPermission(req.GetUserId(), domainOrGlobal(req.GetDomain()), req.GetResource(), req.GetAction(), checkAttributes(req))
+	if err != nil {
+		return nil, statusError(ctx, "check permission", err)
+	}
 	slog.Info("Check permission: ",
 		"User", req.GetUserId(),
+		"Domain", req.GetDomain(),
 		"Object", req.GetResource(),
 		"Action", req.GetAction(),
 		"Allowed", allowed)
-	if err != nil {
-		slog.Error("Check prmission: ",
-			"error", err,
-		)
-	}
-	return &permission.CheckPermissionResponse{
-		Allowed: allowed,
-		Error:   fmt.Sprintf("Check permission err %s", err),
-	}, nil
+	return &permission.CheckPermissionResponse{Allowed: allowed}, nil
 }
 
 func (s *PermissionGRPCServer) AddPolicy(ctx context.Context, req *permission.AddPolicyRequest) (*permission.AddPolicyResponse, error) {
-	added, err := s.permissionServer.AddPolicy(req.GetPolicy(), req.GetResorce(), req.GetPermission())
+	if v := required("", "policy", req.GetPolicy(), "resorce", req.GetResorce(), "permission", req.GetPermission()); len(v) > 0 {
+		return nil, invalidArgument("add policy", v...)
+	}
+	var (
+		added bool
+		err   error
+	)
+	if req.GetCondition() != "" {
+		added, err = s.permissionServer.AddConditionalPolicy(ctx, req.GetPolicy(), domainOrGlobal(req.GetDomain()), req.GetResorce(), req.GetPermission(), req.GetCondition())
+	} else {
+		added, err = s.permissionServer.AddPolicy(ctx, req.GetPolicy(), domainOrGlobal(req.GetDomain()), req.GetResorce(), req.GetPermission())
+	}
+	if err != nil {
+		return nil, statusError(ctx, "add policy", err)
+	}
 	slog.Info("Add policy: ",
 		"Policy", req.GetPolicy(),
+		"Domain", req.GetDomain(),
 		"Resource", req.GetResorce(),
 		"Permission", req.GetPermission(),
+		"Condition", req.GetCondition(),
 		"Added", added)
-	return &permission.AddPolicyResponse{
-		Added: added,
-		Error: fmt.Sprintf("Add policy err %s", err),
-	}, nil
+	return &permission.AddPolicyResponse{Added: added}, nil
 }
 
 func (s *PermissionGRPCServer) RemovePolicy(ctx context.Context, req *permission.RemovePolicyRequest) (*permission.RemovePolicyResponse, error) {
-	removed, err := s.permissionServer.RemovePolicy(req.GetPolicy(), req.GetResource(), req.GetPermission())
-	return &permission.RemovePolicyResponse{
-		Removed: removed,
-		Error:   fmt.Sprintf("Remove policy err %s", err),
-	}, nil
+	if v := required("", "policy", req.GetPolicy(), "resource", req.GetResource(), "permission", req.GetPermission()); len(v) > 0 {
+		return nil, invalidArgument("remove policy", v...)
+	}
+	var (
+		removed bool
+		err     error
+	)
+	if req.GetCondition() != "" {
+		removed, err = s.permissionServer.RemoveConditionalPolicy(ctx, req.GetPolicy(), domainOrGlobal(req.GetDomain()), req.GetResource(), req.GetPermission(), req.GetCondition())
+	} else {
+		removed, err = s.permissionServer.RemovePolicy(ctx, req.GetPolicy(), domainOrGlobal(req.GetDomain()), req.GetResource(), req.GetPermission())
+	}
+	if err != nil {
+		return nil, statusError(ctx, "remove policy", err)
+	}
+	return &permission.RemovePolicyResponse{Removed: removed}, nil
 }
 
 func (s *PermissionGRPCServer) AddPolicyIfNotExists(ctx context.Context, req *permission.AddPolicyIfNotExistsRequest) (*permission.AddPolicyIfNotExistsResponse, error) {
-	exists, err := s.permissionServer.AddPolicyIfNotExists(req.GetPolicy(), req.GetResource(), req.GetPermission())
-	return &permission.AddPolicyIfNotExistsResponse{
-		Exists: exists,
-		Error:  fmt.Sprintf("Add policy if exists %s", err),
+	if v := required("", "policy", req.GetPolicy(), "resource", req.GetResource(), "permission", req.GetPermission()); len(v) > 0 {
+		return nil, invalidArgument("add policy if not exists", v...)
+	}
+	exists, err := s.permissionServer.AddPolicyIfNotExists(ctx, req.GetPolicy(), domainOrGlobal(req.GetDomain()), req.GetResource(), req.GetPermission())
+	if err != nil {
+		return nil, statusError(ctx, "add policy if not exists", err)
+	}
+	return &permission.AddPolicyIfNotExistsResponse{Exists: exists}, nil
+}
+
+func (s *PermissionGRPCServer) AssignRole(ctx context.Context, req *permission.AssignRoleRequest) (*permission.AssignRoleResponse, error) {
+	if v := required("", "user_id", req.GetUserId(), "role", req.GetRole()); len(v) > 0 {
+		return nil, invalidArgument("assign role", v...)
+	}
+	assigned, err := s.permissionServer.AssignRole(ctx, req.GetUserId(), req.GetRole(), domainOrGlobal(req.GetDomain()))
+	if err != nil {
+		return nil, statusError(ctx, "assign role", err)
+	}
+	slog.Info("Assign role: ",
+		"User", req.GetUserId(),
+		"Role", req.GetRole(),
+		"Domain", req.GetDomain(),
+		"Assigned", assigned)
+	return &permission.AssignRoleResponse{Assigned: assigned}, nil
+}
+
+func (s *PermissionGRPCServer) UnassignRole(ctx context.Context, req *permission.UnassignRoleRequest) (*permission.UnassignRoleResponse, error) {
+	if v := required("", "user_id", req.GetUserId(), "role", req.GetRole()); len(v) > 0 {
+		return nil, invalidArgument("unassign role", v...)
+	}
+	unassigned, err := s.permissionServer.UnassignRole(ctx, req.GetUserId(), req.GetRole(), domainOrGlobal(req.GetDomain()))
+	if err != nil {
+		return nil, statusError(ctx, "unassign role", err)
+	}
+	slog.Info("Unassign role: ",
+		"User", req.GetUserId(),
+		"Role", req.GetRole(),
+		"Domain", req.GetDomain(),
+		"Unassigned", unassigned)
+	return &permission.UnassignRoleResponse{Unassigned: unassigned}, nil
+}
+
+func (s *PermissionGRPCServer) ListRoleMembers(ctx context.Context, req *permission.ListRoleMembersRequest) (*permission.ListRoleMembersResponse, error) {
+	if v := required("", "role", req.GetRole()); len(v) > 0 {
+		return nil, invalidArgument("list role members", v...)
+	}
+	members, err := s.permissionServer.GetRoleMembers(req.GetRole(), domainOrGlobal(req.GetDomain()))
+	if err != nil {
+		return nil, statusError(ctx, "list role members", err)
+	}
+	return &permission.ListRoleMembersResponse{UserIds: members}, nil
+}
+
+// maxBatchChecks bounds a single BatchCheckPermission call, so one request
+// can't hold the enforcer read lock for too long.
+const maxBatchChecks = 1000
+
+func (s *PermissionGRPCServer) BatchCheckPermission(ctx context.Context, req *permission.BatchCheckPermissionRequest) (*permission.BatchCheckPermissionResponse, error) {
+	if len(req.GetChecks()) > maxBatchChecks {
+		return nil, invalidArgument("batch check permission", fieldViolation{
+			field:       "checks",
+			description: fmt.Sprintf("too many checks: %d, max %d", len(req.GetChecks()), maxBatchChecks),
+		})
+	}
+	var violations []fieldViolation
+	checks := make([]service.PermissionCheck, len(req.GetChecks()))
+	for i, c := range req.GetChecks() {
+		violations = append(violations, checkViolations(fmt.Sprintf("checks[%d].", i), c)...)
+		checks[i] = service.PermissionCheck{
+			Sub:   c.GetUserId(),
+			Dom:   domainOrGlobal(c.GetDomain()),
+			Obj:   c.GetResource(),
+			Act:   c.GetAction(),
+			Attrs: checkAttributes(c),
+		}
+	}
+	if len(violations) > 0 {
+		return nil, invalidArgument("batch check permission", violations...)
+	}
+	allowed, err := s.permissionServer.BatchCheckPermission(checks)
+	if err != nil {
+		return nil, statusError(ctx, "batch check permission", err)
+	}
+	slog.Info("Batch check permission: ", "Checks", len(checks))
+	return &permission.BatchCheckPermissionResponse{Allowed: allowed}, nil
+}
+
+func (s *PermissionGRPCServer) ListPermissionsForSubject(ctx context.Context, req *permission.ListPermissionsForSubjectRequest) (*permission.ListPermissionsForSubjectResponse, error) {
+	if v := required("", "subject", req.GetSubject()); len(v) > 0 {
+		return nil, invalidArgument("list permissions for subject", v...)
+	}
+	perms, err := s.permissionServer.ListPermissionsForSubject(req.GetSubject(), domainOrGlobal(req.GetDomain()))
+	if err != nil {
+		return nil, statusError(ctx, "list permissions for subject", err)
+	}
+	start, end, next, err := page(len(perms), req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, statusError(ctx, "list permissions for subject", err)
+	}
+	resp := &permission.ListPermissionsForSubjectResponse{NextPageToken: next}
+	for _, p := range perms[start:end] {
+		resp.Permissions = append(resp.Permissions, &permission.Policy{
+			Subject:   p.Subject,
+			Domain:    p.Domain,
+			Resource:  p.Object,
+			Action:    p.Action,
+			Condition: p.Condition,
+		})
+	}
+	return resp, nil
+}
+
+func (s *PermissionGRPCServer) ListSubjectsForResource(ctx context.Context, req *permission.ListSubjectsForResourceRequest) (*permission.ListSubjectsForResourceResponse, error) {
+	if v := required("", "resource", req.GetResource()); len(v) > 0 {
+		return nil, invalidArgument("list subjects for resource", v...)
+	}
+	subjects, err := s.permissionServer.ListSubjectsForResource(req.GetResource(), req.GetAction(), domainOrGlobal(req.GetDomain()))
+	if err != nil {
+		return nil, statusError(ctx, "list subjects for resource", err)
+	}
+	start, end, next, err := page(len(subjects), req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, statusError(ctx, "list subjects for resource", err)
+	}
+	return &permission.ListSubjectsForResourceResponse{
+		Subjects:      subjects[start:end],
+		NextPageToken: next,
 	}, nil
 }
+
+func (s *PermissionGRPCServer) GetImplicitRolesForUser(ctx context.Context, req *permission.GetImplicitRolesForUserRequest) (*permission.GetImplicitRolesForUserResponse, error) {
+	if v := required("", "user_id", req.GetUserId()); len(v) > 0 {
+		return nil, invalidArgument("get implicit roles for user", v...)
+	}
+	roles, err := s.permissionServer.GetImplicitRolesForUser(req.GetUserId(), domainOrGlobal(req.GetDomain()))
+	if err != nil {
+		return nil, statusError(ctx, "get implicit roles for user", err)
+	}
+	return &permission.GetImplicitRolesForUserResponse{Roles: roles}, nil
+}
+
+func (s *PermissionGRPCServer) ExplainPermission(ctx context.Context, req *permission.CheckPermissionRequest) (*permission.ExplainPermissionResponse, error) {
+	if v := checkViolations("", req); len(v) > 0 {
+		return nil, invalidArgument("explain permission", v...)
+	}
+	ex, err := s.permissionServer.ExplainPermission(req.GetUserId(), domainOrGlobal(req.GetDomain()), req.GetResource(), req.GetAction(), checkAttributes(req))
+	if err != nil {
+		return nil, statusError(ctx, "explain permission", err)
+	}
+	slog.Info("Explain permission: ",
+		"User", req.GetUserId(),
+		"Domain", req.GetDomain(),
+		"Object", req.GetResource(),
+		"Action", req.GetAction(),
+		"Allowed", ex.Allowed,
+		"Reason", ex.Reason)
+	resp := &permission.ExplainPermissionResponse{
+		Allowed: ex.Allowed,
+		Roles:   ex.Roles,
+		Reason:  ex.Reason,
+	}
+	for _, p := range ex.Matched {
+		resp.MatchedPolicies = append(resp.MatchedPolicies, &permission.Policy{
+			Subject:   p.Subject,
+			Domain:    p.Domain,
+			Resource:  p.Object,
+			Action:    p.Action,
+			Condition: p.Condition,
+		})
+	}
+	return resp, nil
+}
+
+func (s *PermissionGRPCServer) GetPolicySnapshot(ctx context.Context, req *permission.GetPolicySnapshotRequest) (*permission.GetPolicySnapshotResponse, error) {
+	rules := s.permissionServer.PolicySnapshot()
+	resp := &permission.GetPolicySnapshotResponse{Rules: toPolicyRules(rules)}
+	if caller, ok := Caller(ctx); ok {
+		slog.Info("Policy snapshot: ", "Caller", caller, "Rules", len(rules))
+	}
+	return resp, nil
+}
+
+// WatchPolicies sends the events the caller missed and then every change as
+// it happens. A watcher that falls too far behind is disconnected with
+// Unavailable and resumes from its last revision.
+func (s *PermissionGRPCServer) WatchPolicies(req *permission.WatchPoliciesRequest, stream permission.PermissionService_WatchPoliciesServer) error {
+	backlog, live, cancel := s.permissionServer.WatchPolicies(req.GetEpoch(), req.GetRevision())
+	defer cancel()
+
+	ctx := stream.Context()
+	if caller, ok := Caller(ctx); ok {
+		slog.Info("Policy watch: ", "Caller", caller, "Revision", req.GetRevision())
+	}
+	for _, ev := range backlog {
+		if err := stream.Send(toPolicyEvent(ev)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-s.stopping:
+			return status.Error(codes.Unavailable, "server is shutting down, resume from the last revision")
+		case ev, ok := <-live:
+			if !ok {
+				return status.Error(codes.Unavailable, "policy watcher fell behind, resume from the last revision")
+			}
+			if err := stream.Send(toPolicyEvent(ev)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toPolicyEvent(ev service.PolicyEvent) *permission.PolicyEvent {
+	return &permission.PolicyEvent{
+		Epoch:    ev.Epoch,
+		Revision: ev.Revision,
+		Type:     string(ev.Type),
+		Rules:    toPolicyRules(ev.Rules),
+	}
+}
+
+func policyFilter(f *permission.PolicyFilter) service.PolicyFilter {
+	return service.PolicyFilter{
+		Subject: f.GetSubject(),
+		Domain:  f.GetDomain(),
+		Object:  f.GetResource(),
+		Action:  f.GetAction(),
+	}
+}
+
+func (s *PermissionGRPCServer) ListPolicies(ctx context.Context, req *permission.ListPoliciesRequest) (*permission.ListPoliciesResponse, error) {
+	perms := s.permissionServer.ListPolicies(policyFilter(req.GetFilter()))
+	start, end, next, err := page(len(perms), req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, statusError(ctx, "list policies", err)
+	}
+	resp := &permission.ListPoliciesResponse{NextPageToken: next}
+	for _, p := range perms[start:end] {
+		resp.Policies = append(resp.Policies, &permission.Policy{
+			Subject:   p.Subject,
+			Domain:    p.Domain,
+			Resource:  p.Object,
+			Action:    p.Action,
+			Condition: p.Condition,
+		})
+	}
+	return resp, nil
+}
+
+func (s *PermissionGRPCServer) ReplacePolicies(ctx context.Context, req *permission.ReplacePoliciesRequest) (*permission.ReplacePoliciesResponse, error) {
+	var violations []fieldViolation
+	policies := make([]service.Permission, len(req.GetPolicies()))
+	for i, p := range req.GetPolicies() {
+		violations = append(violations, required(fmt.Sprintf("policies[%d].", i),
+			"subject", p.GetSubject(),
+			"resource", p.GetResource(),
+			"action", p.GetAction())...)
+		policies[i] = service.Permission{
+			Subject:   p.GetSubject(),
+			Domain:    p.GetDomain(),
+			Object:    p.GetResource(),
+			Action:    p.GetAction(),
+			Condition: p.GetCondition(),
+		}
+	}
+	if len(violations) > 0 {
+		return nil, invalidArgument("replace policies", violations...)
+	}
+	added, removed, err := s.permissionServer.ReplacePolicies(ctx, policyFilter(req.GetFilter()), policies)
+	if err != nil {
+		return nil, statusError(ctx, "replace policies", err)
+	}
+	caller, _ := Caller(ctx)
+	slog.Info("Replace policies: ",
+		"Caller", caller,
+		"Filter", req.GetFilter().String(),
+		"Added", added,
+		"Removed", removed)
+	return &permission.ReplacePoliciesResponse{Added: int32(added), Removed: int32(removed)}, nil
+}
+
+func (s *PermissionGRPCServer) ListRoles(ctx context.Context, req *permission.ListRolesRequest) (*permission.ListRolesResponse, error) {
+	return &permission.ListRolesResponse{Roles: s.permissionServer.ListRoles(req.GetDomain())}, nil
+}
+
+func (s *PermissionGRPCServer) SyncPolicies(ctx context.Context, req *permission.SyncPoliciesRequest) (*permission.SyncPoliciesResponse, error) {
+	rules := make([]service.PolicyRule, len(req.GetRules()))
+	for i, r := range req.GetRules() {
+		rules[i] = service.PolicyRule{PType: r.GetPtype(), Values: r.GetValues()}
+	}
+	plan, err := s.permissionServer.SyncPolicies(ctx, rules, req.GetPrune(), req.GetDryRun())
+	if err != nil {
+		return nil, statusError(ctx, "sync policies", err)
+	}
+	caller, _ := Caller(ctx)
+	slog.Info("Sync policies: ",
+		"Caller", caller,
+		"Rules", len(rules),
+		"Prune", req.GetPrune(),
+		"DryRun", req.GetDryRun(),
+		"Added", len(plan.Added),
+		"Removed", len(plan.Removed))
+	return &permission.SyncPoliciesResponse{Added: toPolicyRules(plan.Added), Removed: toPolicyRules(plan.Removed)}, nil
+}
+
+func toPolicyRules(rules []service.PolicyRule) []*permission.PolicyRule {
+	out := make([]*permission.PolicyRule, len(rules))
+	for i, r := range rules {
+		out[i] = &permission.PolicyRule{Ptype: r.PType, Values: r.Values}
+	}
+	return out
+}
+
+func (s *PermissionGRPCServer) ListPolicyChanges(ctx context.Context, req *permission.ListPolicyChangesRequest) (*permission.ListPolicyChangesResponse, error) {
+	f := models.PolicyChangeFilter{
+		Actor:   req.GetActor(),
+		Service: req.GetService(),
+		Subject: req.GetSubject(),
+		Domain:  req.GetDomain(),
+	}
+	var violations []fieldViolation
+	for _, bound := range []struct {
+		field string
+		value string
+		to    *time.Time
+	}{{"since", req.GetSince(), &f.Since}, {"until", req.GetUntil(), &f.Until}} {
+		if bound.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, bound.value)
+		if err != nil {
+			violations = append(violations, fieldViolation{field: bound.field, description: "must be an RFC 3339 time"})
+		}
+		*bound.to = t
+	}
+	if len(violations) > 0 {
+		return nil, invalidArgument("list policy changes", violations...)
+	}
+	before, limit, err := revisionPage(req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, statusError(ctx, "list policy changes", err)
+	}
+	f.BeforeRevision = before
+
+	// One more than asked tells whether there is a next page.
+	changes, err := s.permissionServer.PolicyHistory(ctx, f, limit+1)
+	if err != nil {
+		return nil, statusError(ctx, "list policy changes", err)
+	}
+	resp := &permission.ListPolicyChangesResponse{}
+	if len(changes) > limit {
+		changes = changes[:limit]
+		resp.NextPageToken = pageToken(changes[limit-1].ID)
+	}
+	for _, c := range changes {
+		resp.Changes = append(resp.Changes, toPolicyChange(c))
+	}
+	return resp, nil
+}
+
+func toPolicyChange(c models.PolicyChange) *permission.PolicyChange {
+	out := &permission.PolicyChange{
+		Revision:  c.ID,
+		ChangedAt: c.CreatedAt.UTC().Format(time.RFC3339Nano),
+		Service:   c.Service,
+		Actor:     c.Actor,
+		Type:      c.Op,
+	}
+	if len(c.Before) > 0 {
+		out.Before = &permission.PolicyRule{Ptype: c.PType, Values: c.Before}
+	}
+	if len(c.After) > 0 {
+		out.After = &permission.PolicyRule{Ptype: c.PType, Values: c.After}
+	}
+	return out
+}
+
+func (s *PermissionGRPCServer) RevertPolicies(ctx context.Context, req *permission.RevertPoliciesRequest) (*permission.RevertPoliciesResponse, error) {
+	plan, err := s.permissionServer.RevertPolicies(ctx, req.GetRevision(), req.GetDryRun())
+	if err != nil {
+		return nil, statusError(ctx, "revert policies", err)
+	}
+	caller, _ := Caller(ctx)
+	slog.Info("Revert policies: ",
+		"Caller", caller,
+		"Revision", req.GetRevision(),
+		"DryRun", req.GetDryRun(),
+		"Added", len(plan.Added),
+		"Removed", len(plan.Removed))
+	return &permission.RevertPoliciesResponse{Added: toPolicyRules(plan.Added), Removed: toPolicyRules(plan.Removed)}, nil
+}
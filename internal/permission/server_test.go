@@ -0,0 +1,89 @@
+package permission
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mrhumster/web-server-gin/gen/go/permission"
+	"github.com/mrhumster/web-server-gin/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func fieldViolations(t *testing.T, err error) []string {
+	t.Helper()
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	var fields []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				fields = append(fields, v.GetField())
+			}
+		}
+	}
+	return fields
+}
+
+func TestPermissionGRPCServer_Validation(t *testing.T) {
+	s := NewPermissionGRPCServer(&service.PermissionService{})
+	ctx := context.Background()
+
+	_, err := s.CheckPermission(ctx, &permission.CheckPermissionRequest{UserId: "u1"})
+	assert.Equal(t, []string{"resource", "action"}, fieldViolations(t, err))
+
+	_, err = s.AddPolicy(ctx, &permission.AddPolicyRequest{Resorce: "users", Permission: "read"})
+	assert.Equal(t, []string{"policy"}, fieldViolations(t, err))
+
+	_, err = s.AssignRole(ctx, &permission.AssignRoleRequest{UserId: "u1"})
+	assert.Equal(t, []string{"role"}, fieldViolations(t, err))
+
+	_, err = s.BatchCheckPermission(ctx, &permission.BatchCheckPermissionRequest{
+		Checks: []*permission.CheckPermissionRequest{
+			{UserId: "u1", Resource: "users", Action: "read"},
+			{UserId: "u1", Resource: "users"},
+		},
+	})
+	assert.Equal(t, []string{"checks[1].action"}, fieldViolations(t, err))
+
+	checks := make([]*permission.CheckPermissionRequest, maxBatchChecks+1)
+	_, err = s.BatchCheckPermission(ctx, &permission.BatchCheckPermissionRequest{Checks: checks})
+	assert.Equal(t, []string{"checks"}, fieldViolations(t, err))
+}
+
+func TestStatusError(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{fmt.Errorf("wrap: %w", service.ErrInvalidCondition), codes.InvalidArgument, "INVALID_CONDITION"},
+		{errInvalidPageToken, codes.InvalidArgument, "INVALID_PAGE_TOKEN"},
+		{fmt.Errorf("rules[0]: %w", service.ErrInvalidPolicyRule), codes.InvalidArgument, "INVALID_POLICY_RULE"},
+		{service.ErrHistoryDisabled, codes.FailedPrecondition, "HISTORY_DISABLED"},
+		{fmt.Errorf("adapter: connection refused"), codes.Internal, "ENFORCER_FAILURE"},
+	}
+	for _, tc := range cases {
+		st, ok := status.FromError(statusError(ctx, "op", tc.err))
+		require.True(t, ok)
+		assert.Equal(t, tc.code, st.Code())
+		require.Len(t, st.Details(), 1)
+		assert.Equal(t, tc.reason, st.Details()[0].(*errdetails.ErrorInfo).GetReason())
+	}
+
+	// Internal errors don't tell the caller where the server failed.
+	st := status.Convert(statusError(ctx, "op", fmt.Errorf("dial tcp 10.0.0.5:5432: connection refused")))
+	assert.Equal(t, "op: internal error", st.Message())
+	assert.Equal(t, "op: invalid page token", status.Convert(statusError(ctx, "op", errInvalidPageToken)).Message())
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Equal(t, codes.Canceled, status.Code(statusError(canceled, "op", fmt.Errorf("boom"))))
+}
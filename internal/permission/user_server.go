@@ -0,0 +1,247 @@
+package permission
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/gen/go/user"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"github.com/mrhumster/web-server-gin/internal/service"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBatchUsers bounds a single BatchGetUsers call.
+const maxBatchUsers = 1000
+
+// UserGRPCServer serves service.UserService to other backend services.
+type UserGRPCServer struct {
+	user.UnimplementedUserServiceServer
+	users       *service.UserService
+	permissions *service.PermissionService
+}
+
+// NewUserGRPCServer checks the calls made for a user with permissions, the
+// same policies the HTTP routes are checked with.
+func NewUserGRPCServer(users *service.UserService, permissions *service.PermissionService) *UserGRPCServer {
+	return &UserGRPCServer{users: users, permissions: permissions}
+}
+
+// authorize checks that the subject of the call may act on obj in the
+// organization it acts in, with the policies the HTTP routes are checked
+// with. A service calling on its own behalf needs policies of its own, e.g.
+// "service:billing, users/*, read".
+func (s *UserGRPCServer) authorize(ctx context.Context, obj, act string) error {
+	sub := callSubject(ctx)
+	if sub == "" {
+		return status.Error(codes.Unauthenticated, "no caller to check")
+	}
+	allowed, err := s.permissions.CheckPermission(sub, actingTenant(ctx), obj, act, service.Attributes{})
+	if err != nil {
+		return statusError(ctx, "check permission", err)
+	}
+	if !allowed {
+		return status.Errorf(codes.PermissionDenied, "%s may not %s %s", sub, act, obj)
+	}
+	return nil
+}
+
+// readable reports for each user whether the subject of the call may read
+// it, checking them all with a single batch call.
+func (s *UserGRPCServer) readable(ctx context.Context, users []models.User) ([]bool, error) {
+	sub := callSubject(ctx)
+	if sub == "" {
+		return nil, status.Error(codes.Unauthenticated, "no caller to check")
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+	dom := actingTenant(ctx)
+	checks := make([]service.PermissionCheck, len(users))
+	for i, u := range users {
+		checks[i] = service.PermissionCheck{Sub: sub, Dom: dom, Obj: userObject(u.ID), Act: "read"}
+	}
+	allowed, err := s.permissions.BatchCheckPermission(checks)
+	if err != nil {
+		return nil, statusError(ctx, "check permission", err)
+	}
+	return allowed, nil
+}
+
+func userObject(id uuid.UUID) string {
+	return fmt.Sprintf("users/%s", id)
+}
+
+func (s *UserGRPCServer) GetUser(ctx context.Context, req *user.GetUserRequest) (*user.User, error) {
+	if req.GetId() == "" && req.GetEmail() == "" {
+		return nil, invalidArgument("get user", fieldViolation{field: "id", description: "id or email must be set"})
+	}
+	if req.GetId() != "" {
+		id, err := uuid.Parse(req.GetId())
+		if err != nil {
+			return nil, invalidArgument("get user", fieldViolation{field: "id", description: "must be a UUID"})
+		}
+		if err := s.authorize(ctx, userObject(id), "read"); err != nil {
+			return nil, err
+		}
+		u, err := s.users.ReadUser(ctx, id)
+		if err != nil {
+			return nil, statusError(ctx, "get user", err)
+		}
+		return userMessage(u), nil
+	}
+	u, err := s.users.GetUserByEmail(ctx, req.GetEmail())
+	if err != nil {
+		return nil, statusError(ctx, "get user", err)
+	}
+	// Looking a user up by email must not tell whether the account exists.
+	if err := s.authorize(ctx, userObject(u.ID), "read"); err != nil {
+		return nil, status.Error(codes.NotFound, "get user: user not found")
+	}
+	return userMessage(u), nil
+}
+
+func (s *UserGRPCServer) BatchGetUsers(ctx context.Context, req *user.BatchGetUsersRequest) (*user.BatchGetUsersResponse, error) {
+	if len(req.GetIds()) > maxBatchUsers {
+		return nil, invalidArgument("batch get users", fieldViolation{
+			field:       "ids",
+			description: fmt.Sprintf("at most %d ids per call", maxBatchUsers),
+		})
+	}
+	ids := make([]uuid.UUID, len(req.GetIds()))
+	var violations []fieldViolation
+	for i, raw := range req.GetIds() {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			violations = append(violations, fieldViolation{field: fmt.Sprintf("ids[%d]", i), description: "must be a UUID"})
+		}
+		ids[i] = id
+	}
+	if len(violations) > 0 {
+		return nil, invalidArgument("batch get users", violations...)
+	}
+	found, err := s.users.ReadUsers(ctx, ids)
+	if err != nil {
+		return nil, statusError(ctx, "batch get users", err)
+	}
+	allowed, err := s.readable(ctx, found)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*models.User, len(found))
+	for i := range found {
+		if allowed[i] {
+			byID[found[i].ID] = &found[i]
+		}
+	}
+	resp := &user.BatchGetUsersResponse{}
+	for i, id := range ids {
+		if u, ok := byID[id]; ok {
+			resp.Users = append(resp.Users, userMessage(u))
+		} else {
+			resp.MissingIds = append(resp.MissingIds, req.GetIds()[i])
+		}
+	}
+	return resp, nil
+}
+
+func (s *UserGRPCServer) ListUsers(ctx context.Context, req *user.ListUsersRequest) (*user.ListUsersResponse, error) {
+	return s.search(ctx, "list users", "", req.GetPageSize(), req.GetPageToken())
+}
+
+func (s *UserGRPCServer) SearchUsers(ctx context.Context, req *user.SearchUsersRequest) (*user.ListUsersResponse, error) {
+	if violations := required("", "query", strings.TrimSpace(req.GetQuery())); len(violations) > 0 {
+		return nil, invalidArgument("search users", violations...)
+	}
+	return s.search(ctx, "search users", strings.TrimSpace(req.GetQuery()), req.GetPageSize(), req.GetPageToken())
+}
+
+func (s *UserGRPCServer) search(ctx context.Context, op, query string, pageSize int32, token string) (*user.ListUsersResponse, error) {
+	if err := s.authorize(ctx, "users", "read"); err != nil {
+		return nil, err
+	}
+	offset, limit, err := offsetPage(pageSize, token)
+	if err != nil {
+		return nil, statusError(ctx, op, err)
+	}
+	// Like the HTTP list, one window of users is read and the ones the
+	// subject may not read are left out, so every call looks at no more than
+	// limit users; the token points past the window.
+	users, total, err := s.users.SearchUsers(ctx, query, offset, limit)
+	if err != nil {
+		return nil, statusError(ctx, op, err)
+	}
+	allowed, err := s.readable(ctx, users)
+	if err != nil {
+		return nil, err
+	}
+	resp := &user.ListUsersResponse{}
+	for i := range users {
+		if allowed[i] {
+			resp.Users = append(resp.Users, userMessage(&users[i]))
+		}
+	}
+	if end := offset + len(users); int64(end) < total {
+		resp.NextPageToken = pageToken(uint64(end))
+	}
+	return resp, nil
+}
+
+func (s *UserGRPCServer) CreateUser(ctx context.Context, req *user.CreateUserRequest) (*user.User, error) {
+	if violations := required("", "email", req.GetEmail(), "password", req.GetPassword()); len(violations) > 0 {
+		return nil, invalidArgument("create user", violations...)
+	}
+	// Creating an account is open to anyone over HTTP; granting it a role
+	// other than member takes the right to manage that role.
+	if req.GetRole() != "" && req.GetRole() != models.RoleMember {
+		if err := s.authorize(ctx, "roles/"+req.GetRole(), "write"); err != nil {
+			return nil, err
+		}
+	}
+	u := models.User{Email: req.GetEmail(), Role: req.GetRole()}
+	if err := u.SetPassword(req.GetPassword()); err != nil {
+		return nil, invalidArgument("create user", fieldViolation{field: "password", description: err.Error()})
+	}
+	if actor := actingUser(ctx); actor != "" {
+		ctx = auth.WithActor(ctx, actor)
+	}
+	id, err := s.users.CreateUser(ctx, u)
+	if err != nil {
+		return nil, statusError(ctx, "create user", err)
+	}
+	created, err := s.users.ReadUser(ctx, *id)
+	if err != nil {
+		return nil, statusError(ctx, "create user", err)
+	}
+	return userMessage(created), nil
+}
+
+func (s *UserGRPCServer) DisableUser(ctx context.Context, req *user.DisableUserRequest) (*user.User, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, invalidArgument("disable user", fieldViolation{field: "id", description: "must be a UUID"})
+	}
+	if err := s.authorize(ctx, userObject(id), "write"); err != nil {
+		return nil, err
+	}
+	u, err := s.users.DisableUser(ctx, id)
+	if err != nil {
+		return nil, statusError(ctx, "disable user", err)
+	}
+	return userMessage(u), nil
+}
+
+func userMessage(u *models.User) *user.User {
+	return &user.User{
+		Id:        u.ID.String(),
+		Email:     u.Email,
+		Role:      u.Role,
+		Disabled:  u.Disabled,
+		CreatedAt: u.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: u.UpdatedAt.Format(time.RFC3339),
+	}
+}
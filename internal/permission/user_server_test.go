@@ -0,0 +1,235 @@
+package permission
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/config"
+	"github.com/mrhumster/web-server-gin/gen/go/user"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	repomock "github.com/mrhumster/web-server-gin/internal/repository/mock"
+	"github.com/mrhumster/web-server-gin/internal/service"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+func newTestUserServer(t *testing.T) (*UserGRPCServer, *repomock.MockUserRepository, *service.PermissionService) {
+	t.Helper()
+	e, err := casbin.NewEnforcer(filepath.Join(config.GetRootDir(), "config", "model.conf"))
+	require.NoError(t, err)
+	permissions, err := service.NewPermissionService(e, service.NewMemoryWatcher(nil))
+	require.NoError(t, err)
+	repo := repomock.NewMockUserRepository(gomock.NewController(t))
+	return NewUserGRPCServer(service.NewUserService(repo, nil), permissions), repo, permissions
+}
+
+func asActor(user string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(auth.ActorMetadataKey, user))
+}
+
+// asService calls as the service name on its own behalf.
+func asService(name string) context.Context {
+	return withCaller(context.Background(), name)
+}
+
+// grant gives sub the right to act on each of objs in the global domain.
+func grant(t *testing.T, permissions *service.PermissionService, sub, act string, objs ...string) {
+	t.Helper()
+	for _, obj := range objs {
+		_, err := permissions.AddPolicy(t.Context(), sub, auth.GlobalDomain, obj, act)
+		require.NoError(t, err)
+	}
+}
+
+// asActorIn acts for user in the organization org.
+func asActorIn(user, org string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		auth.ActorMetadataKey, user, auth.TenantMetadataKey, org))
+}
+
+func TestUserGRPCServer_Validation(t *testing.T) {
+	s, _, _ := newTestUserServer(t)
+	ctx := context.Background()
+
+	_, err := s.GetUser(ctx, &user.GetUserRequest{})
+	assert.Equal(t, []string{"id"}, fieldViolations(t, err))
+
+	_, err = s.BatchGetUsers(ctx, &user.BatchGetUsersRequest{Ids: []string{uuid.NewString(), "nope"}})
+	assert.Equal(t, []string{"ids[1]"}, fieldViolations(t, err))
+
+	_, err = s.SearchUsers(ctx, &user.SearchUsersRequest{Query: "  "})
+	assert.Equal(t, []string{"query"}, fieldViolations(t, err))
+
+	_, err = s.CreateUser(ctx, &user.CreateUserRequest{Role: models.RoleMember})
+	assert.Equal(t, []string{"email", "password"}, fieldViolations(t, err))
+
+	_, err = s.DisableUser(ctx, &user.DisableUserRequest{Id: "nope"})
+	assert.Equal(t, []string{"id"}, fieldViolations(t, err))
+}
+
+func TestUserGRPCServer_GetUser(t *testing.T) {
+	s, repo, permissions := newTestUserServer(t)
+	alice := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "alice@example.com", Role: models.RoleMember}
+	_, err := permissions.AddPolicy(t.Context(), "self", auth.GlobalDomain, "users/:id", "read")
+	require.NoError(t, err)
+
+	grant(t, permissions, "service:billing", "read", "users/*")
+
+	repo.EXPECT().ReadUserByID(gomock.Any(), alice.ID).Return(&alice, nil).Times(2)
+	got, err := s.GetUser(asService("billing"), &user.GetUserRequest{Id: alice.ID.String()})
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", got.GetEmail())
+
+	got, err = s.GetUser(asActor(alice.ID.String()), &user.GetUserRequest{Id: alice.ID.String()})
+	require.NoError(t, err)
+	assert.Equal(t, alice.ID.String(), got.GetId())
+
+	_, err = s.GetUser(asActor(uuid.NewString()), &user.GetUserRequest{Id: alice.ID.String()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// A service acting on its own behalf is checked like a user.
+	_, err = s.GetUser(asService("reporting"), &user.GetUserRequest{Id: alice.ID.String()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.GetUser(context.Background(), &user.GetUserRequest{Id: alice.ID.String()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// By email a denial looks like a missing account.
+	repo.EXPECT().ReadUserByEmail(gomock.Any(), alice.Email).Return(&alice, nil)
+	_, err = s.GetUser(asActor(uuid.NewString()), &user.GetUserRequest{Email: alice.Email})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	repo.EXPECT().ReadUserByEmail(gomock.Any(), "ghost@example.com").Return(nil, gorm.ErrRecordNotFound)
+	_, err = s.GetUser(asService("billing"), &user.GetUserRequest{Email: "ghost@example.com"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestUserGRPCServer_BatchGetUsers(t *testing.T) {
+	s, repo, permissions := newTestUserServer(t)
+	alice := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "alice@example.com"}
+	bob := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Email: "bob@example.com"}
+	ghost := uuid.New()
+	_, err := permissions.AddPolicy(t.Context(), "self", auth.GlobalDomain, "users/:id", "read")
+	require.NoError(t, err)
+	grant(t, permissions, "service:billing", "read", "users/*")
+
+	ids := []string{bob.ID.String(), ghost.String(), alice.ID.String()}
+	repo.EXPECT().ReadUsersByIDs(gomock.Any(), gomock.Len(3)).Return([]models.User{alice, bob}, nil).Times(2)
+
+	resp, err := s.BatchGetUsers(asService("billing"), &user.BatchGetUsersRequest{Ids: ids})
+	require.NoError(t, err)
+	require.Len(t, resp.GetUsers(), 2)
+	assert.Equal(t, bob.ID.String(), resp.GetUsers()[0].GetId())
+	assert.Equal(t, alice.ID.String(), resp.GetUsers()[1].GetId())
+	assert.Equal(t, []string{ghost.String()}, resp.GetMissingIds())
+
+	// Users the actor may not read are reported missing too.
+	resp, err = s.BatchGetUsers(asActor(alice.ID.String()), &user.BatchGetUsersRequest{Ids: ids})
+	require.NoError(t, err)
+	require.Len(t, resp.GetUsers(), 1)
+	assert.Equal(t, alice.ID.String(), resp.GetUsers()[0].GetId())
+	assert.Equal(t, []string{bob.ID.String(), ghost.String()}, resp.GetMissingIds())
+}
+
+func TestUserGRPCServer_ListUsers(t *testing.T) {
+	s, repo, permissions := newTestUserServer(t)
+	users := []models.User{{BaseModel: models.BaseModel{ID: uuid.New()}}, {BaseModel: models.BaseModel{ID: uuid.New()}}}
+	grant(t, permissions, "service:billing", "read", "users", "users/*")
+
+	repo.EXPECT().SearchUsers(gomock.Any(), "", 0, 2).Return(users, int64(3), nil)
+	resp, err := s.ListUsers(asService("billing"), &user.ListUsersRequest{PageSize: 2})
+	require.NoError(t, err)
+	assert.Len(t, resp.GetUsers(), 2)
+	require.NotEmpty(t, resp.GetNextPageToken())
+
+	repo.EXPECT().SearchUsers(gomock.Any(), "", 2, 2).Return(users[:1], int64(3), nil)
+	resp, err = s.ListUsers(asService("billing"), &user.ListUsersRequest{PageSize: 2, PageToken: resp.GetNextPageToken()})
+	require.NoError(t, err)
+	assert.Len(t, resp.GetUsers(), 1)
+	assert.Empty(t, resp.GetNextPageToken())
+
+	_, err = s.ListUsers(asActor(uuid.NewString()), &user.ListUsersRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.ListUsers(asService("reporting"), &user.ListUsersRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestUserGRPCServer_ListUsers_Readable(t *testing.T) {
+	s, repo, permissions := newTestUserServer(t)
+	users := make([]models.User, 5)
+	for i := range users {
+		users[i].ID = uuid.New()
+	}
+	alice := uuid.NewString()
+	for _, obj := range []string{"users", userObject(users[1].ID), userObject(users[3].ID)} {
+		_, err := permissions.AddPolicy(t.Context(), alice, "org-1", obj, "read")
+		require.NoError(t, err)
+	}
+
+	// Every call looks at one window of users and leaves out the ones alice
+	// may not read.
+	var got []string
+	token := ""
+	for _, window := range [][2]int{{0, 2}, {2, 4}, {4, 5}} {
+		repo.EXPECT().SearchUsers(gomock.Any(), "", window[0], 2).Return(users[window[0]:window[1]], int64(5), nil)
+		resp, err := s.ListUsers(asActorIn(alice, "org-1"), &user.ListUsersRequest{PageSize: 2, PageToken: token})
+		require.NoError(t, err)
+		for _, u := range resp.GetUsers() {
+			got = append(got, u.GetId())
+		}
+		token = resp.GetNextPageToken()
+	}
+	assert.Equal(t, []string{users[1].ID.String(), users[3].ID.String()}, got)
+	assert.Empty(t, token)
+
+	// The policies are alice's in org-1 only.
+	_, err := s.ListUsers(asActor(alice), &user.ListUsersRequest{PageSize: 2})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.ListUsers(asActorIn(alice, "org-2"), &user.ListUsersRequest{PageSize: 2})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestUserGRPCServer_CreateUser(t *testing.T) {
+	s, repo, _ := newTestUserServer(t)
+
+	_, err := s.CreateUser(asActor(uuid.NewString()), &user.CreateUserRequest{
+		Email: "eve@example.com", Password: "secret123", Role: models.RoleAdmin,
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = s.CreateUser(asService("billing"), &user.CreateUserRequest{
+		Email: "eve@example.com", Password: "secret123", Role: models.RoleAdmin,
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	repo.EXPECT().CreateUser(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, service.ErrUserAlreadyExists)
+	_, err = s.CreateUser(asService("billing"), &user.CreateUserRequest{Email: "taken@example.com", Password: "secret123"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestUserGRPCServer_DisableUser(t *testing.T) {
+	s, repo, permissions := newTestUserServer(t)
+	alice := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Disabled: true}
+	grant(t, permissions, "service:billing", "read", "users/*")
+	grant(t, permissions, "service:support", "write", "users/*")
+
+	// Reading users doesn't let a service change them.
+	_, err := s.DisableUser(asService("billing"), &user.DisableUserRequest{Id: alice.ID.String()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.DisableUser(context.Background(), &user.DisableUserRequest{Id: alice.ID.String()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	repo.EXPECT().DisableUser(gomock.Any(), alice.ID, gomock.Any()).Return(nil)
+	repo.EXPECT().ReadUserByID(gomock.Any(), alice.ID).Return(&alice, nil)
+	got, err := s.DisableUser(asService("support"), &user.DisableUserRequest{Id: alice.ID.String()})
+	require.NoError(t, err)
+	assert.True(t, got.GetDisabled())
+}
@@ -0,0 +1,160 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: organization_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=organization_repository.go -destination=./mock/organization_repository_mock.go -package=repomock
+//
+
+// Package repomock is a generated GoMock package.
+package repomock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	models "github.com/mrhumster/web-server-gin/internal/domain/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOrganizationRepository is a mock of OrganizationRepository interface.
+type MockOrganizationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrganizationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOrganizationRepositoryMockRecorder is the mock recorder for MockOrganizationRepository.
+type MockOrganizationRepositoryMockRecorder struct {
+	mock *MockOrganizationRepository
+}
+
+// NewMockOrganizationRepository creates a new mock instance.
+func NewMockOrganizationRepository(ctrl *gomock.Controller) *MockOrganizationRepository {
+	mock := &MockOrganizationRepository{ctrl: ctrl}
+	mock.recorder = &MockOrganizationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrganizationRepository) EXPECT() *MockOrganizationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m_2 *MockOrganizationRepository) AddMember(ctx context.Context, m models.Membership, ops []models.PolicyOperation) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "AddMember", ctx, m, ops)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockOrganizationRepositoryMockRecorder) AddMember(ctx, m, ops any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockOrganizationRepository)(nil).AddMember), ctx, m, ops)
+}
+
+// CreateOrganization mocks base method.
+func (m *MockOrganizationRepository) CreateOrganization(ctx context.Context, org models.Organization, owner models.Membership, ops []models.PolicyOperation) (*uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrganization", ctx, org, owner, ops)
+	ret0, _ := ret[0].(*uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockOrganizationRepositoryMockRecorder) CreateOrganization(ctx, org, owner, ops any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockOrganizationRepository)(nil).CreateOrganization), ctx, org, owner, ops)
+}
+
+// ReadMembers mocks base method.
+func (m *MockOrganizationRepository) ReadMembers(ctx context.Context, orgID uuid.UUID) ([]models.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadMembers", ctx, orgID)
+	ret0, _ := ret[0].([]models.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadMembers indicates an expected call of ReadMembers.
+func (mr *MockOrganizationRepositoryMockRecorder) ReadMembers(ctx, orgID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadMembers", reflect.TypeOf((*MockOrganizationRepository)(nil).ReadMembers), ctx, orgID)
+}
+
+// ReadMembership mocks base method.
+func (m *MockOrganizationRepository) ReadMembership(ctx context.Context, orgID, userID uuid.UUID) (*models.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadMembership", ctx, orgID, userID)
+	ret0, _ := ret[0].(*models.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadMembership indicates an expected call of ReadMembership.
+func (mr *MockOrganizationRepositoryMockRecorder) ReadMembership(ctx, orgID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadMembership", reflect.TypeOf((*MockOrganizationRepository)(nil).ReadMembership), ctx, orgID, userID)
+}
+
+// ReadOrganizationByID mocks base method.
+func (m *MockOrganizationRepository) ReadOrganizationByID(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOrganizationByID", ctx, id)
+	ret0, _ := ret[0].(*models.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOrganizationByID indicates an expected call of ReadOrganizationByID.
+func (mr *MockOrganizationRepositoryMockRecorder) ReadOrganizationByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOrganizationByID", reflect.TypeOf((*MockOrganizationRepository)(nil).ReadOrganizationByID), ctx, id)
+}
+
+// ReadOrganizationsForUser mocks base method.
+func (m *MockOrganizationRepository) ReadOrganizationsForUser(ctx context.Context, userID uuid.UUID) ([]models.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOrganizationsForUser", ctx, userID)
+	ret0, _ := ret[0].([]models.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOrganizationsForUser indicates an expected call of ReadOrganizationsForUser.
+func (mr *MockOrganizationRepositoryMockRecorder) ReadOrganizationsForUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOrganizationsForUser", reflect.TypeOf((*MockOrganizationRepository)(nil).ReadOrganizationsForUser), ctx, userID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockOrganizationRepository) RemoveMember(ctx context.Context, orgID, userID uuid.UUID, ops []models.PolicyOperation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, orgID, userID, ops)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockOrganizationRepositoryMockRecorder) RemoveMember(ctx, orgID, userID, ops any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockOrganizationRepository)(nil).RemoveMember), ctx, orgID, userID, ops)
+}
+
+// UpdateMemberRole mocks base method.
+func (m *MockOrganizationRepository) UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role string, ops []models.PolicyOperation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemberRole", ctx, orgID, userID, role, ops)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMemberRole indicates an expected call of UpdateMemberRole.
+func (mr *MockOrganizationRepositoryMockRecorder) UpdateMemberRole(ctx, orgID, userID, role, ops any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRole", reflect.TypeOf((*MockOrganizationRepository)(nil).UpdateMemberRole), ctx, orgID, userID, role, ops)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: policy_change_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=policy_change_repository.go -destination=./mock/policy_change_repository_mock.go -package=repomock
+//
+
+// Package repomock is a generated GoMock package.
+package repomock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/mrhumster/web-server-gin/internal/domain/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPolicyChangeRepository is a mock of PolicyChangeRepository interface.
+type MockPolicyChangeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicyChangeRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPolicyChangeRepositoryMockRecorder is the mock recorder for MockPolicyChangeRepository.
+type MockPolicyChangeRepositoryMockRecorder struct {
+	mock *MockPolicyChangeRepository
+}
+
+// NewMockPolicyChangeRepository creates a new mock instance.
+func NewMockPolicyChangeRepository(ctrl *gomock.Controller) *MockPolicyChangeRepository {
+	mock := &MockPolicyChangeRepository{ctrl: ctrl}
+	mock.recorder = &MockPolicyChangeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPolicyChangeRepository) EXPECT() *MockPolicyChangeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreatePolicyChanges mocks base method.
+func (m *MockPolicyChangeRepository) CreatePolicyChanges(ctx context.Context, changes []models.PolicyChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePolicyChanges", ctx, changes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePolicyChanges indicates an expected call of CreatePolicyChanges.
+func (mr *MockPolicyChangeRepositoryMockRecorder) CreatePolicyChanges(ctx, changes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePolicyChanges", reflect.TypeOf((*MockPolicyChangeRepository)(nil).CreatePolicyChanges), ctx, changes)
+}
+
+// LatestPolicyRevision mocks base method.
+func (m *MockPolicyChangeRepository) LatestPolicyRevision(ctx context.Context) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestPolicyRevision", ctx)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestPolicyRevision indicates an expected call of LatestPolicyRevision.
+func (mr *MockPolicyChangeRepositoryMockRecorder) LatestPolicyRevision(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestPolicyRevision", reflect.TypeOf((*MockPolicyChangeRepository)(nil).LatestPolicyRevision), ctx)
+}
+
+// ReadPolicyChanges mocks base method.
+func (m *MockPolicyChangeRepository) ReadPolicyChanges(ctx context.Context, f models.PolicyChangeFilter, limit int) ([]models.PolicyChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadPolicyChanges", ctx, f, limit)
+	ret0, _ := ret[0].([]models.PolicyChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadPolicyChanges indicates an expected call of ReadPolicyChanges.
+func (mr *MockPolicyChangeRepositoryMockRecorder) ReadPolicyChanges(ctx, f, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadPolicyChanges", reflect.TypeOf((*MockPolicyChangeRepository)(nil).ReadPolicyChanges), ctx, f, limit)
+}
+
+// ReadPolicyChangesAfter mocks base method.
+func (m *MockPolicyChangeRepository) ReadPolicyChangesAfter(ctx context.Context, revision uint64) ([]models.PolicyChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadPolicyChangesAfter", ctx, revision)
+	ret0, _ := ret[0].([]models.PolicyChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadPolicyChangesAfter indicates an expected call of ReadPolicyChangesAfter.
+func (mr *MockPolicyChangeRepositoryMockRecorder) ReadPolicyChangesAfter(ctx, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadPolicyChangesAfter", reflect.TypeOf((*MockPolicyChangeRepository)(nil).ReadPolicyChangesAfter), ctx, revision)
+}
@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: policy_outbox_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=policy_outbox_repository.go -destination=./mock/policy_outbox_repository_mock.go -package=repomock
+//
+
+// Package repomock is a generated GoMock package.
+package repomock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/mrhumster/web-server-gin/internal/domain/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPolicyOutboxRepository is a mock of PolicyOutboxRepository interface.
+type MockPolicyOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicyOutboxRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPolicyOutboxRepositoryMockRecorder is the mock recorder for MockPolicyOutboxRepository.
+type MockPolicyOutboxRepositoryMockRecorder struct {
+	mock *MockPolicyOutboxRepository
+}
+
+// NewMockPolicyOutboxRepository creates a new mock instance.
+func NewMockPolicyOutboxRepository(ctrl *gomock.Controller) *MockPolicyOutboxRepository {
+	mock := &MockPolicyOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockPolicyOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPolicyOutboxRepository) EXPECT() *MockPolicyOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimPolicyOperations mocks base method.
+func (m *MockPolicyOutboxRepository) ClaimPolicyOperations(ctx context.Context, limit int, lease time.Duration) ([]models.PolicyOperation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPolicyOperations", ctx, limit, lease)
+	ret0, _ := ret[0].([]models.PolicyOperation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPolicyOperations indicates an expected call of ClaimPolicyOperations.
+func (mr *MockPolicyOutboxRepositoryMockRecorder) ClaimPolicyOperations(ctx, limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPolicyOperations", reflect.TypeOf((*MockPolicyOutboxRepository)(nil).ClaimPolicyOperations), ctx, limit, lease)
+}
+
+// DeletePolicyOperation mocks base method.
+func (m *MockPolicyOutboxRepository) DeletePolicyOperation(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePolicyOperation", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePolicyOperation indicates an expected call of DeletePolicyOperation.
+func (mr *MockPolicyOutboxRepositoryMockRecorder) DeletePolicyOperation(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePolicyOperation", reflect.TypeOf((*MockPolicyOutboxRepository)(nil).DeletePolicyOperation), ctx, id)
+}
+
+// FailPolicyOperation mocks base method.
+func (m *MockPolicyOutboxRepository) FailPolicyOperation(ctx context.Context, id uint64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailPolicyOperation", ctx, id, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailPolicyOperation indicates an expected call of FailPolicyOperation.
+func (mr *MockPolicyOutboxRepositoryMockRecorder) FailPolicyOperation(ctx, id, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailPolicyOperation", reflect.TypeOf((*MockPolicyOutboxRepository)(nil).FailPolicyOperation), ctx, id, reason)
+}
+
+// PolicyOutboxStats mocks base method.
+func (m *MockPolicyOutboxRepository) PolicyOutboxStats(ctx context.Context) (models.PolicyOutboxStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PolicyOutboxStats", ctx)
+	ret0, _ := ret[0].(models.PolicyOutboxStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PolicyOutboxStats indicates an expected call of PolicyOutboxStats.
+func (mr *MockPolicyOutboxRepositoryMockRecorder) PolicyOutboxStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PolicyOutboxStats", reflect.TypeOf((*MockPolicyOutboxRepository)(nil).PolicyOutboxStats), ctx)
+}
+
+// RetryPolicyOperation mocks base method.
+func (m *MockPolicyOutboxRepository) RetryPolicyOperation(ctx context.Context, id uint64, next time.Time, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryPolicyOperation", ctx, id, next, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryPolicyOperation indicates an expected call of RetryPolicyOperation.
+func (mr *MockPolicyOutboxRepositoryMockRecorder) RetryPolicyOperation(ctx, id, next, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryPolicyOperation", reflect.TypeOf((*MockPolicyOutboxRepository)(nil).RetryPolicyOperation), ctx, id, next, reason)
+}
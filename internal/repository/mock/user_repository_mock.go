@@ -44,32 +44,46 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 }
 
 // CreateUser mocks base method.
-func (m *MockUserRepository) CreateUser(ctx context.Context, user models.User) (*uuid.UUID, error) {
+func (m *MockUserRepository) CreateUser(ctx context.Context, user models.User, ops []models.PolicyOperation) (*uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", ctx, user)
+	ret := m.ctrl.Call(m, "CreateUser", ctx, user, ops)
 	ret0, _ := ret[0].(*uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateUser indicates an expected call of CreateUser.
-func (mr *MockUserRepositoryMockRecorder) CreateUser(ctx, user any) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) CreateUser(ctx, user, ops any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepository)(nil).CreateUser), ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepository)(nil).CreateUser), ctx, user, ops)
 }
 
 // DeleteUserByID mocks base method.
-func (m *MockUserRepository) DeleteUserByID(ctx context.Context, id uuid.UUID) error {
+func (m *MockUserRepository) DeleteUserByID(ctx context.Context, id uuid.UUID, ops []models.PolicyOperation) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUserByID", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteUserByID", ctx, id, ops)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteUserByID indicates an expected call of DeleteUserByID.
-func (mr *MockUserRepositoryMockRecorder) DeleteUserByID(ctx, id any) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) DeleteUserByID(ctx, id, ops any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserByID", reflect.TypeOf((*MockUserRepository)(nil).DeleteUserByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserByID", reflect.TypeOf((*MockUserRepository)(nil).DeleteUserByID), ctx, id, ops)
+}
+
+// DisableUser mocks base method.
+func (m *MockUserRepository) DisableUser(ctx context.Context, userID uuid.UUID, tokenVersion string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableUser", ctx, userID, tokenVersion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableUser indicates an expected call of DisableUser.
+func (mr *MockUserRepositoryMockRecorder) DisableUser(ctx, userID, tokenVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableUser", reflect.TypeOf((*MockUserRepository)(nil).DisableUser), ctx, userID, tokenVersion)
 }
 
 // Exists mocks base method.
@@ -86,6 +100,21 @@ func (mr *MockUserRepositoryMockRecorder) Exists(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockUserRepository)(nil).Exists), ctx, id)
 }
 
+// ReadOrganizationIDsForUser mocks base method.
+func (m *MockUserRepository) ReadOrganizationIDsForUser(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOrganizationIDsForUser", ctx, userID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOrganizationIDsForUser indicates an expected call of ReadOrganizationIDsForUser.
+func (mr *MockUserRepositoryMockRecorder) ReadOrganizationIDsForUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOrganizationIDsForUser", reflect.TypeOf((*MockUserRepository)(nil).ReadOrganizationIDsForUser), ctx, userID)
+}
+
 // ReadUserByEmail mocks base method.
 func (m *MockUserRepository) ReadUserByEmail(ctx context.Context, value string) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -132,6 +161,51 @@ func (mr *MockUserRepositoryMockRecorder) ReadUserList(ctx, l, page any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUserList", reflect.TypeOf((*MockUserRepository)(nil).ReadUserList), ctx, l, page)
 }
 
+// ReadUsersByIDs mocks base method.
+func (m *MockUserRepository) ReadUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadUsersByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadUsersByIDs indicates an expected call of ReadUsersByIDs.
+func (mr *MockUserRepositoryMockRecorder) ReadUsersByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUsersByIDs", reflect.TypeOf((*MockUserRepository)(nil).ReadUsersByIDs), ctx, ids)
+}
+
+// SearchUsers mocks base method.
+func (m *MockUserRepository) SearchUsers(ctx context.Context, query string, offset, limit int) ([]models.User, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUsers", ctx, query, offset, limit)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchUsers indicates an expected call of SearchUsers.
+func (mr *MockUserRepositoryMockRecorder) SearchUsers(ctx, query, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockUserRepository)(nil).SearchUsers), ctx, query, offset, limit)
+}
+
+// UpdateRole mocks base method.
+func (m *MockUserRepository) UpdateRole(ctx context.Context, userID uuid.UUID, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockUserRepositoryMockRecorder) UpdateRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserRepository)(nil).UpdateRole), ctx, userID, role)
+}
+
 // UpdateTokenVersion mocks base method.
 func (m *MockUserRepository) UpdateTokenVersion(ctx context.Context, userID *uuid.UUID, version string) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,22 @@
+//go:generate mockgen -source=organization_repository.go -destination=./mock/organization_repository_mock.go -package=repomock
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+)
+
+type OrganizationRepository interface {
+	// CreateOrganization, AddMember, UpdateMemberRole and RemoveMember store
+	// ops in the policy outbox in the same transaction as the change.
+	CreateOrganization(ctx context.Context, org models.Organization, owner models.Membership, ops []models.PolicyOperation) (*uuid.UUID, error)
+	ReadOrganizationByID(ctx context.Context, id uuid.UUID) (*models.Organization, error)
+	ReadOrganizationsForUser(ctx context.Context, userID uuid.UUID) ([]models.Organization, error)
+	AddMember(ctx context.Context, m models.Membership, ops []models.PolicyOperation) error
+	ReadMembership(ctx context.Context, orgID, userID uuid.UUID) (*models.Membership, error)
+	UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role string, ops []models.PolicyOperation) error
+	RemoveMember(ctx context.Context, orgID, userID uuid.UUID, ops []models.PolicyOperation) error
+	ReadMembers(ctx context.Context, orgID uuid.UUID) ([]models.Membership, error)
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+type GormOrganizationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOrganizationRepository(db *gorm.DB) *GormOrganizationRepository {
+	return &GormOrganizationRepository{db: db}
+}
+
+// CreateOrganization stores the organization together with the owner's
+// membership and ops, so an organization never exists without an admin.
+func (r *GormOrganizationRepository) CreateOrganization(ctx context.Context, org models.Organization, owner models.Membership, ops []models.PolicyOperation) (*uuid.UUID, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&org).Error; err != nil {
+			return err
+		}
+		owner.OrganizationID = org.ID
+		if err := tx.Create(&owner).Error; err != nil {
+			return err
+		}
+		return createPolicyOperations(tx, ops)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &org.ID, nil
+}
+
+func (r *GormOrganizationRepository) ReadOrganizationByID(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	var org models.Organization
+	if err := r.db.WithContext(ctx).First(&org, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *GormOrganizationRepository) ReadOrganizationsForUser(ctx context.Context, userID uuid.UUID) ([]models.Organization, error) {
+	var orgs []models.Organization
+	result := r.db.WithContext(ctx).
+		Joins("JOIN memberships ON memberships.organization_id = organizations.id AND memberships.deleted_at IS NULL").
+		Where("memberships.user_id = ?", userID).
+		Order("organizations.created_at DESC").
+		Find(&orgs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return orgs, nil
+}
+
+func (r *GormOrganizationRepository) AddMember(ctx context.Context, m models.Membership, ops []models.PolicyOperation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&m).Error; err != nil {
+			return err
+		}
+		return createPolicyOperations(tx, ops)
+	})
+}
+
+func (r *GormOrganizationRepository) ReadMembership(ctx context.Context, orgID, userID uuid.UUID) (*models.Membership, error) {
+	var m models.Membership
+	err := r.db.WithContext(ctx).
+		First(&m, "organization_id = ? AND user_id = ?", orgID, userID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *GormOrganizationRepository) UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role string, ops []models.PolicyOperation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Membership{}).
+			Where("organization_id = ? AND user_id = ?", orgID, userID).
+			Update("role", role)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return createPolicyOperations(tx, ops)
+	})
+}
+
+// RemoveMember hard-deletes the membership so the user can be added again
+// without hitting the unique (organization, user) index.
+func (r *GormOrganizationRepository) RemoveMember(ctx context.Context, orgID, userID uuid.UUID, ops []models.PolicyOperation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().
+			Where("organization_id = ? AND user_id = ?", orgID, userID).
+			Delete(&models.Membership{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return createPolicyOperations(tx, ops)
+	})
+}
+
+func (r *GormOrganizationRepository) ReadMembers(ctx context.Context, orgID uuid.UUID) ([]models.Membership, error) {
+	var members []models.Membership
+	result := r.db.WithContext(ctx).
+		Where("organization_id = ?", orgID).
+		Order("created_at ASC").
+		Find(&members)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return members, nil
+}
+
+// createPolicyOperations stores ops in the policy outbox within tx.
+func createPolicyOperations(tx *gorm.DB, ops []models.PolicyOperation) error {
+	if len(ops) == 0 {
+		return nil
+	}
+	return tx.Create(&ops).Error
+}
@@ -0,0 +1,18 @@
+//go:generate mockgen -source=policy_change_repository.go -destination=./mock/policy_change_repository_mock.go -package=repomock
+package repository
+
+import (
+	"context"
+
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+)
+
+type PolicyChangeRepository interface {
+	CreatePolicyChanges(ctx context.Context, changes []models.PolicyChange) error
+	// ReadPolicyChanges returns up to limit changes matching f, newest first.
+	ReadPolicyChanges(ctx context.Context, f models.PolicyChangeFilter, limit int) ([]models.PolicyChange, error)
+	// ReadPolicyChangesAfter returns every change newer than revision, oldest
+	// first.
+	ReadPolicyChangesAfter(ctx context.Context, revision uint64) ([]models.PolicyChange, error)
+	LatestPolicyRevision(ctx context.Context) (uint64, error)
+}
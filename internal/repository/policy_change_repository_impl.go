@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+type GormPolicyChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPolicyChangeRepository(db *gorm.DB) *GormPolicyChangeRepository {
+	return &GormPolicyChangeRepository{db: db}
+}
+
+func (r *GormPolicyChangeRepository) CreatePolicyChanges(ctx context.Context, changes []models.PolicyChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(changes, 500).Error
+}
+
+func (r *GormPolicyChangeRepository) ReadPolicyChanges(ctx context.Context, f models.PolicyChangeFilter, limit int) ([]models.PolicyChange, error) {
+	q := r.db.WithContext(ctx).Model(&models.PolicyChange{})
+	if f.Actor != "" {
+		q = q.Where("actor = ?", f.Actor)
+	}
+	if f.Service != "" {
+		q = q.Where("service = ?", f.Service)
+	}
+	if f.Subject != "" {
+		q = q.Where("subject = ?", f.Subject)
+	}
+	if f.Domain != "" {
+		q = q.Where("domain = ?", f.Domain)
+	}
+	if !f.Since.IsZero() {
+		q = q.Where("created_at >= ?", f.Since)
+	}
+	if !f.Until.IsZero() {
+		q = q.Where("created_at < ?", f.Until)
+	}
+	if f.BeforeRevision > 0 {
+		q = q.Where("id < ?", f.BeforeRevision)
+	}
+	var changes []models.PolicyChange
+	if err := q.Order("id DESC").Limit(limit).Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (r *GormPolicyChangeRepository) ReadPolicyChangesAfter(ctx context.Context, revision uint64) ([]models.PolicyChange, error) {
+	var changes []models.PolicyChange
+	err := r.db.WithContext(ctx).Where("id > ?", revision).Order("id").Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (r *GormPolicyChangeRepository) LatestPolicyRevision(ctx context.Context) (uint64, error) {
+	var revision uint64
+	err := r.db.WithContext(ctx).Model(&models.PolicyChange{}).Select("COALESCE(MAX(id), 0)").Scan(&revision).Error
+	return revision, err
+}
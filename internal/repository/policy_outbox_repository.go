@@ -0,0 +1,24 @@
+//go:generate mockgen -source=policy_outbox_repository.go -destination=./mock/policy_outbox_repository_mock.go -package=repomock
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+)
+
+type PolicyOutboxRepository interface {
+	// ClaimPolicyOperations returns up to limit operations that are due, the
+	// oldest of each subject only, and hides them from other callers for
+	// lease. Failed operations are left out.
+	ClaimPolicyOperations(ctx context.Context, limit int, lease time.Duration) ([]models.PolicyOperation, error)
+	DeletePolicyOperation(ctx context.Context, id uint64) error
+	// RetryPolicyOperation counts a failed attempt and makes the operation
+	// due again at next.
+	RetryPolicyOperation(ctx context.Context, id uint64, next time.Time, reason string) error
+	// FailPolicyOperation counts a failed attempt and gives up on the
+	// operation, which is kept but never claimed again.
+	FailPolicyOperation(ctx context.Context, id uint64, reason string) error
+	PolicyOutboxStats(ctx context.Context) (models.PolicyOutboxStats, error)
+}
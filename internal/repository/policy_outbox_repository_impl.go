@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormPolicyOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPolicyOutboxRepository(db *gorm.DB) *GormPolicyOutboxRepository {
+	return &GormPolicyOutboxRepository{db: db}
+}
+
+// ClaimPolicyOperations locks the rows it picks with SKIP LOCKED and moves
+// them past the lease in the same transaction, so replicas running the
+// outbox never apply an operation at the same time.
+func (r *GormPolicyOutboxRepository) ClaimPolicyOperations(ctx context.Context, limit int, lease time.Duration) ([]models.PolicyOperation, error) {
+	var ops []models.PolicyOperation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		oldest := tx.Model(&models.PolicyOperation{}).Where("failed_at IS NULL").Select("MIN(id)").Group("subject")
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("next_attempt_at <= ? AND id IN (?)", now, oldest).
+			Order("id").
+			Limit(limit).
+			Find(&ops).Error
+		if err != nil || len(ops) == 0 {
+			return err
+		}
+		ids := make([]uint64, len(ops))
+		for i, op := range ops {
+			ids[i] = op.ID
+		}
+		return tx.Model(&models.PolicyOperation{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+func (r *GormPolicyOutboxRepository) DeletePolicyOperation(ctx context.Context, id uint64) error {
+	return r.db.WithContext(ctx).Delete(&models.PolicyOperation{}, id).Error
+}
+
+func (r *GormPolicyOutboxRepository) RetryPolicyOperation(ctx context.Context, id uint64, next time.Time, reason string) error {
+	return r.db.WithContext(ctx).Model(&models.PolicyOperation{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": next,
+			"last_error":      reason,
+		}).Error
+}
+
+func (r *GormPolicyOutboxRepository) FailPolicyOperation(ctx context.Context, id uint64, reason string) error {
+	return r.db.WithContext(ctx).Model(&models.PolicyOperation{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"failed_at":  time.Now(),
+			"last_error": reason,
+		}).Error
+}
+
+func (r *GormPolicyOutboxRepository) PolicyOutboxStats(ctx context.Context) (models.PolicyOutboxStats, error) {
+	var stats models.PolicyOutboxStats
+	if err := r.db.WithContext(ctx).Model(&models.PolicyOperation{}).Where("failed_at IS NOT NULL").Count(&stats.Failed).Error; err != nil {
+		return stats, err
+	}
+	pending := r.db.WithContext(ctx).Model(&models.PolicyOperation{}).Where("failed_at IS NULL")
+	if err := pending.Session(&gorm.Session{}).Count(&stats.Pending).Error; err != nil || stats.Pending == 0 {
+		return stats, err
+	}
+	if err := pending.Session(&gorm.Session{}).Where("attempts > 0").Count(&stats.Failing).Error; err != nil {
+		return stats, err
+	}
+	var oldest models.PolicyOperation
+	if err := pending.Session(&gorm.Session{}).Order("id").First(&oldest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return stats, nil
+		}
+		return stats, err
+	}
+	stats.OldestAt = &oldest.CreatedAt
+	var failing models.PolicyOperation
+	err := pending.Session(&gorm.Session{}).Where("attempts > 0").Order("id DESC").First(&failing).Error
+	if err == nil {
+		stats.LastError = failing.LastError
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return stats, err
+	}
+	return stats, nil
+}
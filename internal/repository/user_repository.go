@@ -10,12 +10,25 @@ import (
 )
 
 type UserRepository interface {
-	CreateUser(ctx context.Context, user models.User) (*uuid.UUID, error)
+	// CreateUser and DeleteUserByID store ops in the policy outbox in the same
+	// transaction, so the user and its policies can't go out of step.
+	CreateUser(ctx context.Context, user models.User, ops []models.PolicyOperation) (*uuid.UUID, error)
 	ReadUserByID(ctx context.Context, id uuid.UUID) (*models.User, error)
 	UpdateUser(ctx context.Context, id uuid.UUID, user request.UpdateUserRequest) (*uuid.UUID, error)
-	DeleteUserByID(ctx context.Context, id uuid.UUID) error
+	DeleteUserByID(ctx context.Context, id uuid.UUID, ops []models.PolicyOperation) error
 	ReadUserList(ctx context.Context, l, page int64) ([]models.User, int64, error)
+	// ReadUsersByIDs returns the users found, in no particular order.
+	ReadUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]models.User, error)
+	// SearchUsers returns a window of the users whose email contains query,
+	// newest first, and the number of matches. An empty query matches all.
+	SearchUsers(ctx context.Context, query string, offset, limit int) ([]models.User, int64, error)
 	ReadUserByEmail(ctx context.Context, value string) (*models.User, error)
+	// ReadOrganizationIDsForUser returns the organizations the user is a
+	// member of.
+	ReadOrganizationIDsForUser(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
 	Exists(ctx context.Context, id uuid.UUID) bool
 	UpdateTokenVersion(ctx context.Context, userID *uuid.UUID, version string) error
+	UpdateRole(ctx context.Context, userID uuid.UUID, role string) error
+	// DisableUser marks the user disabled and sets a new token version.
+	DisableUser(ctx context.Context, userID uuid.UUID, tokenVersion string) error
 }
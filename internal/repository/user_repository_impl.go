@@ -8,6 +8,7 @@ import (
 	"github.com/sony/gobreaker"
 	"gorm.io/gorm"
 	"log"
+	"strings"
 	"time"
 )
 
@@ -39,10 +40,18 @@ func NewGormUserRepository(db *gorm.DB) *GormUserRepository {
 	return &GormUserRepository{db: db}
 }
 
-func (r *GormUserRepository) CreateUser(ctx context.Context, user models.User) (*uuid.UUID, error) {
-	result := r.db.WithContext(ctx).Create(&user)
-	if result.Error != nil {
-		return nil, result.Error
+func (r *GormUserRepository) CreateUser(ctx context.Context, user models.User, ops []models.PolicyOperation) (*uuid.UUID, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		if len(ops) == 0 {
+			return nil
+		}
+		return tx.Create(&ops).Error
+	})
+	if err != nil {
+		return nil, err
 	}
 	return &user.ID, nil
 }
@@ -70,9 +79,28 @@ func (r *GormUserRepository) UpdateUser(ctx context.Context, id uuid.UUID, user
 	return &userForUpdate.ID, nil
 }
 
-func (r *GormUserRepository) DeleteUserByID(ctx context.Context, id uuid.UUID) error {
-	result := r.db.WithContext(ctx).Delete(&models.User{}, id)
-	return result.Error
+func (r *GormUserRepository) DeleteUserByID(ctx context.Context, id uuid.UUID, ops []models.PolicyOperation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.User{}, id).Error; err != nil {
+			return err
+		}
+		if len(ops) == 0 {
+			return nil
+		}
+		return tx.Create(&ops).Error
+	})
+}
+
+func (r *GormUserRepository) ReadOrganizationIDsForUser(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	result := r.db.WithContext(ctx).
+		Model(&models.Membership{}).
+		Where("user_id = ?", userID).
+		Pluck("organization_id", &ids)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return ids, nil
 }
 
 func (r *GormUserRepository) ReadUserList(ctx context.Context, l, page int64) ([]models.User, int64, error) {
@@ -95,6 +123,38 @@ func (r *GormUserRepository) ReadUserList(ctx context.Context, l, page int64) ([
 	return users, total, nil
 }
 
+func (r *GormUserRepository) ReadUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *GormUserRepository) SearchUsers(ctx context.Context, query string, offset, limit int) ([]models.User, int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.User{})
+	if query != "" {
+		q = q.Where("email ILIKE ?", "%"+escapeLike(query)+"%")
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []models.User
+	if err := q.Order("created_at DESC").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// escapeLike makes the LIKE wildcards in s match themselves.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 func (r *GormUserRepository) ReadUserByEmail(ctx context.Context, value string) (*models.User, error) {
 	var user models.User
 	if err := r.db.WithContext(ctx).Model(&models.User{}).First(&user, "email = ?", value).Error; err != nil {
@@ -118,3 +178,26 @@ func (r *GormUserRepository) UpdateTokenVersion(ctx context.Context, userID *uui
 	result = r.db.WithContext(ctx).Save(&userForUpdate)
 	return result.Error
 }
+
+func (r *GormUserRepository) DisableUser(ctx context.Context, userID uuid.UUID, tokenVersion string) error {
+	result := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).
+		Updates(map[string]any{"disabled": true, "token_version": tokenVersion})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormUserRepository) UpdateRole(ctx context.Context, userID uuid.UUID, role string) error {
+	result := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
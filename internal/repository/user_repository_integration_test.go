@@ -30,7 +30,7 @@ func TestUserRepository_CreateRepo(t *testing.T) {
 
 	t.Run("Create and Read user", func(t *testing.T) {
 		user := generateUniqueUser()
-		id, err := repo.CreateUser(ctx, user)
+		id, err := repo.CreateUser(ctx, user, nil)
 		if id == nil {
 			t.Errorf("⚠️ Error create user: %v", err)
 		}
@@ -54,12 +54,12 @@ func TestUserRepository_ReadList(t *testing.T) {
 			PasswordHash: "#########",
 			Email:        "testuser@test.local",
 		}
-		repo.CreateUser(ctx, user)
+		repo.CreateUser(ctx, user, nil)
 		user = models.User{
 			PasswordHash: "#########",
 			Email:        "testuser2@test.local",
 		}
-		repo.CreateUser(ctx, user)
+		repo.CreateUser(ctx, user, nil)
 		users, _, err := repo.ReadUserList(ctx, 2, 1)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, users)
@@ -78,7 +78,7 @@ func TestUserRepository_ReadByEmail(t *testing.T) {
 			Email:        "billy@test.local",
 			PasswordHash: "***1234***",
 		}
-		id, err := repo.CreateUser(ctx, user)
+		id, err := repo.CreateUser(ctx, user, nil)
 		assert.NotEmpty(t, id)
 		assert.NoError(t, err)
 		u, _ := repo.ReadUserByID(ctx, *id)
@@ -99,7 +99,7 @@ func TestUserRepository_UserExist(t *testing.T) {
 			Email:        "billy@test.local",
 			PasswordHash: "***1234***",
 		}
-		id, err := repo.CreateUser(ctx, user)
+		id, err := repo.CreateUser(ctx, user, nil)
 		if err != nil {
 			t.Error(err.Error())
 		}
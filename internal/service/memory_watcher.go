@@ -0,0 +1,109 @@
+package service
+
+import (
+	"sync"
+)
+
+// MemoryHub connects the memory watchers of one process, e.g. several
+// permission services in a test.
+type MemoryHub struct {
+	mu       sync.RWMutex
+	watchers map[*MemoryWatcher]struct{}
+}
+
+func NewMemoryHub() *MemoryHub {
+	return &MemoryHub{watchers: make(map[*MemoryWatcher]struct{})}
+}
+
+func (h *MemoryHub) broadcast(from *MemoryWatcher, msg string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for w := range h.watchers {
+		if w != from {
+			w.enqueue(msg)
+		}
+	}
+}
+
+// MemoryWatcher passes policy changes to the other watchers of its hub. A
+// single process needs nothing more: its own changes are applied already.
+// Messages are delivered in order on a goroutine of the receiving watcher, so
+// a service publishing under its lock never waits for another one.
+type MemoryWatcher struct {
+	watcherMessages
+	hub *MemoryHub
+
+	mu       sync.Mutex
+	queue    []string
+	callback func(string)
+	wake     chan struct{}
+	done     chan struct{}
+}
+
+// NewMemoryWatcher joins hub; with a nil hub the watcher has no peers.
+func NewMemoryWatcher(hub *MemoryHub) *MemoryWatcher {
+	if hub == nil {
+		hub = NewMemoryHub()
+	}
+	w := &MemoryWatcher{hub: hub, wake: make(chan struct{}, 1), done: make(chan struct{})}
+	w.watcherMessages = newWatcherMessages(func(data []byte) error {
+		hub.broadcast(w, string(data))
+		return nil
+	})
+	hub.mu.Lock()
+	hub.watchers[w] = struct{}{}
+	hub.mu.Unlock()
+	go w.deliver()
+	return w
+}
+
+func (w *MemoryWatcher) SetUpdateCallback(callback func(string)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callback = callback
+	return nil
+}
+
+func (w *MemoryWatcher) enqueue(msg string) {
+	w.mu.Lock()
+	w.queue = append(w.queue, msg)
+	w.mu.Unlock()
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *MemoryWatcher) deliver() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.wake:
+		}
+		for {
+			w.mu.Lock()
+			if len(w.queue) == 0 {
+				w.mu.Unlock()
+				break
+			}
+			msg, callback := w.queue[0], w.callback
+			w.queue = w.queue[1:]
+			w.mu.Unlock()
+			if callback != nil {
+				callback(msg)
+			}
+		}
+	}
+}
+
+// Close leaves the hub. Messages not delivered yet are dropped.
+func (w *MemoryWatcher) Close() {
+	w.hub.mu.Lock()
+	_, joined := w.hub.watchers[w]
+	delete(w.hub.watchers, w)
+	w.hub.mu.Unlock()
+	if joined {
+		close(w.done)
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"github.com/mrhumster/web-server-gin/internal/repository"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrganizationNotFound    = errors.New("organization not found")
+	ErrOrganizationExists      = errors.New("organization with this slug already exists")
+	ErrMembershipNotFound      = errors.New("membership not found")
+	ErrMembershipAlreadyExists = errors.New("user is already a member of the organization")
+	ErrInvalidOrgRole          = errors.New("invalid organization role")
+	ErrLastOrganizationAdmin   = errors.New("organization must keep at least one admin")
+)
+
+// OrganizationService manages organizations and their members. Every
+// organization is its own Casbin domain, keyed by the organization ID, and
+// member roles are granted only inside that domain. The policies and role
+// links go through the policy outbox, stored in the same transaction as the
+// organization or membership they belong to.
+type OrganizationService struct {
+	repo   repository.OrganizationRepository
+	users  repository.UserRepository
+	outbox *PolicyOutbox
+}
+
+func NewOrganizationService(repo repository.OrganizationRepository, users repository.UserRepository) *OrganizationService {
+	return &OrganizationService{repo: repo, users: users}
+}
+
+// SetPolicyOutbox wakes outbox whenever an organization's policy operations
+// are stored, so they are applied right away.
+func (s *OrganizationService) SetPolicyOutbox(outbox *PolicyOutbox) {
+	s.outbox = outbox
+}
+
+func (s *OrganizationService) CreateOrganization(ctx context.Context, org models.Organization, ownerID uuid.UUID) (*models.Organization, error) {
+	if org.ID == uuid.Nil {
+		org.ID = uuid.New()
+	}
+	domain := org.ID.String()
+	resource := fmt.Sprintf("orgs/%s", domain)
+	rules := [][]string{
+		{"p", models.OrgRoleAdmin, domain, resource, "read"},
+		{"p", models.OrgRoleAdmin, domain, resource, "write"},
+		{"p", models.OrgRoleMember, domain, resource, "read"},
+		{"g", ownerID.String(), models.OrgRoleAdmin, domain},
+	}
+	owner := models.Membership{UserID: ownerID, Role: models.OrgRoleAdmin}
+	actor, _ := auth.ActorFromContext(ctx)
+	id, err := s.repo.CreateOrganization(ctx, org, owner, policyOperations(models.PolicyChangeAdd, rules, actor))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrOrganizationExists
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrOrganizationExists
+		}
+		return nil, err
+	}
+	org.ID = *id
+	s.notifyOutbox()
+	return &org, nil
+}
+
+func (s *OrganizationService) ReadOrganization(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	org, err := s.repo.ReadOrganizationByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOrganizationNotFound
+	}
+	return org, err
+}
+
+func (s *OrganizationService) ListOrganizations(ctx context.Context, userID uuid.UUID) ([]models.Organization, error) {
+	return s.repo.ReadOrganizationsForUser(ctx, userID)
+}
+
+func (s *OrganizationService) ReadMembership(ctx context.Context, orgID, userID uuid.UUID) (*models.Membership, error) {
+	m, err := s.repo.ReadMembership(ctx, orgID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMembershipNotFound
+	}
+	return m, err
+}
+
+func (s *OrganizationService) ListMembers(ctx context.Context, orgID uuid.UUID) ([]models.Membership, error) {
+	if _, err := s.ReadOrganization(ctx, orgID); err != nil {
+		return nil, err
+	}
+	return s.repo.ReadMembers(ctx, orgID)
+}
+
+func (s *OrganizationService) AddMember(ctx context.Context, orgID, userID uuid.UUID, role string) error {
+	if !isOrgRole(role) {
+		return ErrInvalidOrgRole
+	}
+	if _, err := s.ReadOrganization(ctx, orgID); err != nil {
+		return err
+	}
+	if !s.users.Exists(ctx, userID) {
+		return ErrUserNotFount
+	}
+	if _, err := s.repo.ReadMembership(ctx, orgID, userID); err == nil {
+		return ErrMembershipAlreadyExists
+	}
+	m := models.Membership{OrganizationID: orgID, UserID: userID, Role: role}
+	if err := s.repo.AddMember(ctx, m, memberOperations(ctx, models.PolicyChangeAdd, m)); err != nil {
+		return err
+	}
+	s.notifyOutbox()
+	return nil
+}
+
+func (s *OrganizationService) UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role string) error {
+	if !isOrgRole(role) {
+		return ErrInvalidOrgRole
+	}
+	m, err := s.ReadMembership(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if m.Role == role {
+		return nil
+	}
+	if m.Role == models.OrgRoleAdmin {
+		if err := s.ensureAnotherAdmin(ctx, orgID, userID); err != nil {
+			return err
+		}
+	}
+	ops := memberOperations(ctx, models.PolicyChangeRemove, *m)
+	m.Role = role
+	ops = append(ops, memberOperations(ctx, models.PolicyChangeAdd, *m)...)
+	if err := s.repo.UpdateMemberRole(ctx, orgID, userID, role, ops); err != nil {
+		return err
+	}
+	s.notifyOutbox()
+	return nil
+}
+
+func (s *OrganizationService) RemoveMember(ctx context.Context, orgID, userID uuid.UUID) error {
+	m, err := s.ReadMembership(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if m.Role == models.OrgRoleAdmin {
+		if err := s.ensureAnotherAdmin(ctx, orgID, userID); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.RemoveMember(ctx, orgID, userID, memberOperations(ctx, models.PolicyChangeRemove, *m)); err != nil {
+		return err
+	}
+	s.notifyOutbox()
+	return nil
+}
+
+// memberOperations builds the outbox operation granting or revoking the
+// member's role in the organization's domain.
+func memberOperations(ctx context.Context, op string, m models.Membership) []models.PolicyOperation {
+	actor, _ := auth.ActorFromContext(ctx)
+	rule := []string{"g", m.UserID.String(), m.Role, m.OrganizationID.String()}
+	return policyOperations(op, [][]string{rule}, actor)
+}
+
+func (s *OrganizationService) notifyOutbox() {
+	if s.outbox != nil {
+		s.outbox.Notify()
+	}
+}
+
+func (s *OrganizationService) ensureAnotherAdmin(ctx context.Context, orgID, userID uuid.UUID) error {
+	members, err := s.repo.ReadMembers(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if m.Role == models.OrgRoleAdmin && m.UserID != userID {
+			return nil
+		}
+	}
+	return ErrLastOrganizationAdmin
+}
+
+func isOrgRole(role string) bool {
+	return role == models.OrgRoleAdmin || role == models.OrgRoleMember
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	repomock "github.com/mrhumster/web-server-gin/internal/repository/mock"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestOrganizationService_CreateOrganization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockOrganizationRepository(ctrl)
+	users := repomock.NewMockUserRepository(ctrl)
+	service := NewOrganizationService(repo, users)
+
+	ownerID := uuid.New()
+	orgID := uuid.New()
+	domain := orgID.String()
+	resource := "orgs/" + domain
+	op := func(ptype string, rule ...string) models.PolicyOperation {
+		return models.PolicyOperation{Op: models.PolicyChangeAdd, PType: ptype, Rule: rule, Subject: rule[0], Actor: "admin-1"}
+	}
+
+	repo.EXPECT().
+		CreateOrganization(gomock.Any(), models.Organization{BaseModel: models.BaseModel{ID: orgID}, Name: "Acme", Slug: "acme"},
+			models.Membership{UserID: ownerID, Role: models.OrgRoleAdmin},
+			[]models.PolicyOperation{
+				op("p", models.OrgRoleAdmin, domain, resource, "read"),
+				op("p", models.OrgRoleAdmin, domain, resource, "write"),
+				op("p", models.OrgRoleMember, domain, resource, "read"),
+				op("g", ownerID.String(), models.OrgRoleAdmin, domain),
+			}).
+		Return(&orgID, nil)
+
+	ctx := auth.WithActor(context.Background(), "admin-1")
+	org, err := service.CreateOrganization(ctx, models.Organization{BaseModel: models.BaseModel{ID: orgID}, Name: "Acme", Slug: "acme"}, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, orgID, org.ID)
+	assert.Equal(t, "acme", org.Slug)
+}
+
+func TestOrganizationService_AddMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockOrganizationRepository(ctrl)
+	users := repomock.NewMockUserRepository(ctrl)
+	service := NewOrganizationService(repo, users)
+
+	orgID := uuid.New()
+	userID := uuid.New()
+
+	err := service.AddMember(context.Background(), orgID, userID, models.RoleAdmin)
+	assert.ErrorIs(t, err, ErrInvalidOrgRole)
+
+	repo.EXPECT().ReadOrganizationByID(gomock.Any(), orgID).Return(&models.Organization{}, nil)
+	users.EXPECT().Exists(gomock.Any(), userID).Return(true)
+	repo.EXPECT().ReadMembership(gomock.Any(), orgID, userID).Return(nil, gorm.ErrRecordNotFound)
+	repo.EXPECT().AddMember(gomock.Any(), models.Membership{
+		OrganizationID: orgID,
+		UserID:         userID,
+		Role:           models.OrgRoleMember,
+	}, []models.PolicyOperation{{
+		Op:      models.PolicyChangeAdd,
+		PType:   "g",
+		Rule:    []string{userID.String(), models.OrgRoleMember, orgID.String()},
+		Subject: userID.String(),
+	}}).Return(nil)
+
+	err = service.AddMember(context.Background(), orgID, userID, models.OrgRoleMember)
+	require.NoError(t, err)
+}
+
+func TestOrganizationService_RemoveLastAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockOrganizationRepository(ctrl)
+	users := repomock.NewMockUserRepository(ctrl)
+	service := NewOrganizationService(repo, users)
+
+	orgID := uuid.New()
+	adminID := uuid.New()
+	admin := models.Membership{OrganizationID: orgID, UserID: adminID, Role: models.OrgRoleAdmin}
+
+	repo.EXPECT().ReadMembership(gomock.Any(), orgID, adminID).Return(&admin, nil)
+	repo.EXPECT().ReadMembers(gomock.Any(), orgID).Return([]models.Membership{admin}, nil)
+
+	err := service.RemoveMember(context.Background(), orgID, adminID)
+	assert.ErrorIs(t, err, ErrLastOrganizationAdmin)
+}
+
+func TestOrganizationService_UpdateMemberRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockOrganizationRepository(ctrl)
+	users := repomock.NewMockUserRepository(ctrl)
+	service := NewOrganizationService(repo, users)
+
+	orgID := uuid.New()
+	userID := uuid.New()
+	link := func(op, role string) models.PolicyOperation {
+		return models.PolicyOperation{
+			Op:      op,
+			PType:   "g",
+			Rule:    []string{userID.String(), role, orgID.String()},
+			Subject: userID.String(),
+		}
+	}
+
+	repo.EXPECT().ReadMembership(gomock.Any(), orgID, userID).
+		Return(&models.Membership{OrganizationID: orgID, UserID: userID, Role: models.OrgRoleMember}, nil)
+	repo.EXPECT().UpdateMemberRole(gomock.Any(), orgID, userID, models.OrgRoleAdmin, []models.PolicyOperation{
+		link(models.PolicyChangeRemove, models.OrgRoleMember),
+		link(models.PolicyChangeAdd, models.OrgRoleAdmin),
+	}).Return(nil)
+
+	err := service.UpdateMemberRole(context.Background(), orgID, userID, models.OrgRoleAdmin)
+	require.NoError(t, err)
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/util"
+	"github.com/casbin/govaluate"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+)
+
+// abacContext selects the r2/p2/e2/m2 section of model.conf, which holds the
+// attribute-based rules.
+var abacContext = casbin.NewEnforceContext("2")
+
+var ErrInvalidCondition = errors.New("invalid policy condition")
+
+// Attributes are the ABAC inputs of a permission check. Conditions read them
+// as r2.attrs.subject.<key>, r2.attrs.resource.<key> and r2.attrs.env.<key>.
+type Attributes struct {
+	Subject  map[string]string
+	Resource map[string]string
+	Env      map[string]string
+}
+
+func (a Attributes) IsEmpty() bool {
+	return len(a.Subject) == 0 && len(a.Resource) == 0 && len(a.Env) == 0
+}
+
+// toRequest builds the attrs value handed to the enforcer, see
+// auth.Attributes.ConditionInput.
+func (a Attributes) toRequest(sub string, now time.Time) map[string]any {
+	return auth.Attributes{Subject: a.Subject, Resource: a.Resource, Environment: a.Env}.ConditionInput(sub, now)
+}
+
+// validateCondition rejects conditions the matcher could never evaluate, so
+// a typo fails when the rule is added rather than on every check.
+func validateCondition(cond string) error {
+	if cond == "" {
+		return fmt.Errorf("%w: empty", ErrInvalidCondition)
+	}
+	fm := model.LoadFunctionMap()
+	if _, err := govaluate.NewEvaluableExpressionWithFunctions(util.EscapeAssertion(cond), fm.GetFunctions()); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidCondition, err)
+	}
+	return nil
+}
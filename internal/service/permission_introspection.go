@@ -0,0 +1,236 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/casbin/casbin/v2/util"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+)
+
+// Permission is a policy line as seen by introspection. Condition is set for
+// ABAC rules only.
+type Permission struct {
+	Subject   string
+	Domain    string
+	Object    string
+	Action    string
+	Condition string
+}
+
+const (
+	ReasonPolicy            = "matched policy"
+	ReasonConditionalPolicy = "matched conditional policy"
+	ReasonNoMatch           = "no matching policy"
+)
+
+// Explanation describes how a check was decided.
+type Explanation struct {
+	Allowed bool
+	Matched []Permission
+	Roles   []string
+	Reason  string
+}
+
+// ExplainPermission decides like CheckPermission and reports the policy that
+// granted access, or that none did, along with the subject's roles.
+func (p *PermissionService) ExplainPermission(subj, dom, obj, act string, attrs Attributes) (*Explanation, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	roles, err := p.implicitRoles(subj, dom)
+	if err != nil {
+		return nil, err
+	}
+	ex := &Explanation{Roles: roles, Reason: ReasonNoMatch}
+
+	allowed, rule, err := p.enforcer.EnforceEx(subj, dom, obj, act)
+	if err != nil {
+		return nil, err
+	}
+	if allowed {
+		ex.Allowed = true
+		ex.Reason = ReasonPolicy
+		ex.Matched = explainedRules(rule)
+		return ex, nil
+	}
+
+	if rules, ok := p.enforcer.GetModel()["p"]["p2"]; !ok || len(rules.Policy) == 0 {
+		return ex, nil
+	}
+	allowed, rule, err = p.enforcer.EnforceEx(abacContext, subj, dom, obj, act, attrs.toRequest(subj, p.now()))
+	if err != nil {
+		ex.Reason = fmt.Sprintf("%s: condition not evaluated: %s", ReasonNoMatch, err)
+		return ex, nil
+	}
+	if allowed {
+		ex.Allowed = true
+		ex.Reason = ReasonConditionalPolicy
+		ex.Matched = explainedRules(rule)
+	}
+	return ex, nil
+}
+
+func explainedRules(rule []string) []Permission {
+	if len(rule) < 4 {
+		return nil
+	}
+	perm := Permission{Subject: rule[0], Domain: rule[1], Object: rule[2], Action: rule[3]}
+	if len(rule) > 4 {
+		perm.Condition = rule[4]
+	}
+	return []Permission{perm}
+}
+
+// GetImplicitRolesForUser returns the roles the user holds in dom, directly or
+// through the role hierarchy, including the platform roles that apply in
+// every domain.
+func (p *PermissionService) GetImplicitRolesForUser(sub, dom string) ([]string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.implicitRoles(sub, dom)
+}
+
+// ListPermissionsForSubject returns every policy that can grant sub access in
+// dom. Owner ("self") policies are reported with the subject substituted into
+// the object pattern.
+func (p *PermissionService) ListPermissionsForSubject(sub, dom string) ([]Permission, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	roles, err := p.implicitRoles(sub, dom)
+	if err != nil {
+		return nil, err
+	}
+	subjects := append([]string{sub}, roles...)
+
+	var result []Permission
+	for _, ptype := range []string{"p", "p2"} {
+		rules, ok := p.enforcer.GetModel()["p"][ptype]
+		if !ok {
+			continue
+		}
+		for _, rule := range rules.Policy {
+			if len(rule) < 4 || !domainApplies(rule[1], dom) {
+				continue
+			}
+			perm := Permission{Subject: rule[0], Domain: rule[1], Object: rule[2], Action: rule[3]}
+			if ptype == "p2" && len(rule) > 4 {
+				perm.Condition = rule[4]
+			}
+			switch {
+			case slices.Contains(subjects, rule[0]):
+			case rule[0] == models.SelfSubject:
+				perm.Object = strings.ReplaceAll(rule[2], ":id", sub)
+			default:
+				continue
+			}
+			result = append(result, perm)
+		}
+	}
+	return result, nil
+}
+
+// ListSubjectsForResource returns the sorted users and roles that RBAC
+// policies allow to perform act on obj in dom. An empty act matches any
+// action.
+func (p *PermissionService) ListSubjectsForResource(obj, act, dom string) ([]string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	seen := map[string]struct{}{}
+	for _, rule := range p.enforcer.GetModel()["p"]["p"].Policy {
+		if len(rule) < 4 || !domainApplies(rule[1], dom) || !util.KeyMatch2(obj, rule[2]) {
+			continue
+		}
+		if act != "" && rule[3] != act {
+			continue
+		}
+		if rule[0] == models.SelfSubject {
+			if owner := util.KeyGet2(obj, rule[2], "id"); owner != "" {
+				seen[owner] = struct{}{}
+			}
+			continue
+		}
+		seen[rule[0]] = struct{}{}
+		members, err := p.implicitUsers(rule[0], dom)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			seen[m] = struct{}{}
+		}
+	}
+
+	subjects := make([]string, 0, len(seen))
+	for s := range seen {
+		subjects = append(subjects, s)
+	}
+	slices.Sort(subjects)
+	return subjects, nil
+}
+
+// implicitRoles mirrors the matcher, which accepts role links made in the
+// request domain or in the global one. The caller holds the read lock.
+func (p *PermissionService) implicitRoles(sub, dom string) ([]string, error) {
+	roles, err := p.enforcer.GetImplicitRolesForUser(sub, models.GlobalDomain)
+	if err != nil {
+		return nil, err
+	}
+	if dom != models.GlobalDomain {
+		domainRoles, err := p.enforcer.GetImplicitRolesForUser(sub, dom)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, domainRoles...)
+	}
+	slices.Sort(roles)
+	return slices.Compact(roles), nil
+}
+
+func (p *PermissionService) implicitUsers(role, dom string) ([]string, error) {
+	users, err := p.enforcer.GetImplicitUsersForRole(role, models.GlobalDomain)
+	if err != nil {
+		return nil, err
+	}
+	if dom != models.GlobalDomain {
+		domainUsers, err := p.enforcer.GetImplicitUsersForRole(role, dom)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, domainUsers...)
+	}
+	return users, nil
+}
+
+func domainApplies(policyDomain, dom string) bool {
+	return policyDomain == models.GlobalDomain || policyDomain == dom
+}
+
+// PolicyRule is a raw rule of the model: its type (p, p2, g) and values.
+type PolicyRule struct {
+	PType  string
+	Values []string
+}
+
+// PolicySnapshot copies every policy and grouping rule, policies first.
+func (p *PermissionService) PolicySnapshot() []PolicyRule {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var rules []PolicyRule
+	m := p.enforcer.GetModel()
+	for _, sec := range []string{"p", "g"} {
+		ptypes := make([]string, 0, len(m[sec]))
+		for ptype := range m[sec] {
+			ptypes = append(ptypes, ptype)
+		}
+		slices.Sort(ptypes)
+		for _, ptype := range ptypes {
+			for _, rule := range m[sec][ptype].Policy {
+				rules = append(rules, PolicyRule{PType: ptype, Values: slices.Clone(rule)})
+			}
+		}
+	}
+	return rules
+}
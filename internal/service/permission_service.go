@@ -5,12 +5,12 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/casbin/casbin/v2/persist"
-	rediswatcher "github.com/casbin/redis-watcher/v2"
-	"github.com/mrhumster/web-server-gin/config"
-	"github.com/redis/go-redis/v9"
+	"github.com/mrhumster/web-server-gin/internal/repository"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
 )
 
 type PermissionClient interface {
@@ -18,6 +18,24 @@ type PermissionClient interface {
 	AddPolicy(ctx context.Context, userID, resource, action string) (bool, error)
 	RemovePolicy(ctx context.Context, userID, resource, action string) (bool, error)
 	AddPolicyIfNotExists(ctx context.Context, userID, resource, action string) (bool, error)
+	AssignRole(ctx context.Context, userID, role string) (bool, error)
+	UnassignRole(ctx context.Context, userID, role string) (bool, error)
+	GetRoleMembers(ctx context.Context, role string) ([]string, error)
+	CheckPermissionInDomain(ctx context.Context, userID, domain, resource, action string) (bool, error)
+	AddPolicyInDomain(ctx context.Context, userID, domain, resource, action string) (bool, error)
+	AssignRoleInDomain(ctx context.Context, userID, role, domain string) (bool, error)
+	UnassignRoleInDomain(ctx context.Context, userID, role, domain string) (bool, error)
+	BatchCheckPermission(ctx context.Context, checks []auth.CheckRequest) ([]bool, error)
+	ListPermissionsForSubject(ctx context.Context, subject, domain string, pageSize int32, pageToken string) ([]auth.Permission, string, error)
+	GetImplicitRolesForUser(ctx context.Context, userID, domain string) ([]string, error)
+	AddConditionalPolicy(ctx context.Context, subject, domain, resource, action, condition string) (bool, error)
+	RemoveConditionalPolicy(ctx context.Context, subject, domain, resource, action, condition string) (bool, error)
+	RemovePolicyInDomain(ctx context.Context, subject, domain, resource, action string) (bool, error)
+	ListPolicies(ctx context.Context, filter auth.PolicyFilter, pageSize int32, pageToken string) ([]auth.Permission, string, error)
+	ReplacePolicies(ctx context.Context, filter auth.PolicyFilter, policies []auth.Permission) (added, removed int, err error)
+	ListRoles(ctx context.Context, domain string) ([]string, error)
+	ListPolicyChanges(ctx context.Context, filter auth.PolicyChangeFilter, pageSize int32, pageToken string) ([]auth.PolicyChange, string, error)
+	RevertPolicies(ctx context.Context, revision uint64, dryRun bool) (*auth.PolicyPlan, error)
 	Close() error
 }
 
@@ -25,73 +43,212 @@ type PermissionService struct {
 	enforcer *casbin.Enforcer
 	watcher  persist.Watcher
 	mu       sync.RWMutex
+	events   *policyLog
+	history  repository.PolicyChangeRepository
+	// now is the time conditions are evaluated at.
+	now func() time.Time
 }
 
-func NewPermissionService(e *casbin.Enforcer, cfg config.Redis) (*PermissionService, error) {
-	ps := &PermissionService{
-		enforcer: e,
-	}
-
-	slog.Info("Redis conn password ", "length", len(cfg.Password))
-
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     cfg.Addr,
-		Password: cfg.Password,
-		DB:       0,
-	})
+func newPermissionService(e *casbin.Enforcer) *PermissionService {
+	return &PermissionService{enforcer: e, events: newPolicyLog(), now: time.Now}
+}
 
-	w, err := rediswatcher.NewWatcher(cfg.Addr, rediswatcher.WatcherOptions{
-		SubClient: redisClient,
-		PubClient: redisClient,
-		Channel:   "/casbin",
-	})
-	if err != nil {
-		return nil, fmt.Errorf("error create csbin watcher: %w", err)
-	}
+// NewPermissionService sets w as the enforcer's watcher, so changes made here
+// reach the other replicas and theirs are applied here.
+func NewPermissionService(e *casbin.Enforcer, w persist.Watcher) (*PermissionService, error) {
+	ps := newPermissionService(e)
 	if err := e.SetWatcher(w); err != nil {
 		return nil, fmt.Errorf("error setting watcher for casbin enforcer: %w", err)
 	}
-	w.SetUpdateCallback(func(msg string) {
-		slog.Info("🔄 Casbin watcher signal received", "msg", msg)
-		ps.mu.Lock()
-		err := ps.enforcer.LoadPolicy()
-		ps.mu.Unlock()
-		if err != nil {
-			slog.Error("❌ Failed to reload Casbin policies", "error", err)
-		} else {
-			slog.Info("✅ Casbin policies reloaded successfully")
-		}
-	})
+	if err := w.SetUpdateCallback(ps.onWatcherMessage); err != nil {
+		return nil, fmt.Errorf("error setting casbin watcher callback: %w", err)
+	}
+	ps.watcher = w
 	return ps, nil
 }
 
-func (p *PermissionService) AddPolicyIfNotExists(sub, obj, act string) (bool, error) {
-	hasPolicy, err := p.enforcer.HasPolicy(sub, obj, act)
+func (p *PermissionService) AddPolicyIfNotExists(ctx context.Context, sub, dom, obj, act string) (bool, error) {
+	p.mu.RLock()
+	hasPolicy, err := p.enforcer.HasPolicy(sub, dom, obj, act)
+	p.mu.RUnlock()
 	if err != nil {
 		return false, err
 	}
 
 	if !hasPolicy {
-		success, err := p.AddPolicy(sub, obj, act)
+		success, err := p.AddPolicy(ctx, sub, dom, obj, act)
 		return success, err
 	}
 	return true, nil
 }
 
-func (p *PermissionService) CheckPermission(subj, obj, act string) (bool, error) {
+// CheckPermission allows the request when an RBAC policy matches or, failing
+// that, when an ABAC rule matches and its condition holds for attrs.
+func (p *PermissionService) CheckPermission(subj, dom, obj, act string, attrs Attributes) (bool, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	allowed, err := p.enforcer.Enforce(subj, dom, obj, act)
+	if err != nil || allowed {
+		return allowed, err
+	}
+	return p.checkConditions(subj, dom, obj, act, attrs), nil
+}
+
+// PermissionCheck is one entry of a batch check.
+type PermissionCheck struct {
+	Sub   string
+	Dom   string
+	Obj   string
+	Act   string
+	Attrs Attributes
+}
+
+// BatchCheckPermission evaluates all checks against a single policy snapshot
+// and returns the decisions in the same order, with the semantics of
+// CheckPermission for each entry.
+func (p *PermissionService) BatchCheckPermission(checks []PermissionCheck) ([]bool, error) {
+	requests := make([][]any, len(checks))
+	for i, c := range checks {
+		requests[i] = []any{c.Sub, c.Dom, c.Obj, c.Act}
+	}
+
 	p.mu.RLock()
 	defer p.mu.RUnlock()
-	return p.enforcer.Enforce(subj, obj, act)
+	results, err := p.enforcer.BatchEnforce(requests)
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range checks {
+		if !results[i] {
+			results[i] = p.checkConditions(c.Sub, c.Dom, c.Obj, c.Act, c.Attrs)
+		}
+	}
+	return results, nil
 }
 
-func (p *PermissionService) AddPolicy(sub, obj, act string) (bool, error) {
+// checkConditions evaluates the ABAC rules. The caller holds the read lock.
+func (p *PermissionService) checkConditions(subj, dom, obj, act string, attrs Attributes) bool {
+	if rules, ok := p.enforcer.GetModel()["p"]["p2"]; !ok || len(rules.Policy) == 0 {
+		return false
+	}
+	allowed, err := p.enforcer.Enforce(abacContext, subj, dom, obj, act, attrs.toRequest(subj, p.now()))
+	if err != nil {
+		// A condition referring to an attribute the caller didn't send can't
+		// hold, so it denies instead of failing the check.
+		slog.Warn("ABAC condition not evaluated", "sub", subj, "obj", obj, "act", act, "error", err)
+		return false
+	}
+	return allowed
+}
+
+func (p *PermissionService) AddPolicy(ctx context.Context, sub, dom, obj, act string) (bool, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	return p.enforcer.AddPolicy(sub, obj, act)
+	added, err := p.enforcer.AddPolicy(sub, dom, obj, act)
+	if err != nil || !added {
+		return added, err
+	}
+	if err := p.publish(ctx, PolicyEventAdd, "p", sub, dom, obj, act); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-func (p *PermissionService) RemovePolicy(sub, obj, act string) (bool, error) {
-	return p.enforcer.RemovePolicy(sub, obj, act)
+func (p *PermissionService) RemovePolicy(ctx context.Context, sub, dom, obj, act string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	removed, err := p.enforcer.RemovePolicy(sub, dom, obj, act)
+	if err != nil || !removed {
+		return removed, err
+	}
+	if err := p.publish(ctx, PolicyEventRemove, "p", sub, dom, obj, act); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// publish reports a single rule change that took effect. The caller holds
+// the write lock.
+func (p *PermissionService) publish(ctx context.Context, typ PolicyEventType, ptype string, values ...string) error {
+	return p.applied(ctx, typ, PolicyRule{PType: ptype, Values: values})
+}
+
+func (p *PermissionService) AddConditionalPolicy(ctx context.Context, sub, dom, obj, act, cond string) (bool, error) {
+	if err := validateCondition(cond); err != nil {
+		return false, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	added, err := p.enforcer.AddNamedPolicy("p2", sub, dom, obj, act, cond)
+	if err != nil || !added {
+		return added, err
+	}
+	if err := p.publish(ctx, PolicyEventAdd, "p2", sub, dom, obj, act, cond); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (p *PermissionService) RemoveConditionalPolicy(ctx context.Context, sub, dom, obj, act, cond string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	removed, err := p.enforcer.RemoveNamedPolicy("p2", sub, dom, obj, act, cond)
+	if err != nil || !removed {
+		return removed, err
+	}
+	if err := p.publish(ctx, PolicyEventRemove, "p2", sub, dom, obj, act, cond); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (p *PermissionService) AssignRole(ctx context.Context, user, role, dom string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	added, err := p.enforcer.AddRoleForUserInDomain(user, role, dom)
+	if err != nil || !added {
+		return added, err
+	}
+	if err := p.publish(ctx, PolicyEventAdd, "g", user, role, dom); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (p *PermissionService) UnassignRole(ctx context.Context, user, role, dom string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	removed, err := p.enforcer.DeleteRoleForUserInDomain(user, role, dom)
+	if err != nil || !removed {
+		return removed, err
+	}
+	if err := p.publish(ctx, PolicyEventRemove, "g", user, role, dom); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (p *PermissionService) GetRoleMembers(role, dom string) ([]string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.enforcer.GetUsersForRoleInDomain(role, dom), nil
+}
+
+// CheckEnforcer fails when the enforcer can't evaluate a request.
+func (p *PermissionService) CheckEnforcer(context.Context) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, err := p.enforcer.Enforce("health", auth.GlobalDomain, "health", "check")
+	return err
+}
+
+// CheckWatcher fails while the watcher can't reach the other replicas, for
+// watchers that can tell.
+func (p *PermissionService) CheckWatcher(context.Context) error {
+	if w, ok := p.watcher.(interface{ Healthy() error }); ok {
+		return w.Healthy()
+	}
+	return nil
 }
 
 func (p *PermissionService) Close() error {
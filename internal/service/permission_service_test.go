@@ -0,0 +1,361 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/mrhumster/web-server-gin/config"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const global = models.GlobalDomain
+
+func newTestPermissionService(t *testing.T) *PermissionService {
+	t.Helper()
+	e, err := casbin.NewEnforcer(filepath.Join(config.GetRootDir(), "config", "model.conf"))
+	require.NoError(t, err)
+	return newPermissionService(e)
+}
+
+// at is a clock stopped at hour today.
+func at(hour int) func() time.Time {
+	y, m, d := time.Now().Date()
+	return func() time.Time { return time.Date(y, m, d, hour, 0, 0, 0, time.Local) }
+}
+
+func TestPermissionService_RoleHierarchy(t *testing.T) {
+	ps := newTestPermissionService(t)
+
+	_, err := ps.AddPolicy(t.Context(), "member", global, "users", "read")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(t.Context(), "admin", global, "users/*", "delete")
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "admin", "member", global)
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "alice", "member", global)
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "bob", "admin", global)
+	require.NoError(t, err)
+
+	cases := []struct {
+		sub, obj, act string
+		allowed       bool
+	}{
+		{"alice", "users", "read", true},
+		{"alice", "users/123", "delete", false},
+		{"bob", "users", "read", true},
+		{"bob", "users/123", "delete", true},
+		{"carol", "users", "read", false},
+	}
+	for _, c := range cases {
+		allowed, err := ps.CheckPermission(c.sub, global, c.obj, c.act, Attributes{})
+		require.NoError(t, err)
+		assert.Equal(t, c.allowed, allowed, "%s %s %s", c.sub, c.obj, c.act)
+	}
+
+	members, err := ps.GetRoleMembers("member", global)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"admin", "alice"}, members)
+
+	_, err = ps.UnassignRole(t.Context(), "bob", "admin", global)
+	require.NoError(t, err)
+	allowed, err := ps.CheckPermission("bob", global, "users/123", "delete", Attributes{})
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestPermissionService_DomainIsolation(t *testing.T) {
+	ps := newTestPermissionService(t)
+
+	_, err := ps.AddPolicy(t.Context(), "member", global, "users", "read")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(t.Context(), "admin", global, "orgs/*", "write")
+	require.NoError(t, err)
+	for _, org := range []string{"org1", "org2"} {
+		_, err = ps.AddPolicy(t.Context(), models.OrgRoleAdmin, org, "orgs/"+org, "write")
+		require.NoError(t, err)
+		_, err = ps.AddPolicy(t.Context(), models.OrgRoleMember, org, "orgs/"+org, "read")
+		require.NoError(t, err)
+	}
+	_, err = ps.AssignRole(t.Context(), "alice", "member", global)
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "alice", models.OrgRoleAdmin, "org1")
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "bob", models.OrgRoleMember, "org1")
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "root", "admin", global)
+	require.NoError(t, err)
+
+	cases := []struct {
+		sub, dom, obj, act string
+		allowed            bool
+	}{
+		// platform roles apply inside every organization
+		{"alice", "org1", "users", "read", true},
+		{"alice", global, "users", "read", true},
+		// org roles apply only in their own domain
+		{"alice", "org1", "orgs/org1", "write", true},
+		{"alice", "org2", "orgs/org2", "write", false},
+		{"alice", "org1", "orgs/org2", "write", false},
+		{"alice", global, "orgs/org1", "write", false},
+		{"bob", "org1", "orgs/org1", "read", true},
+		{"bob", "org1", "orgs/org1", "write", false},
+		{"bob", "org1", "users", "read", false},
+		// platform admins manage every organization
+		{"root", "org2", "orgs/org2", "write", true},
+		{"root", global, "orgs/org1", "write", true},
+	}
+	for _, c := range cases {
+		allowed, err := ps.CheckPermission(c.sub, c.dom, c.obj, c.act, Attributes{})
+		require.NoError(t, err)
+		assert.Equal(t, c.allowed, allowed, "%s %s %s %s", c.sub, c.dom, c.obj, c.act)
+	}
+
+	members, err := ps.GetRoleMembers(models.OrgRoleAdmin, "org1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, members)
+	members, err = ps.GetRoleMembers(models.OrgRoleAdmin, "org2")
+	require.NoError(t, err)
+	assert.Empty(t, members)
+}
+
+func TestPermissionService_ABAC(t *testing.T) {
+	ps := newTestPermissionService(t)
+
+	_, err := ps.AssignRole(t.Context(), "agent", "support", global)
+	require.NoError(t, err)
+	_, err = ps.AddConditionalPolicy(t.Context(), "support", global, "users/*", "read",
+		"r2.attrs.subject.region == r2.attrs.resource.region && r2.attrs.env.hour >= 9 && r2.attrs.env.hour < 18")
+	require.NoError(t, err)
+	_, err = ps.AddConditionalPolicy(t.Context(), models.SelfSubject, global, "users/:id", "delete",
+		"r2.attrs.resource.verified == false")
+	require.NoError(t, err)
+
+	_, err = ps.AddConditionalPolicy(t.Context(), "support", global, "users/*", "write", "r2.attrs.subject.region ==")
+	assert.ErrorIs(t, err, ErrInvalidCondition)
+
+	eu := Attributes{Subject: map[string]string{"region": "eu"}, Resource: map[string]string{"region": "eu"}}
+	cases := []struct {
+		name          string
+		sub, obj, act string
+		attrs         Attributes
+		hour          int
+		allowed       bool
+	}{
+		{"same region in business hours", "agent", "users/42", "read", eu, 10, true},
+		{"other region", "agent", "users/42", "read", Attributes{
+			Subject: map[string]string{"region": "eu"}, Resource: map[string]string{"region": "us"},
+		}, 10, false},
+		{"outside business hours", "agent", "users/42", "read", eu, 22, false},
+		{"spoofed hour", "agent", "users/42", "read", Attributes{
+			Subject: eu.Subject, Resource: eu.Resource, Env: map[string]string{"hour": "10"},
+		}, 22, false},
+		{"missing attributes deny", "agent", "users/42", "read", Attributes{}, 10, false},
+		{"not a support agent", "mallory", "users/42", "read", eu, 10, false},
+		{"owner deletes unverified account", "u1", "users/u1", "delete", Attributes{
+			Resource: map[string]string{"verified": "false"},
+		}, 10, true},
+		{"owner can't delete verified account", "u1", "users/u1", "delete", Attributes{
+			Resource: map[string]string{"verified": "true"},
+		}, 10, false},
+		{"other users' accounts", "u2", "users/u1", "delete", Attributes{
+			Resource: map[string]string{"verified": "false"},
+		}, 10, false},
+	}
+	for _, c := range cases {
+		ps.now = at(c.hour)
+		allowed, err := ps.CheckPermission(c.sub, global, c.obj, c.act, c.attrs)
+		require.NoError(t, err, c.name)
+		assert.Equal(t, c.allowed, allowed, c.name)
+	}
+
+	_, err = ps.RemoveConditionalPolicy(t.Context(), models.SelfSubject, global, "users/:id", "delete",
+		"r2.attrs.resource.verified == false")
+	require.NoError(t, err)
+	allowed, err := ps.CheckPermission("u1", global, "users/u1", "delete", Attributes{
+		Resource: map[string]string{"verified": "false"},
+	})
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestPermissionService_BatchCheckPermission(t *testing.T) {
+	ps := newTestPermissionService(t)
+
+	_, err := ps.AddPolicy(t.Context(), models.SelfSubject, global, "users/:id", "read")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(t.Context(), "admin", global, "users/*", "read")
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "bob", "admin", global)
+	require.NoError(t, err)
+	_, err = ps.AddConditionalPolicy(t.Context(), "support", global, "users/*", "read", "r2.attrs.resource.region == \"eu\"")
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "agent", "support", global)
+	require.NoError(t, err)
+
+	checks := []PermissionCheck{
+		{Sub: "alice", Dom: global, Obj: "users/alice", Act: "read"},
+		{Sub: "alice", Dom: global, Obj: "users/bob", Act: "read"},
+		{Sub: "bob", Dom: global, Obj: "users/alice", Act: "read"},
+		{Sub: "bob", Dom: global, Obj: "users/alice", Act: "delete"},
+		{Sub: "agent", Dom: global, Obj: "users/alice", Act: "read", Attrs: Attributes{Resource: map[string]string{"region": "eu"}}},
+		{Sub: "agent", Dom: global, Obj: "users/bob", Act: "read", Attrs: Attributes{Resource: map[string]string{"region": "us"}}},
+	}
+	results, err := ps.BatchCheckPermission(checks)
+	require.NoError(t, err)
+	require.Len(t, results, len(checks))
+	for i, c := range checks {
+		single, err := ps.CheckPermission(c.Sub, c.Dom, c.Obj, c.Act, c.Attrs)
+		require.NoError(t, err)
+		assert.Equal(t, single, results[i], "%s %s %s", c.Sub, c.Obj, c.Act)
+	}
+	assert.Equal(t, []bool{true, false, true, false, true, false}, results)
+}
+
+func TestPermissionService_Introspection(t *testing.T) {
+	ps := newTestPermissionService(t)
+
+	_, err := ps.AddPolicy(t.Context(), models.SelfSubject, global, "users/:id", "write")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(t.Context(), "member", global, "users", "read")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(t.Context(), "admin", global, "users/*", "write")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(t.Context(), models.OrgRoleAdmin, "org1", "orgs/org1", "write")
+	require.NoError(t, err)
+	_, err = ps.AddConditionalPolicy(t.Context(), "member", global, "stream", "read", "r2.attrs.env.hour < 18")
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "admin", "member", global)
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "alice", "member", global)
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "alice", models.OrgRoleAdmin, "org1")
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "bob", "admin", global)
+	require.NoError(t, err)
+
+	roles, err := ps.GetImplicitRolesForUser("bob", global)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin", "member"}, roles)
+	roles, err = ps.GetImplicitRolesForUser("alice", "org1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"member", models.OrgRoleAdmin}, roles)
+
+	perms, err := ps.ListPermissionsForSubject("alice", "org1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []Permission{
+		{Subject: models.SelfSubject, Domain: global, Object: "users/alice", Action: "write"},
+		{Subject: "member", Domain: global, Object: "users", Action: "read"},
+		{Subject: models.OrgRoleAdmin, Domain: "org1", Object: "orgs/org1", Action: "write"},
+		{Subject: "member", Domain: global, Object: "stream", Action: "read", Condition: "r2.attrs.env.hour < 18"},
+	}, perms)
+	perms, err = ps.ListPermissionsForSubject("alice", "org2")
+	require.NoError(t, err)
+	assert.Len(t, perms, 3)
+
+	subjects, err := ps.ListSubjectsForResource("users/alice", "write", global)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin", "alice", "bob"}, subjects)
+	subjects, err = ps.ListSubjectsForResource("orgs/org1", "", "org1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", models.OrgRoleAdmin}, subjects)
+	subjects, err = ps.ListSubjectsForResource("orgs/org1", "", global)
+	require.NoError(t, err)
+	assert.Empty(t, subjects)
+}
+
+func TestPermissionService_ExplainPermission(t *testing.T) {
+	ps := newTestPermissionService(t)
+
+	_, err := ps.AddPolicy(t.Context(), "member", global, "users", "read")
+	require.NoError(t, err)
+	_, err = ps.AddConditionalPolicy(t.Context(), "member", global, "stream", "read", "r2.attrs.env.hour < 18")
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "alice", "member", global)
+	require.NoError(t, err)
+
+	ex, err := ps.ExplainPermission("alice", global, "users", "read", Attributes{})
+	require.NoError(t, err)
+	assert.True(t, ex.Allowed)
+	assert.Equal(t, ReasonPolicy, ex.Reason)
+	assert.Equal(t, []Permission{{Subject: "member", Domain: global, Object: "users", Action: "read"}}, ex.Matched)
+	assert.Equal(t, []string{"member"}, ex.Roles)
+
+	ps.now = at(10)
+	ex, err = ps.ExplainPermission("alice", global, "stream", "read", Attributes{})
+	require.NoError(t, err)
+	assert.True(t, ex.Allowed)
+	assert.Equal(t, ReasonConditionalPolicy, ex.Reason)
+	assert.Equal(t, []Permission{{Subject: "member", Domain: global, Object: "stream", Action: "read", Condition: "r2.attrs.env.hour < 18"}}, ex.Matched)
+
+	ex, err = ps.ExplainPermission("alice", global, "users", "write", Attributes{})
+	require.NoError(t, err)
+	assert.False(t, ex.Allowed)
+	assert.Equal(t, ReasonNoMatch, ex.Reason)
+	assert.Empty(t, ex.Matched)
+}
+
+func TestPermissionService_WatchPolicies(t *testing.T) {
+	ps := newTestPermissionService(t)
+
+	backlog, live, cancel := ps.WatchPolicies("", 0)
+	require.Len(t, backlog, 1)
+	assert.Equal(t, PolicyEventReload, backlog[0].Type, "a new watcher starts from a full load")
+	epoch := backlog[0].Epoch
+
+	_, err := ps.AddPolicy(t.Context(), "member", global, "users", "read")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(t.Context(), "member", global, "users", "read")
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "alice", "member", global)
+	require.NoError(t, err)
+	_, err = ps.RemovePolicy(t.Context(), "member", global, "users", "read")
+	require.NoError(t, err)
+
+	want := []PolicyEvent{
+		{Epoch: epoch, Revision: 1, Type: PolicyEventAdd, Rules: []PolicyRule{{PType: "p", Values: []string{"member", global, "users", "read"}}}},
+		{Epoch: epoch, Revision: 2, Type: PolicyEventAdd, Rules: []PolicyRule{{PType: "g", Values: []string{"alice", "member", global}}}},
+		{Epoch: epoch, Revision: 3, Type: PolicyEventRemove, Rules: []PolicyRule{{PType: "p", Values: []string{"member", global, "users", "read"}}}},
+	}
+	for _, ev := range want {
+		assert.Equal(t, ev, <-live, "duplicates are not published")
+	}
+	cancel()
+	_, open := <-live
+	assert.False(t, open)
+
+	backlog, _, cancel = ps.WatchPolicies(epoch, 1)
+	defer cancel()
+	assert.Equal(t, append(want[1:], PolicyEvent{Epoch: epoch, Revision: 3, Type: PolicyEventSync}), backlog)
+
+	backlog, _, cancel = ps.WatchPolicies("previous-run", 1)
+	defer cancel()
+	assert.Equal(t, []PolicyEvent{{Epoch: epoch, Revision: 3, Type: PolicyEventReload}}, backlog)
+}
+
+func TestPolicyLog_Overflow(t *testing.T) {
+	l := newPolicyLog()
+	_, live, cancel := l.subscribe(l.epoch, 0)
+	defer cancel()
+	for range policyLogSize + 1 {
+		l.publish(PolicyEventAdd)
+	}
+
+	n := 0
+	for range live {
+		n++
+	}
+	assert.Equal(t, subscriberBuffer, n, "a watcher that falls behind is disconnected")
+
+	backlog, _, cancel := l.subscribe(l.epoch, 0)
+	defer cancel()
+	assert.Equal(t, PolicyEventReload, backlog[0].Type, "events no longer kept can't be replayed")
+	backlog, _, cancel = l.subscribe(l.epoch, 1)
+	defer cancel()
+	assert.Len(t, backlog, policyLogSize+1)
+	assert.Equal(t, PolicyEventSync, backlog[policyLogSize].Type)
+}
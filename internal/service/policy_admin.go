@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+)
+
+var ErrPolicyOutsideFilter = errors.New("policy does not match the filter")
+
+// PolicyFilter selects stored policies by exact field values. Empty fields
+// match any value.
+type PolicyFilter struct {
+	Subject string
+	Domain  string
+	Object  string
+	Action  string
+}
+
+func (f PolicyFilter) matches(perm Permission) bool {
+	return (f.Subject == "" || f.Subject == perm.Subject) &&
+		(f.Domain == "" || f.Domain == perm.Domain) &&
+		(f.Object == "" || f.Object == perm.Object) &&
+		(f.Action == "" || f.Action == perm.Action)
+}
+
+// ptype is the policy type a permission is stored as.
+func (perm Permission) ptype() string {
+	if perm.Condition != "" {
+		return "p2"
+	}
+	return "p"
+}
+
+func (perm Permission) rule() []string {
+	rule := []string{perm.Subject, perm.Domain, perm.Object, perm.Action}
+	if perm.Condition != "" {
+		rule = append(rule, perm.Condition)
+	}
+	return rule
+}
+
+// ListPolicies returns the stored policies matching f, plain ones first.
+func (p *PermissionService) ListPolicies(f PolicyFilter) []Permission {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.listPolicies(f)
+}
+
+func (p *PermissionService) listPolicies(f PolicyFilter) []Permission {
+	var result []Permission
+	for _, ptype := range []string{"p", "p2"} {
+		rules, ok := p.enforcer.GetModel()["p"][ptype]
+		if !ok {
+			continue
+		}
+		for _, rule := range rules.Policy {
+			if len(rule) < 4 {
+				continue
+			}
+			perm := Permission{Subject: rule[0], Domain: rule[1], Object: rule[2], Action: rule[3]}
+			if ptype == "p2" && len(rule) > 4 {
+				perm.Condition = rule[4]
+			}
+			if f.matches(perm) {
+				result = append(result, perm)
+			}
+		}
+	}
+	return result
+}
+
+// ReplacePolicies makes policies the only stored policies matching f: the
+// missing ones are added and the others matching f removed, under one lock and
+// as a whole or not at all, so checks see either the old or the new set. Every
+// policy has to match f.
+func (p *PermissionService) ReplacePolicies(ctx context.Context, f PolicyFilter, policies []Permission) (added, removed int, err error) {
+	want := make(map[string][][]string)
+	for i, perm := range policies {
+		if perm.Domain == "" {
+			perm.Domain = models.GlobalDomain
+		}
+		if !f.matches(perm) {
+			return 0, 0, fmt.Errorf("%w: policies[%d]", ErrPolicyOutsideFilter, i)
+		}
+		if perm.Condition != "" {
+			if err := validateCondition(perm.Condition); err != nil {
+				return 0, 0, err
+			}
+		}
+		want[perm.ptype()] = append(want[perm.ptype()], perm.rule())
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	have := make(map[string][][]string)
+	for _, perm := range p.listPolicies(f) {
+		have[perm.ptype()] = append(have[perm.ptype()], perm.rule())
+	}
+	var steps []policyStep
+	for _, ptype := range []string{"p", "p2"} {
+		if stale := missingRules(have[ptype], want[ptype]); len(stale) > 0 {
+			steps = append(steps, policyStep{PolicyEventRemove, ptype, stale})
+			removed += len(stale)
+		}
+		if fresh := missingRules(want[ptype], have[ptype]); len(fresh) > 0 {
+			steps = append(steps, policyStep{PolicyEventAdd, ptype, fresh})
+			added += len(fresh)
+		}
+	}
+	if err := p.applySteps(ctx, steps); err != nil {
+		return 0, 0, err
+	}
+	return added, removed, nil
+}
+
+// missingRules returns the rules of a that are not in b, without duplicates.
+func missingRules(a, b [][]string) [][]string {
+	seen := make(map[string]struct{}, len(b))
+	for _, rule := range b {
+		seen[strings.Join(rule, "\x00")] = struct{}{}
+	}
+	var missing [][]string
+	for _, rule := range a {
+		key := strings.Join(rule, "\x00")
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			missing = append(missing, rule)
+		}
+	}
+	return missing
+}
+
+func policyRules(ptype string, rules [][]string) []PolicyRule {
+	out := make([]PolicyRule, len(rules))
+	for i, rule := range rules {
+		out[i] = PolicyRule{PType: ptype, Values: rule}
+	}
+	return out
+}
+
+// ListRoles returns the sorted roles granted in dom, or in any domain when
+// dom is empty. A role is anything users are assigned to.
+func (p *PermissionService) ListRoles(dom string) []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var roles []string
+	for _, rule := range p.enforcer.GetModel()["g"]["g"].Policy {
+		if len(rule) < 3 || (dom != "" && rule[2] != dom) {
+			continue
+		}
+		roles = append(roles, rule[1])
+	}
+	slices.Sort(roles)
+	return slices.Compact(roles)
+}
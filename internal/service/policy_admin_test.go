@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionService_ReplacePolicies(t *testing.T) {
+	ps := newTestPermissionService(t)
+	_, err := ps.AddPolicy(t.Context(), "member", global, "users", "read")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(t.Context(), "member", global, "stream", "read")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(t.Context(), "admin", global, "users/*", "write")
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "alice", "member", global)
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "bob", "admin", "org1")
+	require.NoError(t, err)
+
+	member := PolicyFilter{Subject: "member"}
+	added, removed, err := ps.ReplacePolicies(t.Context(), member, []Permission{
+		{Subject: "member", Object: "users", Action: "read"},
+		{Subject: "member", Domain: global, Object: "stream", Action: "write"},
+		{Subject: "member", Domain: global, Object: "stream", Action: "read", Condition: "r2.attrs.env.hour < 18"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, []Permission{
+		{Subject: "member", Domain: global, Object: "users", Action: "read"},
+		{Subject: "member", Domain: global, Object: "stream", Action: "write"},
+		{Subject: "member", Domain: global, Object: "stream", Action: "read", Condition: "r2.attrs.env.hour < 18"},
+	}, ps.ListPolicies(member))
+	assert.Len(t, ps.ListPolicies(PolicyFilter{Subject: "admin"}), 1, "policies outside the filter are kept")
+
+	_, _, err = ps.ReplacePolicies(t.Context(), member, []Permission{{Subject: "admin", Domain: global, Object: "users", Action: "read"}})
+	assert.ErrorIs(t, err, ErrPolicyOutsideFilter)
+	_, _, err = ps.ReplacePolicies(t.Context(), member, []Permission{{Subject: "member", Domain: global, Object: "users", Action: "read", Condition: "r2.attrs.("}})
+	assert.ErrorIs(t, err, ErrInvalidCondition)
+	assert.Len(t, ps.ListPolicies(member), 3, "a rejected replace changes nothing")
+
+	assert.Equal(t, []string{"admin", "member"}, ps.ListRoles(""))
+	assert.Equal(t, []string{"admin"}, ps.ListRoles("org1"))
+}
+
+func TestPermissionService_ReplacePolicies_PartlyApplied(t *testing.T) {
+	ps := newTestPermissionService(t)
+	history := &memoryHistory{}
+	ps.SetHistory(history)
+	_, err := ps.AddPolicy(t.Context(), "member", global, "users", "read")
+	require.NoError(t, err)
+	member := PolicyFilter{Subject: "member"}
+	before := ps.ListPolicies(member)
+
+	// The removal of users read is recorded, the add of stream read is not.
+	history.err, history.errAfter = errors.New("connection refused"), 1
+	added, removed, err := ps.ReplacePolicies(t.Context(), member, []Permission{
+		{Subject: "member", Domain: global, Object: "stream", Action: "read"},
+	})
+	assert.ErrorIs(t, err, ErrHistoryNotRecorded)
+	assert.Zero(t, added)
+	assert.Zero(t, removed)
+	assert.Equal(t, before, ps.ListPolicies(member), "a failed replace leaves the old set")
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type PolicyEventType string
+
+const (
+	PolicyEventAdd    PolicyEventType = "add"
+	PolicyEventRemove PolicyEventType = "remove"
+	// PolicyEventReload means the policies were replaced as a whole; watchers
+	// have to refetch them.
+	PolicyEventReload PolicyEventType = "reload"
+	// PolicyEventSync marks the end of a replay: the watcher is up to date
+	// and further events are live.
+	PolicyEventSync PolicyEventType = "sync"
+)
+
+// PolicyEvent is one change of the policies held by this server. Revisions
+// grow by one per change and are only comparable within one epoch, which
+// changes with every server start.
+type PolicyEvent struct {
+	Epoch    string
+	Revision uint64
+	Type     PolicyEventType
+	Rules    []PolicyRule
+}
+
+const (
+	// policyLogSize is how many past events a watcher can resume across.
+	policyLogSize = 1024
+	// subscriberBuffer is how far a watcher may fall behind before it is
+	// dropped and has to resume.
+	subscriberBuffer = 256
+)
+
+// policyLog numbers policy changes, keeps the recent ones for resuming
+// watchers and fans them out to subscribers.
+type policyLog struct {
+	mu       sync.Mutex
+	epoch    string
+	revision uint64
+	events   []PolicyEvent
+	subs     map[chan PolicyEvent]struct{}
+}
+
+func newPolicyLog() *policyLog {
+	return &policyLog{
+		epoch: uuid.NewString(),
+		subs:  make(map[chan PolicyEvent]struct{}),
+	}
+}
+
+func (l *policyLog) publish(typ PolicyEventType, rules ...PolicyRule) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.revision++
+	ev := PolicyEvent{Epoch: l.epoch, Revision: l.revision, Type: typ, Rules: rules}
+	if len(l.events) == policyLogSize {
+		copy(l.events, l.events[1:])
+		l.events = l.events[:policyLogSize-1]
+	}
+	l.events = append(l.events, ev)
+	for ch := range l.subs {
+		select {
+		case ch <- ev:
+		default:
+			// Too slow: end its stream so it resumes from its last revision.
+			delete(l.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// subscribe returns the events a watcher that has seen revision of epoch
+// missed, followed by a sync event, and a channel of the live events. When
+// the missed events are no longer kept, or the epoch differs, it gets a
+// single reload event instead.
+func (l *policyLog) subscribe(epoch string, revision uint64) ([]PolicyEvent, <-chan PolicyEvent, func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var backlog []PolicyEvent
+	if l.canReplay(epoch, revision) {
+		for _, ev := range l.events {
+			if ev.Revision > revision {
+				backlog = append(backlog, ev)
+			}
+		}
+		backlog = append(backlog, PolicyEvent{Epoch: l.epoch, Revision: l.revision, Type: PolicyEventSync})
+	} else {
+		backlog = []PolicyEvent{{Epoch: l.epoch, Revision: l.revision, Type: PolicyEventReload}}
+	}
+
+	ch := make(chan PolicyEvent, subscriberBuffer)
+	l.subs[ch] = struct{}{}
+	cancel := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if _, ok := l.subs[ch]; ok {
+			delete(l.subs, ch)
+			close(ch)
+		}
+	}
+	return backlog, ch, cancel
+}
+
+func (l *policyLog) canReplay(epoch string, revision uint64) bool {
+	if epoch != l.epoch || revision > l.revision {
+		return false
+	}
+	if revision == l.revision {
+		return true
+	}
+	return len(l.events) > 0 && l.events[0].Revision <= revision+1
+}
+
+// WatchPolicies subscribes to policy changes after revision of epoch, see
+// policyLog.subscribe. Call cancel when done.
+func (p *PermissionService) WatchPolicies(epoch string, revision uint64) (backlog []PolicyEvent, live <-chan PolicyEvent, cancel func()) {
+	return p.events.subscribe(epoch, revision)
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"github.com/mrhumster/web-server-gin/internal/repository"
+)
+
+var (
+	ErrHistoryDisabled = errors.New("policy history is not recorded")
+	ErrUnknownRevision = errors.New("unknown policy revision")
+	// ErrHistoryNotRecorded fails a policy change whose history could not be
+	// written; the change itself is undone.
+	ErrHistoryNotRecorded = errors.New("policy change not recorded")
+)
+
+// Actor is who a policy change is made by: the authenticated service calling
+// the permission service and the user it acts for, if any.
+type Actor struct {
+	Service string
+	User    string
+}
+
+type actorKey struct{}
+
+// WithActor attributes the policy changes made with ctx to a.
+func WithActor(ctx context.Context, a Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, a)
+}
+
+func actorFrom(ctx context.Context) Actor {
+	a, _ := ctx.Value(actorKey{}).(Actor)
+	return a
+}
+
+// SetHistory makes the service record every change it applies in h. Changes
+// applied from other replicas' watcher messages are recorded by the replica
+// that made them.
+func (p *PermissionService) SetHistory(h repository.PolicyChangeRepository) {
+	p.history = h
+}
+
+// applied records and publishes changes that took effect. A change that
+// can't be recorded is undone and fails, so the history never misses one. The
+// caller holds the write lock, so revisions follow the order the changes were
+// applied.
+func (p *PermissionService) applied(ctx context.Context, typ PolicyEventType, rules ...PolicyRule) error {
+	if err := p.record(ctx, typ, rules); err != nil {
+		if undoErr := p.undo(typ, rules); undoErr != nil {
+			slog.Error("Unrecorded policy change not undone", "changes", len(rules), "error", undoErr)
+		}
+		return fmt.Errorf("%w: %v", ErrHistoryNotRecorded, err)
+	}
+	p.events.publish(typ, rules...)
+	return nil
+}
+
+func (p *PermissionService) record(ctx context.Context, typ PolicyEventType, rules []PolicyRule) error {
+	if p.history == nil {
+		return nil
+	}
+	actor := actorFrom(ctx)
+	now := time.Now()
+	changes := make([]models.PolicyChange, len(rules))
+	for i, rule := range rules {
+		c := models.PolicyChange{
+			CreatedAt: now,
+			Service:   actor.Service,
+			Actor:     actor.User,
+			PType:     rule.PType,
+			Subject:   rule.Values[0],
+			Domain:    ruleDomain(rule),
+		}
+		if typ == PolicyEventRemove {
+			c.Op, c.Before = models.PolicyChangeRemove, rule.Values
+		} else {
+			c.Op, c.After = models.PolicyChangeAdd, rule.Values
+		}
+		changes[i] = c
+	}
+	// The change is in effect already; it is recorded or undone even if the
+	// caller has gone away.
+	return p.history.CreatePolicyChanges(context.WithoutCancel(ctx), changes)
+}
+
+// undo reverts changes that took effect, through the enforcer so the watcher
+// tells the other replicas. The caller holds the write lock.
+func (p *PermissionService) undo(typ PolicyEventType, rules []PolicyRule) error {
+	byType := make(map[string][][]string)
+	for _, rule := range rules {
+		byType[rule.PType] = append(byType[rule.PType], rule.Values)
+	}
+	for ptype, values := range byType {
+		var err error
+		switch {
+		case typ == PolicyEventRemove && ptype == "g":
+			_, err = p.enforcer.AddNamedGroupingPolicies(ptype, values)
+		case typ == PolicyEventRemove:
+			_, err = p.enforcer.AddNamedPolicies(ptype, values)
+		case ptype == "g":
+			_, err = p.enforcer.RemoveNamedGroupingPolicies(ptype, values)
+		default:
+			_, err = p.enforcer.RemoveNamedPolicies(ptype, values)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ruleDomain(rule PolicyRule) string {
+	if rule.PType == "g" {
+		return rule.Values[2]
+	}
+	return rule.Values[1]
+}
+
+// PolicyHistory returns up to limit recorded changes matching f, newest
+// first.
+func (p *PermissionService) PolicyHistory(ctx context.Context, f models.PolicyChangeFilter, limit int) ([]models.PolicyChange, error) {
+	if p.history == nil {
+		return nil, ErrHistoryDisabled
+	}
+	return p.history.ReadPolicyChanges(ctx, f, limit)
+}
+
+// RevertPolicies puts the policy set back the way it was at revision, by
+// undoing every recorded change made since. The undo is applied under one
+// lock, as a whole or not at all, and recorded like any other change, so it
+// can be reverted too. With dryRun the plan is only computed.
+func (p *PermissionService) RevertPolicies(ctx context.Context, revision uint64, dryRun bool) (PolicyPlan, error) {
+	if p.history == nil {
+		return PolicyPlan{}, ErrHistoryDisabled
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	latest, err := p.history.LatestPolicyRevision(ctx)
+	if err != nil {
+		return PolicyPlan{}, err
+	}
+	if revision > latest {
+		return PolicyPlan{}, fmt.Errorf("%w: %d, the latest is %d", ErrUnknownRevision, revision, latest)
+	}
+	changes, err := p.history.ReadPolicyChangesAfter(ctx, revision)
+	if err != nil {
+		return PolicyPlan{}, err
+	}
+
+	// The first change made to a rule after revision tells whether the rule
+	// was there at revision: it was unless that change added it.
+	existed := make(map[string]bool)
+	rules := make(map[string]PolicyRule)
+	var keys []string
+	for _, c := range changes {
+		key := c.PType + "\x00" + strings.Join(c.Rule(), "\x00")
+		if _, seen := existed[key]; seen {
+			continue
+		}
+		existed[key] = c.Op == models.PolicyChangeRemove
+		rules[key] = PolicyRule{PType: c.PType, Values: c.Rule()}
+		keys = append(keys, key)
+	}
+
+	var plan PolicyPlan
+	add := make(map[string][][]string)
+	remove := make(map[string][][]string)
+	for _, key := range keys {
+		rule := rules[key]
+		has, err := p.hasRule(rule)
+		if err != nil {
+			return PolicyPlan{}, err
+		}
+		switch {
+		case existed[key] && !has:
+			add[rule.PType] = append(add[rule.PType], rule.Values)
+			plan.Added = append(plan.Added, rule)
+		case !existed[key] && has:
+			remove[rule.PType] = append(remove[rule.PType], rule.Values)
+			plan.Removed = append(plan.Removed, rule)
+		}
+	}
+	if dryRun {
+		return plan, nil
+	}
+	var steps []policyStep
+	for _, ptype := range []string{"p", "p2", "g"} {
+		if len(remove[ptype]) > 0 {
+			steps = append(steps, policyStep{PolicyEventRemove, ptype, remove[ptype]})
+		}
+		if len(add[ptype]) > 0 {
+			steps = append(steps, policyStep{PolicyEventAdd, ptype, add[ptype]})
+		}
+	}
+	if err := p.applySteps(ctx, steps); err != nil {
+		return PolicyPlan{}, err
+	}
+	return plan, nil
+}
+
+// hasRule reports whether the model holds rule. The caller holds the lock.
+func (p *PermissionService) hasRule(rule PolicyRule) (bool, error) {
+	if rule.PType == "g" {
+		return p.enforcer.HasNamedGroupingPolicy(rule.PType, rule.Values)
+	}
+	return p.enforcer.HasNamedPolicy(rule.PType, rule.Values)
+}
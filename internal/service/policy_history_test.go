@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryHistory stands in for the policy_changes table.
+type memoryHistory struct {
+	mu      sync.Mutex
+	changes []models.PolicyChange
+	// err fails the writes while set, after the first errAfter of them.
+	err      error
+	errAfter int
+}
+
+func (h *memoryHistory) CreatePolicyChanges(_ context.Context, changes []models.PolicyChange) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.err != nil {
+		if h.errAfter == 0 {
+			return h.err
+		}
+		h.errAfter--
+	}
+	for _, c := range changes {
+		c.ID = uint64(len(h.changes) + 1)
+		h.changes = append(h.changes, c)
+	}
+	return nil
+}
+
+func (h *memoryHistory) ReadPolicyChanges(_ context.Context, f models.PolicyChangeFilter, limit int) ([]models.PolicyChange, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var out []models.PolicyChange
+	for _, c := range slices.Backward(h.changes) {
+		if (f.Actor == "" || c.Actor == f.Actor) && (f.Subject == "" || c.Subject == f.Subject) &&
+			(f.BeforeRevision == 0 || c.ID < f.BeforeRevision) && len(out) < limit {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (h *memoryHistory) ReadPolicyChangesAfter(_ context.Context, revision uint64) ([]models.PolicyChange, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.changes[revision:]), nil
+}
+
+func (h *memoryHistory) LatestPolicyRevision(context.Context) (uint64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return uint64(len(h.changes)), nil
+}
+
+func TestPermissionService_PolicyHistory(t *testing.T) {
+	ps := newTestPermissionService(t)
+	history := &memoryHistory{}
+	ps.SetHistory(history)
+	ctx := WithActor(t.Context(), Actor{Service: "web-server-gin", User: "admin-1"})
+
+	_, err := ps.AddPolicy(ctx, "member", global, "users", "read")
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "alice", "member", global)
+	require.NoError(t, err)
+	_, err = ps.RemovePolicy(ctx, "member", global, "users", "read")
+	require.NoError(t, err)
+	_, err = ps.RemovePolicy(ctx, "member", global, "users", "read")
+	require.NoError(t, err)
+
+	changes, err := ps.PolicyHistory(t.Context(), models.PolicyChangeFilter{}, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 3, "a change that changes nothing isn't recorded")
+	assert.Equal(t, models.PolicyChange{
+		ID: 3, CreatedAt: changes[0].CreatedAt, Service: "web-server-gin", Actor: "admin-1",
+		Op: models.PolicyChangeRemove, PType: "p", Subject: "member", Domain: global,
+		Before: []string{"member", global, "users", "read"},
+	}, changes[0])
+	assert.Equal(t, models.PolicyChangeAdd, changes[1].Op)
+	assert.Equal(t, []string{"alice", "member", global}, changes[1].After)
+	assert.Empty(t, changes[1].Actor)
+
+	mine, err := ps.PolicyHistory(t.Context(), models.PolicyChangeFilter{Actor: "admin-1"}, 10)
+	require.NoError(t, err)
+	assert.Len(t, mine, 2)
+}
+
+func TestPermissionService_PolicyHistory_NotRecorded(t *testing.T) {
+	ps := newTestPermissionService(t)
+	history := &memoryHistory{}
+	ps.SetHistory(history)
+	ctx := t.Context()
+	_, err := ps.AddPolicy(ctx, "member", global, "users", "read")
+	require.NoError(t, err)
+
+	_, live, cancel := ps.events.subscribe(ps.events.epoch, ps.events.revision)
+	defer cancel()
+	history.err = errors.New("connection refused")
+
+	added, err := ps.AddPolicy(ctx, "member", global, "orgs", "create")
+	assert.ErrorIs(t, err, ErrHistoryNotRecorded)
+	assert.False(t, added)
+	removed, err := ps.RemovePolicy(ctx, "member", global, "users", "read")
+	assert.ErrorIs(t, err, ErrHistoryNotRecorded)
+	assert.False(t, removed)
+	_, err = ps.AssignRole(ctx, "alice", "member", global)
+	assert.ErrorIs(t, err, ErrHistoryNotRecorded)
+
+	has := func(rule ...string) bool {
+		ok, err := ps.enforcer.HasPolicy(rule)
+		require.NoError(t, err)
+		return ok
+	}
+	assert.False(t, has("member", global, "orgs", "create"), "an unrecorded add is undone")
+	assert.True(t, has("member", global, "users", "read"), "an unrecorded remove is undone")
+	members, err := ps.GetRoleMembers("member", global)
+	require.NoError(t, err)
+	assert.NotContains(t, members, "alice")
+	select {
+	case ev := <-live:
+		t.Fatalf("unrecorded change published: %+v", ev)
+	default:
+	}
+}
+
+func TestPermissionService_RevertPolicies(t *testing.T) {
+	ps := newTestPermissionService(t)
+	ps.SetHistory(&memoryHistory{})
+	ctx := t.Context()
+	has := func(rule ...string) bool {
+		ok, err := ps.enforcer.HasPolicy(rule)
+		require.NoError(t, err)
+		return ok
+	}
+
+	_, err := ps.AddPolicy(ctx, "member", global, "users", "read")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(ctx, "member", global, "stream", "read")
+	require.NoError(t, err)
+	_, err = ps.AssignRole(ctx, "alice", "member", global)
+	require.NoError(t, err)
+	// Revision 3 is the state to go back to.
+	_, err = ps.RemovePolicy(ctx, "member", global, "users", "read")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(ctx, "member", global, "orgs", "create")
+	require.NoError(t, err)
+	_, err = ps.RemovePolicy(ctx, "member", global, "stream", "read")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(ctx, "member", global, "stream", "read")
+	require.NoError(t, err)
+
+	plan, err := ps.RevertPolicies(ctx, 3, true)
+	require.NoError(t, err)
+	assert.Equal(t, []PolicyRule{{PType: "p", Values: []string{"member", global, "users", "read"}}}, plan.Added)
+	assert.Equal(t, []PolicyRule{{PType: "p", Values: []string{"member", global, "orgs", "create"}}}, plan.Removed,
+		"stream read was removed and added back, so it stays")
+	assert.False(t, has("member", global, "users", "read"), "a dry run changes nothing")
+
+	_, live, cancel := ps.events.subscribe(ps.events.epoch, 0)
+	defer cancel()
+	applied, err := ps.RevertPolicies(ctx, 3, false)
+	require.NoError(t, err)
+	assert.Equal(t, plan, applied)
+	assert.True(t, has("member", global, "users", "read"))
+	assert.False(t, has("member", global, "orgs", "create"))
+	assert.True(t, has("member", global, "stream", "read"))
+	assert.Equal(t, PolicyEventRemove, (<-live).Type)
+	assert.Equal(t, PolicyEventAdd, (<-live).Type)
+
+	// The revert is recorded as revisions 8 and 9, so it can be undone too.
+	undo, err := ps.RevertPolicies(ctx, 7, false)
+	require.NoError(t, err)
+	assert.Equal(t, plan.Added, undo.Removed)
+	assert.Equal(t, plan.Removed, undo.Added)
+
+	_, err = ps.RevertPolicies(ctx, 100, false)
+	assert.ErrorIs(t, err, ErrUnknownRevision)
+}
+
+func TestPermissionService_RevertPolicies_PartlyApplied(t *testing.T) {
+	ps := newTestPermissionService(t)
+	history := &memoryHistory{}
+	ps.SetHistory(history)
+	ctx := t.Context()
+	has := func(rule ...string) bool {
+		ok, err := ps.enforcer.HasPolicy(rule)
+		require.NoError(t, err)
+		return ok
+	}
+
+	_, err := ps.AddPolicy(ctx, "member", global, "users", "read")
+	require.NoError(t, err)
+	_, err = ps.RemovePolicy(ctx, "member", global, "users", "read")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(ctx, "member", global, "orgs", "create")
+	require.NoError(t, err)
+
+	// Going back to revision 1 removes orgs create, then adds users read back;
+	// recording the add fails.
+	_, live, cancel := ps.events.subscribe(ps.events.epoch, ps.events.revision)
+	defer cancel()
+	history.err, history.errAfter = errors.New("connection refused"), 1
+	_, err = ps.RevertPolicies(ctx, 1, false)
+	assert.ErrorIs(t, err, ErrHistoryNotRecorded)
+
+	assert.True(t, has("member", global, "orgs", "create"), "the applied removal is undone")
+	assert.False(t, has("member", global, "users", "read"))
+	assert.Equal(t, PolicyEventRemove, (<-live).Type)
+	assert.Equal(t, PolicyEventAdd, (<-live).Type, "the undo is published")
+	select {
+	case ev := <-live:
+		t.Fatalf("unexpected event: %+v", ev)
+	default:
+	}
+}
+
+func TestPermissionService_RevertPolicies_NoHistory(t *testing.T) {
+	ps := newTestPermissionService(t)
+	_, err := ps.RevertPolicies(t.Context(), 1, false)
+	assert.ErrorIs(t, err, ErrHistoryDisabled)
+}
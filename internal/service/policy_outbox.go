@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"github.com/mrhumster/web-server-gin/internal/repository"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+)
+
+const (
+	outboxBatch    = 100
+	outboxInterval = time.Second
+	// outboxLease is how long a claimed operation is hidden from other
+	// replicas; one that crashed mid-batch is retried after it.
+	outboxLease      = 30 * time.Second
+	outboxMinBackoff = time.Second
+	outboxMaxBackoff = 5 * time.Minute
+)
+
+// PolicyOutbox applies the policy operations stored in the outbox, in order
+// per subject, retrying failures with backoff until they succeed. Operations
+// with a malformed rule can never succeed and are marked failed instead, so
+// the later operations of their subject go ahead.
+// Every operation is idempotent, so applying one twice is harmless.
+type PolicyOutbox struct {
+	repo             repository.PolicyOutboxRepository
+	permissionClient PermissionClient
+	notify           chan struct{}
+}
+
+func NewPolicyOutbox(repo repository.PolicyOutboxRepository, perm PermissionClient) *PolicyOutbox {
+	return &PolicyOutbox{repo: repo, permissionClient: perm, notify: make(chan struct{}, 1)}
+}
+
+// Notify wakes the outbox after operations were stored, so they are applied
+// right away instead of on the next poll.
+func (o *PolicyOutbox) Notify() {
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run applies operations until ctx is done.
+func (o *PolicyOutbox) Run(ctx context.Context) {
+	ticker := time.NewTicker(outboxInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := o.Process(ctx)
+			if err != nil {
+				slog.Error("Policy outbox not processed", "error", err)
+			}
+			if err != nil || n < outboxBatch {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-o.notify:
+		}
+	}
+}
+
+// Process applies one batch of due operations and returns how many it took.
+func (o *PolicyOutbox) Process(ctx context.Context) (int, error) {
+	ops, err := o.repo.ClaimPolicyOperations(ctx, outboxBatch, outboxLease)
+	if err != nil {
+		return 0, err
+	}
+	for _, op := range ops {
+		if err := o.apply(ctx, op); permanent(err) {
+			slog.Error("Policy operation can't be applied, giving up",
+				"id", op.ID, "op", op.Op, "rule", op.Rule, "error", err)
+			if err := o.repo.FailPolicyOperation(ctx, op.ID, err.Error()); err != nil {
+				return len(ops), err
+			}
+			continue
+		} else if err != nil {
+			next := time.Now().Add(outboxBackoff(op.Attempts + 1))
+			slog.Warn("Policy operation failed, will retry",
+				"id", op.ID, "op", op.Op, "rule", op.Rule, "attempts", op.Attempts+1, "next", next, "error", err)
+			if err := o.repo.RetryPolicyOperation(ctx, op.ID, next, err.Error()); err != nil {
+				return len(ops), err
+			}
+			continue
+		}
+		if err := o.repo.DeletePolicyOperation(ctx, op.ID); err != nil {
+			return len(ops), err
+		}
+	}
+	return len(ops), nil
+}
+
+// Stats reports the operations still waiting.
+func (o *PolicyOutbox) Stats(ctx context.Context) (models.PolicyOutboxStats, error) {
+	return o.repo.PolicyOutboxStats(ctx)
+}
+
+func (o *PolicyOutbox) apply(ctx context.Context, op models.PolicyOperation) error {
+	if op.Actor != "" {
+		ctx = auth.WithActor(ctx, op.Actor)
+	}
+	if op.Op == models.PolicyOperationRevokeRoles {
+		return o.revokeRoles(ctx, op)
+	}
+	if err := validatePolicyRule(PolicyRule{PType: op.PType, Values: op.Rule}); err != nil {
+		return err
+	}
+	rule := append([]string{op.PType}, op.Rule...)
+	var err error
+	if op.Op == models.PolicyChangeAdd {
+		err = auth.GrantRule(ctx, o.permissionClient, rule)
+	} else {
+		err = auth.RevokeRule(ctx, o.permissionClient, rule)
+	}
+	if err != nil {
+		return fmt.Errorf("%s %s %v: %w", op.Op, op.PType, op.Rule, err)
+	}
+	return nil
+}
+
+// revokeRoles unassigns every role the subject holds in the domain. The
+// implicit roles include inherited ones, whose unassigning changes nothing.
+func (o *PolicyOutbox) revokeRoles(ctx context.Context, op models.PolicyOperation) error {
+	if len(op.Rule) != 2 || op.Rule[0] == "" || op.Rule[1] == "" {
+		return fmt.Errorf("%w: %s wants a subject and a domain, got %v", ErrInvalidPolicyRule, op.Op, op.Rule)
+	}
+	sub, dom := op.Rule[0], op.Rule[1]
+	roles, err := o.permissionClient.GetImplicitRolesForUser(ctx, sub, dom)
+	if err != nil {
+		return fmt.Errorf("%s %s in %s: %w", op.Op, sub, dom, err)
+	}
+	for _, role := range roles {
+		if _, err := o.permissionClient.UnassignRoleInDomain(ctx, sub, role, dom); err != nil {
+			return fmt.Errorf("%s %s in %s: %w", op.Op, sub, dom, err)
+		}
+	}
+	return nil
+}
+
+// permanent reports whether err means the operation can never be applied:
+// its rule is malformed, locally or by the permission service.
+func permanent(err error) bool {
+	return errors.Is(err, ErrInvalidPolicyRule) || errors.Is(err, ErrInvalidCondition) || errors.Is(err, auth.ErrInvalidRequest)
+}
+
+// outboxBackoff doubles the delay with every attempt, up to a ceiling.
+func outboxBackoff(attempt int) time.Duration {
+	d := outboxMinBackoff
+	for i := 1; i < attempt && d < outboxMaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, outboxMaxBackoff)
+}
+
+// policyOperations builds the outbox operations adding or removing rules in
+// the PolicySnapshot layout.
+func policyOperations(op string, rules [][]string, actor string) []models.PolicyOperation {
+	ops := make([]models.PolicyOperation, len(rules))
+	for i, rule := range rules {
+		ops[i] = models.PolicyOperation{Op: op, PType: rule[0], Rule: rule[1:], Subject: rule[1], Actor: actor}
+	}
+	return ops
+}
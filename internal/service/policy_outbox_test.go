@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	repomock "github.com/mrhumster/web-server-gin/internal/repository/mock"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+	authmock "github.com/mrhumster/web-server-gin/pkg/auth/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+)
+
+func TestPolicyOutbox_Process(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomock.NewMockPolicyOutboxRepository(ctrl)
+	client := authmock.NewMockPermissionClient(ctrl)
+	outbox := NewPolicyOutbox(repo, client)
+
+	ops := []models.PolicyOperation{
+		{ID: 1, Op: models.PolicyChangeAdd, PType: "g", Rule: []string{"u1", "member", "*"}, Actor: "u1"},
+		{ID: 2, Op: models.PolicyChangeRemove, PType: "g", Rule: []string{"u2", "admin", "*"}},
+		{ID: 3, Op: models.PolicyChangeAdd, PType: "p", Rule: []string{"u3", "*", "streams/1", "read"}, Attempts: 2},
+		{ID: 4, Op: models.PolicyChangeRemove, PType: "p2", Rule: []string{"u4", "*", "streams", "read", "r.obj.Owner == r.sub"}},
+	}
+	repo.EXPECT().ClaimPolicyOperations(gomock.Any(), outboxBatch, outboxLease).Return(ops, nil)
+
+	client.EXPECT().AssignRoleInDomain(gomock.Any(), "u1", "member", "*").
+		DoAndReturn(func(ctx context.Context, _, _, _ string) (bool, error) {
+			actor, _ := auth.ActorFromContext(ctx)
+			assert.Equal(t, "u1", actor)
+			return true, nil
+		})
+	client.EXPECT().UnassignRoleInDomain(gomock.Any(), "u2", "admin", "*").Return(true, nil)
+	client.EXPECT().AddPolicyInDomain(gomock.Any(), "u3", "*", "streams/1", "read").
+		Return(false, auth.ErrUnavailable)
+	client.EXPECT().RemoveConditionalPolicy(gomock.Any(), "u4", "*", "streams", "read", "r.obj.Owner == r.sub").Return(true, nil)
+
+	repo.EXPECT().DeletePolicyOperation(gomock.Any(), uint64(1)).Return(nil)
+	repo.EXPECT().DeletePolicyOperation(gomock.Any(), uint64(2)).Return(nil)
+	repo.EXPECT().DeletePolicyOperation(gomock.Any(), uint64(4)).Return(nil)
+	repo.EXPECT().
+		RetryPolicyOperation(gomock.Any(), uint64(3), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uint64, next time.Time, reason string) error {
+			assert.WithinDuration(t, time.Now().Add(4*time.Second), next, time.Second)
+			assert.Contains(t, reason, "streams/1")
+			return nil
+		})
+
+	n, err := outbox.Process(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, len(ops), n)
+}
+
+func TestPolicyOutbox_RevokeRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomock.NewMockPolicyOutboxRepository(ctrl)
+	client := authmock.NewMockPermissionClient(ctrl)
+	outbox := NewPolicyOutbox(repo, client)
+
+	repo.EXPECT().ClaimPolicyOperations(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]models.PolicyOperation{
+			{ID: 1, Op: models.PolicyOperationRevokeRoles, PType: "g", Rule: []string{"u1", "*"}},
+			{ID: 2, Op: models.PolicyOperationRevokeRoles, PType: "g", Rule: []string{"u1", "org-1"}},
+			{ID: 3, Op: models.PolicyOperationRevokeRoles, PType: "g", Rule: []string{"u1"}},
+		}, nil)
+	client.EXPECT().GetImplicitRolesForUser(gomock.Any(), "u1", "*").Return([]string{"member", "editor"}, nil)
+	client.EXPECT().UnassignRoleInDomain(gomock.Any(), "u1", "member", "*").Return(true, nil)
+	client.EXPECT().UnassignRoleInDomain(gomock.Any(), "u1", "editor", "*").Return(true, nil)
+	client.EXPECT().GetImplicitRolesForUser(gomock.Any(), "u1", "org-1").Return(nil, auth.ErrUnavailable)
+
+	repo.EXPECT().DeletePolicyOperation(gomock.Any(), uint64(1)).Return(nil)
+	repo.EXPECT().RetryPolicyOperation(gomock.Any(), uint64(2), gomock.Any(), gomock.Any()).Return(nil)
+	repo.EXPECT().FailPolicyOperation(gomock.Any(), uint64(3), gomock.Any()).Return(nil)
+
+	_, err := outbox.Process(t.Context())
+	require.NoError(t, err)
+}
+
+func TestPolicyOutbox_ProcessClaimError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomock.NewMockPolicyOutboxRepository(ctrl)
+	outbox := NewPolicyOutbox(repo, authmock.NewMockPermissionClient(ctrl))
+
+	claimErr := errors.New("connection refused")
+	repo.EXPECT().ClaimPolicyOperations(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, claimErr)
+
+	_, err := outbox.Process(t.Context())
+	assert.ErrorIs(t, err, claimErr)
+}
+
+func TestPolicyOutbox_InvalidRuleFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomock.NewMockPolicyOutboxRepository(ctrl)
+	client := authmock.NewMockPermissionClient(ctrl)
+	outbox := NewPolicyOutbox(repo, client)
+
+	repo.EXPECT().ClaimPolicyOperations(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]models.PolicyOperation{
+			{ID: 7, Op: models.PolicyChangeAdd, PType: "g", Rule: []string{"u1"}},
+			{ID: 8, Op: models.PolicyChangeAdd, PType: "p", Rule: []string{"u2", "*", "streams", "read"}},
+		}, nil)
+	client.EXPECT().AddPolicyInDomain(gomock.Any(), "u2", "*", "streams", "read").
+		Return(false, &auth.Error{Op: "AddPolicy", Code: codes.InvalidArgument, Message: "bad rule"})
+	repo.EXPECT().
+		FailPolicyOperation(gomock.Any(), uint64(7), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uint64, reason string) error {
+			assert.Contains(t, reason, ErrInvalidPolicyRule.Error())
+			return nil
+		})
+	repo.EXPECT().FailPolicyOperation(gomock.Any(), uint64(8), gomock.Any()).Return(nil)
+
+	_, err := outbox.Process(t.Context())
+	require.NoError(t, err)
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, outboxBackoff(1))
+	assert.Equal(t, 2*time.Second, outboxBackoff(2))
+	assert.Equal(t, 8*time.Second, outboxBackoff(4))
+	assert.Equal(t, outboxMaxBackoff, outboxBackoff(20))
+	assert.Equal(t, outboxMaxBackoff, outboxBackoff(1000))
+}
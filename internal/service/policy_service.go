@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+)
+
+var (
+	ErrPolicyIncomplete = errors.New("policy needs a subject, resource and action")
+	ErrPolicyExists     = errors.New("policy already exists")
+	ErrPolicyNotFound   = errors.New("policy not found")
+)
+
+// PolicyService manages policies on behalf of operators. Every change goes
+// through the permission service, whose watcher propagates it.
+type PolicyService struct {
+	permissionClient PermissionClient
+}
+
+func NewPolicyService(perm PermissionClient) *PolicyService {
+	return &PolicyService{permissionClient: perm}
+}
+
+// List returns one page of the stored policies matching filter and the token
+// of the next page.
+func (s *PolicyService) List(ctx context.Context, filter auth.PolicyFilter, pageSize int32, pageToken string) ([]auth.Permission, string, error) {
+	return s.permissionClient.ListPolicies(ctx, filter, pageSize, pageToken)
+}
+
+// Create stores the policy, as an ABAC rule when it has a condition. A policy
+// without a domain applies in every domain.
+func (s *PolicyService) Create(ctx context.Context, p auth.Permission) (auth.Permission, error) {
+	p = withDomain(p)
+	if p.Subject == "" || p.Resource == "" || p.Action == "" {
+		return p, ErrPolicyIncomplete
+	}
+	var (
+		added bool
+		err   error
+	)
+	if p.Condition != "" {
+		added, err = s.permissionClient.AddConditionalPolicy(ctx, p.Subject, p.Domain, p.Resource, p.Action, p.Condition)
+	} else {
+		added, err = s.permissionClient.AddPolicyInDomain(ctx, p.Subject, p.Domain, p.Resource, p.Action)
+	}
+	if err != nil {
+		return p, err
+	}
+	if !added {
+		return p, ErrPolicyExists
+	}
+	return p, nil
+}
+
+func (s *PolicyService) Delete(ctx context.Context, p auth.Permission) error {
+	p = withDomain(p)
+	if p.Subject == "" || p.Resource == "" || p.Action == "" {
+		return ErrPolicyIncomplete
+	}
+	var (
+		removed bool
+		err     error
+	)
+	if p.Condition != "" {
+		removed, err = s.permissionClient.RemoveConditionalPolicy(ctx, p.Subject, p.Domain, p.Resource, p.Action, p.Condition)
+	} else {
+		removed, err = s.permissionClient.RemovePolicyInDomain(ctx, p.Subject, p.Domain, p.Resource, p.Action)
+	}
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrPolicyNotFound
+	}
+	return nil
+}
+
+// Replace makes policies the only stored policies matching filter.
+func (s *PolicyService) Replace(ctx context.Context, filter auth.PolicyFilter, policies []auth.Permission) (added, removed int, err error) {
+	for i, p := range policies {
+		if p.Subject == "" || p.Resource == "" || p.Action == "" {
+			return 0, 0, ErrPolicyIncomplete
+		}
+		policies[i] = withDomain(p)
+	}
+	return s.permissionClient.ReplacePolicies(ctx, filter, policies)
+}
+
+// History returns one page of the recorded policy changes matching filter,
+// newest first, and the token of the next page.
+func (s *PolicyService) History(ctx context.Context, filter auth.PolicyChangeFilter, pageSize int32, pageToken string) ([]auth.PolicyChange, string, error) {
+	return s.permissionClient.ListPolicyChanges(ctx, filter, pageSize, pageToken)
+}
+
+// Revert undoes every policy change made after revision.
+func (s *PolicyService) Revert(ctx context.Context, revision uint64, dryRun bool) (*auth.PolicyPlan, error) {
+	return s.permissionClient.RevertPolicies(ctx, revision, dryRun)
+}
+
+func withDomain(p auth.Permission) auth.Permission {
+	if p.Domain == "" {
+		p.Domain = auth.GlobalDomain
+	}
+	return p
+}
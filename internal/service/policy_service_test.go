@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+	authmock "github.com/mrhumster/web-server-gin/pkg/auth/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPolicyService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	permissionClient := authmock.NewMockPermissionClient(ctrl)
+	service := NewPolicyService(permissionClient)
+	ctx := context.Background()
+
+	permissionClient.EXPECT().AddPolicyInDomain(gomock.Any(), "member", auth.GlobalDomain, "users", "read").Return(true, nil)
+	policy, err := service.Create(ctx, auth.Permission{Subject: "member", Resource: "users", Action: "read"})
+	require.NoError(t, err)
+	assert.Equal(t, auth.GlobalDomain, policy.Domain)
+
+	permissionClient.EXPECT().AddConditionalPolicy(gomock.Any(), "member", "org1", "stream", "read", "r2.attrs.env.hour < 18").Return(false, nil)
+	_, err = service.Create(ctx, auth.Permission{Subject: "member", Domain: "org1", Resource: "stream", Action: "read", Condition: "r2.attrs.env.hour < 18"})
+	assert.ErrorIs(t, err, ErrPolicyExists)
+
+	_, err = service.Create(ctx, auth.Permission{Subject: "member", Resource: "users"})
+	assert.ErrorIs(t, err, ErrPolicyIncomplete)
+}
+
+func TestPolicyService_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	permissionClient := authmock.NewMockPermissionClient(ctrl)
+	service := NewPolicyService(permissionClient)
+	ctx := context.Background()
+
+	permissionClient.EXPECT().RemovePolicyInDomain(gomock.Any(), "member", auth.GlobalDomain, "users", "read").Return(true, nil)
+	require.NoError(t, service.Delete(ctx, auth.Permission{Subject: "member", Resource: "users", Action: "read"}))
+
+	permissionClient.EXPECT().RemovePolicyInDomain(gomock.Any(), "member", "org1", "users", "read").Return(false, nil)
+	err := service.Delete(ctx, auth.Permission{Subject: "member", Domain: "org1", Resource: "users", Action: "read"})
+	assert.ErrorIs(t, err, ErrPolicyNotFound)
+}
+
+func TestRoleService_ReadRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	permissionClient := authmock.NewMockPermissionClient(ctrl)
+	service := NewRoleService(nil, permissionClient)
+
+	first := auth.Permission{Subject: "admin", Domain: auth.GlobalDomain, Resource: "users/*", Action: "read"}
+	second := auth.Permission{Subject: "admin", Domain: auth.GlobalDomain, Resource: "users/*", Action: "write"}
+	filter := auth.PolicyFilter{Subject: "admin"}
+	permissionClient.EXPECT().ListPolicies(gomock.Any(), filter, int32(0), "").Return([]auth.Permission{first}, "next", nil)
+	permissionClient.EXPECT().ListPolicies(gomock.Any(), filter, int32(0), "next").Return([]auth.Permission{second}, "", nil)
+	permissionClient.EXPECT().GetRoleMembers(gomock.Any(), "admin").Return([]string{"bob"}, nil)
+
+	role, err := service.ReadRole(context.Background(), "admin")
+	require.NoError(t, err)
+	assert.Equal(t, &RoleDetails{Role: "admin", Policies: []auth.Permission{first, second}, Members: []string{"bob"}}, role)
+}
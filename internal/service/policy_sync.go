@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidPolicyRule = errors.New("invalid policy rule")
+
+// ruleArity is the number of values of each rule type in model.conf.
+var ruleArity = map[string]int{"p": 4, "p2": 5, "g": 3}
+
+// PolicyPlan is what a sync adds and removes.
+type PolicyPlan struct {
+	Added   []PolicyRule
+	Removed []PolicyRule
+}
+
+func validatePolicyRule(rule PolicyRule) error {
+	arity, ok := ruleArity[rule.PType]
+	if !ok {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidPolicyRule, rule.PType)
+	}
+	if len(rule.Values) != arity {
+		return fmt.Errorf("%w: %s needs %d values, got %d", ErrInvalidPolicyRule, rule.PType, arity, len(rule.Values))
+	}
+	if slices.Contains(rule.Values, "") {
+		return fmt.Errorf("%w: empty value in %s, %s", ErrInvalidPolicyRule, rule.PType, strings.Join(rule.Values, ", "))
+	}
+	if rule.PType == "p2" {
+		return validateCondition(rule.Values[4])
+	}
+	return nil
+}
+
+// SyncPolicies adds the rules that are missing and, with prune, removes the
+// stored rules that are not listed: policies in the domains the listed
+// policies use and role links of the subjects the listed links start from.
+// Anything else, like user role assignments and organization policies made
+// at runtime, is never pruned. With dryRun the plan is only computed.
+func (p *PermissionService) SyncPolicies(ctx context.Context, rules []PolicyRule, prune, dryRun bool) (PolicyPlan, error) {
+	want := make(map[string][][]string)
+	domains := make(map[string]bool)
+	linked := make(map[string]bool)
+	for i, rule := range rules {
+		if err := validatePolicyRule(rule); err != nil {
+			return PolicyPlan{}, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+		want[rule.PType] = append(want[rule.PType], rule.Values)
+		if rule.PType == "g" {
+			linked[rule.Values[0]] = true
+		} else {
+			domains[rule.Values[1]] = true
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var (
+		plan  PolicyPlan
+		steps []policyStep
+	)
+	m := p.enforcer.GetModel()
+	for _, ptype := range []string{"p", "p2", "g"} {
+		sec := ptype[:1]
+		var have [][]string
+		if ast, ok := m[sec][ptype]; ok {
+			have = ast.Policy
+		}
+		if prune {
+			owned := slices.DeleteFunc(slices.Clone(have), func(rule []string) bool {
+				if sec == "g" {
+					return !linked[rule[0]]
+				}
+				return !domains[rule[1]]
+			})
+			stale := missingRules(owned, want[ptype])
+			if len(stale) > 0 {
+				steps = append(steps, policyStep{PolicyEventRemove, ptype, stale})
+			}
+			plan.Removed = append(plan.Removed, policyRules(ptype, stale)...)
+		}
+		fresh := missingRules(want[ptype], have)
+		if len(fresh) > 0 {
+			steps = append(steps, policyStep{PolicyEventAdd, ptype, fresh})
+		}
+		plan.Added = append(plan.Added, policyRules(ptype, fresh)...)
+	}
+	if dryRun {
+		return plan, nil
+	}
+	if err := p.applySteps(ctx, steps); err != nil {
+		return PolicyPlan{}, err
+	}
+	return plan, nil
+}
+
+// addNamed and removeNamed store rules through the enforcer, so the watcher
+// tells the other replicas, and report them as applied. The caller holds the
+// write lock.
+func (p *PermissionService) addNamed(ctx context.Context, sec, ptype string, rules [][]string) error {
+	var err error
+	if sec == "g" {
+		_, err = p.enforcer.AddNamedGroupingPolicies(ptype, rules)
+	} else {
+		_, err = p.enforcer.AddNamedPolicies(ptype, rules)
+	}
+	if err != nil {
+		return err
+	}
+	return p.applied(ctx, PolicyEventAdd, policyRules(ptype, rules)...)
+}
+
+func (p *PermissionService) removeNamed(ctx context.Context, sec, ptype string, rules [][]string) error {
+	var err error
+	if sec == "g" {
+		_, err = p.enforcer.RemoveNamedGroupingPolicies(ptype, rules)
+	} else {
+		_, err = p.enforcer.RemoveNamedPolicies(ptype, rules)
+	}
+	if err != nil {
+		return err
+	}
+	return p.applied(ctx, PolicyEventRemove, policyRules(ptype, rules)...)
+}
+
+// policyStep is a batch of rules of one type added or removed as part of a
+// change made in several steps.
+type policyStep struct {
+	typ   PolicyEventType
+	ptype string
+	rules [][]string
+}
+
+// applySteps applies the steps in order. When one fails, the steps applied
+// before it are undone in reverse order, so the change takes effect whole or
+// not at all. The caller holds the write lock.
+func (p *PermissionService) applySteps(ctx context.Context, steps []policyStep) error {
+	for i, step := range steps {
+		var err error
+		if step.typ == PolicyEventAdd {
+			err = p.addNamed(ctx, step.ptype[:1], step.ptype, step.rules)
+		} else {
+			err = p.removeNamed(ctx, step.ptype[:1], step.ptype, step.rules)
+		}
+		if err == nil {
+			continue
+		}
+		for _, done := range slices.Backward(steps[:i]) {
+			if undoErr := p.revertStep(ctx, done); undoErr != nil {
+				slog.Error("Partly applied policy change not undone", "ptype", done.ptype, "rules", len(done.rules), "error", undoErr)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// revertStep undoes an applied step and records and publishes the undo.
+// Unlike a step, the undo stays in effect when it can't be recorded, since
+// failing it would leave the change half applied.
+func (p *PermissionService) revertStep(ctx context.Context, step policyStep) error {
+	rules := policyRules(step.ptype, step.rules)
+	if err := p.undo(step.typ, rules); err != nil {
+		return err
+	}
+	typ := PolicyEventAdd
+	if step.typ == PolicyEventAdd {
+		typ = PolicyEventRemove
+	}
+	if err := p.record(ctx, typ, rules); err != nil {
+		slog.Error("Policy change undo not recorded", "ptype", step.ptype, "rules", len(rules), "error", err)
+	}
+	p.events.publish(typ, rules...)
+	return nil
+}
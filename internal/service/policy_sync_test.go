@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionService_SyncPolicies(t *testing.T) {
+	ps := newTestPermissionService(t)
+	_, err := ps.AddPolicy(t.Context(), "member", global, "users", "read")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(t.Context(), "member", global, "orgs", "create")
+	require.NoError(t, err)
+	_, err = ps.AddPolicy(t.Context(), "org_admin", "org1", "orgs/org1", "write")
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "admin", "member", global)
+	require.NoError(t, err)
+	_, err = ps.AssignRole(t.Context(), "alice", "admin", global)
+	require.NoError(t, err)
+
+	file := []PolicyRule{
+		{PType: "p", Values: []string{"member", global, "users", "read"}},
+		{PType: "p", Values: []string{"member", global, "stream", "read"}},
+		{PType: "g", Values: []string{"admin", "member", global}},
+	}
+	_, live, cancel := ps.events.subscribe(ps.events.epoch, 0)
+	defer cancel()
+
+	plan, err := ps.SyncPolicies(t.Context(), file, true, true)
+	require.NoError(t, err)
+	assert.Equal(t, []PolicyRule{{PType: "p", Values: []string{"member", global, "stream", "read"}}}, plan.Added)
+	assert.Equal(t, []PolicyRule{{PType: "p", Values: []string{"member", global, "orgs", "create"}}}, plan.Removed,
+		"the organization policy and alice's role are outside the file and kept")
+	has, err := ps.enforcer.HasPolicy("member", global, "orgs", "create")
+	require.NoError(t, err)
+	assert.True(t, has, "a dry run changes nothing")
+	assert.Empty(t, live)
+
+	applied, err := ps.SyncPolicies(t.Context(), file, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, plan, applied)
+	assert.Equal(t, PolicyEventRemove, (<-live).Type)
+	assert.Equal(t, PolicyEventAdd, (<-live).Type)
+	for _, rule := range [][]string{{"member", global, "stream", "read"}, {"org_admin", "org1", "orgs/org1", "write"}} {
+		has, err := ps.enforcer.HasPolicy(rule)
+		require.NoError(t, err)
+		assert.True(t, has, rule)
+	}
+	allowed, err := ps.CheckPermission("alice", global, "users", "read", Attributes{})
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	again, err := ps.SyncPolicies(t.Context(), file, true, false)
+	require.NoError(t, err)
+	assert.Empty(t, again.Added)
+	assert.Empty(t, again.Removed)
+}
+
+func TestPermissionService_SyncPolicies_Invalid(t *testing.T) {
+	ps := newTestPermissionService(t)
+	for _, rule := range []PolicyRule{
+		{PType: "x", Values: []string{"a"}},
+		{PType: "p", Values: []string{"member", global, "users"}},
+		{PType: "g", Values: []string{"alice", "", global}},
+	} {
+		_, err := ps.SyncPolicies(t.Context(), []PolicyRule{rule}, false, false)
+		assert.ErrorIs(t, err, ErrInvalidPolicyRule, rule.PType)
+	}
+	_, err := ps.SyncPolicies(t.Context(), []PolicyRule{{PType: "p2", Values: []string{"member", global, "users", "read", "r2.attrs.("}}}, false, false)
+	assert.ErrorIs(t, err, ErrInvalidCondition)
+}
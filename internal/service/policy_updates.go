@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/casbin/casbin/v2/model"
+	rediswatcher "github.com/casbin/redis-watcher/v2"
+)
+
+// onWatcherMessage applies a change another replica published. Incremental
+// messages touch only the rules they name; anything else reloads every
+// policy.
+func (p *PermissionService) onWatcherMessage(msg string) {
+	var m rediswatcher.MSG
+	if err := m.UnmarshalBinary([]byte(msg)); err != nil {
+		slog.Warn("Unreadable Casbin watcher message, reloading", "error", err)
+		p.reload()
+		return
+	}
+
+	p.mu.Lock()
+	applied, err := p.applyUpdate(&m)
+	p.mu.Unlock()
+	if err != nil {
+		slog.Warn("Casbin update not applied, reloading", "method", m.Method, "error", err)
+		p.reload()
+		return
+	}
+	if !applied {
+		p.reload()
+	}
+}
+
+func (p *PermissionService) reload() {
+	p.mu.Lock()
+	err := p.enforcer.LoadPolicy()
+	if err == nil {
+		p.events.publish(PolicyEventReload)
+	}
+	p.mu.Unlock()
+	if err != nil {
+		slog.Error("❌ Failed to reload Casbin policies", "error", err)
+	} else {
+		slog.Info("✅ Casbin policies reloaded successfully")
+	}
+}
+
+// applyUpdate applies an incremental watcher message to the in-memory model
+// only; the replica that made the change has stored it already. It reports
+// false for messages that need a full reload. The caller holds the write
+// lock.
+func (p *PermissionService) applyUpdate(m *rediswatcher.MSG) (bool, error) {
+	switch m.Method {
+	case rediswatcher.UpdateForAddPolicy:
+		return true, p.addRules(m.Sec, m.Ptype, [][]string{m.NewRule})
+	case rediswatcher.UpdateForAddPolicies:
+		return true, p.addRules(m.Sec, m.Ptype, m.NewRules)
+	case rediswatcher.UpdateForRemovePolicy:
+		return true, p.removeRules(m.Sec, m.Ptype, [][]string{m.NewRule})
+	case rediswatcher.UpdateForRemovePolicies:
+		return true, p.removeRules(m.Sec, m.Ptype, m.NewRules)
+	case rediswatcher.UpdateForRemoveFilteredPolicy:
+		if len(m.FieldValues) == 0 {
+			return false, nil
+		}
+		_, removed, err := p.enforcer.GetModel().RemoveFilteredPolicy(m.Sec, m.Ptype, m.FieldIndex, m.FieldValues...)
+		if err != nil {
+			return true, err
+		}
+		return true, p.rulesChanged(model.PolicyRemove, m.Sec, m.Ptype, removed)
+	case rediswatcher.UpdateForUpdatePolicy:
+		if err := p.removeRules(m.Sec, m.Ptype, [][]string{m.OldRule}); err != nil {
+			return true, err
+		}
+		return true, p.addRules(m.Sec, m.Ptype, [][]string{m.NewRule})
+	case rediswatcher.UpdateForUpdatePolicies:
+		if err := p.removeRules(m.Sec, m.Ptype, m.OldRules); err != nil {
+			return true, err
+		}
+		return true, p.addRules(m.Sec, m.Ptype, m.NewRules)
+	default:
+		// Update, UpdateForSavePolicy and methods added to the watcher later.
+		return false, nil
+	}
+}
+
+func (p *PermissionService) addRules(sec, ptype string, rules [][]string) error {
+	added, err := p.enforcer.GetModel().AddPoliciesWithAffected(sec, ptype, rules)
+	if err != nil {
+		return err
+	}
+	return p.rulesChanged(model.PolicyAdd, sec, ptype, added)
+}
+
+func (p *PermissionService) removeRules(sec, ptype string, rules [][]string) error {
+	removed, err := p.enforcer.GetModel().RemovePoliciesWithAffected(sec, ptype, rules)
+	if err != nil {
+		return err
+	}
+	return p.rulesChanged(model.PolicyRemove, sec, ptype, removed)
+}
+
+// rulesChanged updates the role links for grouping rules and tells policy
+// watchers about the rules that actually changed.
+func (p *PermissionService) rulesChanged(op model.PolicyOp, sec, ptype string, rules [][]string) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	if sec == "g" {
+		if err := p.enforcer.BuildIncrementalRoleLinks(op, ptype, rules); err != nil {
+			return fmt.Errorf("update role links: %w", err)
+		}
+	}
+	typ := PolicyEventAdd
+	if op == model.PolicyRemove {
+		typ = PolicyEventRemove
+	}
+	changed := make([]PolicyRule, len(rules))
+	for i, rule := range rules {
+		changed[i] = PolicyRule{PType: ptype, Values: rule}
+	}
+	p.events.publish(typ, changed...)
+	return nil
+}
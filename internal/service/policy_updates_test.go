@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	fileadapter "github.com/casbin/casbin/v2/persist/file-adapter"
+	rediswatcher "github.com/casbin/redis-watcher/v2"
+	"github.com/mrhumster/web-server-gin/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFilePermissionService serves the policies in a CSV file, standing in for
+// the casbin_rule table another replica writes to.
+func newFilePermissionService(tb testing.TB, lines []string) (*PermissionService, string) {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "policy.csv")
+	require.NoError(tb, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600))
+	e, err := casbin.NewEnforcer(filepath.Join(config.GetRootDir(), "config", "model.conf"), fileadapter.NewAdapter(path))
+	require.NoError(tb, err)
+	return newPermissionService(e), path
+}
+
+func watcherMessage(tb testing.TB, m rediswatcher.MSG) string {
+	tb.Helper()
+	data, err := m.MarshalBinary()
+	require.NoError(tb, err)
+	return string(data)
+}
+
+func TestPermissionService_OnWatcherMessage(t *testing.T) {
+	ps, path := newFilePermissionService(t, []string{"p, member, *, users, read"})
+	_, live, cancel := ps.events.subscribe(ps.events.epoch, 0)
+	defer cancel()
+	allowed := func() bool {
+		ok, err := ps.CheckPermission("alice", global, "users", "read", Attributes{})
+		require.NoError(t, err)
+		return ok
+	}
+
+	ps.onWatcherMessage(watcherMessage(t, rediswatcher.MSG{
+		Method: rediswatcher.UpdateForAddPolicy, Sec: "g", Ptype: "g", NewRule: []string{"alice", "member", global},
+	}))
+	assert.True(t, allowed(), "role links follow an added grouping rule")
+	assert.Equal(t, PolicyEventAdd, (<-live).Type)
+
+	ps.onWatcherMessage(watcherMessage(t, rediswatcher.MSG{
+		Method: rediswatcher.UpdateForAddPolicy, Sec: "g", Ptype: "g", NewRule: []string{"alice", "member", global},
+	}))
+	assert.Empty(t, live, "a rule that is already there changes nothing")
+
+	ps.onWatcherMessage(watcherMessage(t, rediswatcher.MSG{
+		Method: rediswatcher.UpdateForRemoveFilteredPolicy, Sec: "g", Ptype: "g", FieldIndex: 0, FieldValues: []string{"alice"},
+	}))
+	assert.False(t, allowed())
+	ev := <-live
+	assert.Equal(t, PolicyEventRemove, ev.Type)
+	assert.Equal(t, []PolicyRule{{PType: "g", Values: []string{"alice", "member", global}}}, ev.Rules)
+
+	// Nothing but a full reload picks up what the incremental messages left
+	// out, so the file only changes here.
+	require.NoError(t, os.WriteFile(path, []byte("p, member, *, users, read\ng, alice, member, *\n"), 0o600))
+	ps.onWatcherMessage(watcherMessage(t, rediswatcher.MSG{Method: rediswatcher.Update}))
+	assert.True(t, allowed())
+	assert.Equal(t, PolicyEventReload, (<-live).Type)
+
+	require.NoError(t, os.WriteFile(path, []byte("p, member, *, users, read\n"), 0o600))
+	ps.onWatcherMessage("not json")
+	assert.False(t, allowed(), "unreadable messages reload")
+}
+
+// BenchmarkWatcherMessage compares applying another replica's single added
+// policy with reloading all 100k policies, as every signup used to cause.
+func BenchmarkWatcherMessage(b *testing.B) {
+	lines := make([]string, 0, 100_000)
+	for i := range 50_000 {
+		lines = append(lines,
+			fmt.Sprintf("p, user%d, *, files/%d, write", i, i),
+			fmt.Sprintf("g, user%d, member, *", i))
+	}
+	ps, _ := newFilePermissionService(b, lines)
+
+	b.Run("reload", func(b *testing.B) {
+		msg := watcherMessage(b, rediswatcher.MSG{Method: rediswatcher.Update})
+		for b.Loop() {
+			ps.onWatcherMessage(msg)
+		}
+	})
+	b.Run("incremental", func(b *testing.B) {
+		add := watcherMessage(b, rediswatcher.MSG{
+			Method: rediswatcher.UpdateForAddPolicy, Sec: "p", Ptype: "p", NewRule: []string{"signup", global, "files/new", "write"},
+		})
+		remove := watcherMessage(b, rediswatcher.MSG{
+			Method: rediswatcher.UpdateForRemovePolicy, Sec: "p", Ptype: "p", NewRule: []string{"signup", global, "files/new", "write"},
+		})
+		for b.Loop() {
+			ps.onWatcherMessage(add)
+			ps.onWatcherMessage(remove)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+	rediswatcher "github.com/casbin/redis-watcher/v2"
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/config"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Policy watchers tell the other replicas about policy changes. Redis and
+// Postgres reach replicas anywhere; memory reaches only services in the same
+// process.
+const (
+	WatcherRedis    = "redis"
+	WatcherPostgres = "postgres"
+	WatcherMemory   = "memory"
+)
+
+// NewPolicyWatcher creates the watcher cfg.PolicyWatcher selects. Postgres
+// uses the database policies are stored in, so it needs nothing else.
+func NewPolicyWatcher(cfg *config.Config, db *gorm.DB) (persist.Watcher, error) {
+	switch cfg.PolicyWatcher {
+	case WatcherRedis:
+		return NewRedisWatcher(cfg.Redis)
+	case WatcherPostgres, "":
+		return NewPostgresWatcher(db, cfg.GetDsn(), PostgresPolicyChannel)
+	case WatcherMemory:
+		return NewMemoryWatcher(nil), nil
+	default:
+		return nil, fmt.Errorf("unknown policy watcher %q, want %s, %s or %s",
+			cfg.PolicyWatcher, WatcherRedis, WatcherPostgres, WatcherMemory)
+	}
+}
+
+// NewRedisWatcher publishes changes on the Redis channel local permission
+// clients may follow too.
+func NewRedisWatcher(cfg config.Redis) (persist.Watcher, error) {
+	slog.Info("Redis conn password ", "length", len(cfg.Password))
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       0,
+	})
+
+	w, err := rediswatcher.NewWatcher(cfg.Addr, rediswatcher.WatcherOptions{
+		SubClient: redisClient,
+		PubClient: redisClient,
+		Channel:   auth.PolicyChannel,
+		// Changes made here are applied and published already.
+		IgnoreSelf: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error create csbin watcher: %w", err)
+	}
+	return w, nil
+}
+
+// reloadMessage makes onWatcherMessage reload every policy.
+const reloadMessage = `{"Method":"Update"}`
+
+// watcherMessages implements the publishing side of persist.WatcherEx and
+// persist.UpdatableWatcher with the messages of the Redis watcher, so every
+// watcher feeds onWatcherMessage the same way. Each message carries the id of
+// its sender, which ignores its own messages.
+type watcherMessages struct {
+	id      string
+	publish func(data []byte) error
+}
+
+func newWatcherMessages(publish func(data []byte) error) watcherMessages {
+	return watcherMessages{id: uuid.NewString(), publish: publish}
+}
+
+func (w watcherMessages) send(m *rediswatcher.MSG) error {
+	m.ID = w.id
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return w.publish(data)
+}
+
+// own reports whether msg was sent by this watcher.
+func (w watcherMessages) own(msg string) bool {
+	var m rediswatcher.MSG
+	return m.UnmarshalBinary([]byte(msg)) == nil && m.ID == w.id
+}
+
+func (w watcherMessages) Update() error {
+	return w.send(&rediswatcher.MSG{Method: rediswatcher.Update})
+}
+
+func (w watcherMessages) UpdateForAddPolicy(sec, ptype string, params ...string) error {
+	return w.send(&rediswatcher.MSG{Method: rediswatcher.UpdateForAddPolicy, Sec: sec, Ptype: ptype, NewRule: params})
+}
+
+func (w watcherMessages) UpdateForRemovePolicy(sec, ptype string, params ...string) error {
+	return w.send(&rediswatcher.MSG{Method: rediswatcher.UpdateForRemovePolicy, Sec: sec, Ptype: ptype, NewRule: params})
+}
+
+func (w watcherMessages) UpdateForRemoveFilteredPolicy(sec, ptype string, fieldIndex int, fieldValues ...string) error {
+	return w.send(&rediswatcher.MSG{
+		Method:      rediswatcher.UpdateForRemoveFilteredPolicy,
+		Sec:         sec,
+		Ptype:       ptype,
+		FieldIndex:  fieldIndex,
+		FieldValues: fieldValues,
+	})
+}
+
+func (w watcherMessages) UpdateForSavePolicy(model.Model) error {
+	return w.send(&rediswatcher.MSG{Method: rediswatcher.UpdateForSavePolicy})
+}
+
+func (w watcherMessages) UpdateForAddPolicies(sec, ptype string, rules ...[]string) error {
+	return w.send(&rediswatcher.MSG{Method: rediswatcher.UpdateForAddPolicies, Sec: sec, Ptype: ptype, NewRules: rules})
+}
+
+func (w watcherMessages) UpdateForRemovePolicies(sec, ptype string, rules ...[]string) error {
+	return w.send(&rediswatcher.MSG{Method: rediswatcher.UpdateForRemovePolicies, Sec: sec, Ptype: ptype, NewRules: rules})
+}
+
+func (w watcherMessages) UpdateForUpdatePolicy(sec, ptype string, oldRule, newRule []string) error {
+	return w.send(&rediswatcher.MSG{Method: rediswatcher.UpdateForUpdatePolicy, Sec: sec, Ptype: ptype, OldRule: oldRule, NewRule: newRule})
+}
+
+func (w watcherMessages) UpdateForUpdatePolicies(sec, ptype string, oldRules, newRules [][]string) error {
+	return w.send(&rediswatcher.MSG{Method: rediswatcher.UpdateForUpdatePolicies, Sec: sec, Ptype: ptype, OldRules: oldRules, NewRules: newRules})
+}
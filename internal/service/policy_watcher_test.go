@@ -0,0 +1,107 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	rediswatcher "github.com/casbin/redis-watcher/v2"
+	"github.com/mrhumster/web-server-gin/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryWatcher_ReplicatesChanges(t *testing.T) {
+	hub := NewMemoryHub()
+	newReplica := func() *PermissionService {
+		ps, _ := newFilePermissionService(t, []string{"p, member, *, users, read"})
+		// The file adapter can't save single rules; the replicas only need
+		// the messages.
+		ps.enforcer.EnableAutoSave(false)
+		replica, err := NewPermissionService(ps.enforcer, NewMemoryWatcher(hub))
+		require.NoError(t, err)
+		t.Cleanup(func() { replica.Close() })
+		return replica
+	}
+	a, b := newReplica(), newReplica()
+	_, live, cancel := b.events.subscribe(b.events.epoch, 0)
+	defer cancel()
+	next := func() PolicyEvent {
+		select {
+		case ev := <-live:
+			return ev
+		case <-time.After(time.Second):
+			t.Fatal("the change did not reach the other replica")
+			return PolicyEvent{}
+		}
+	}
+	allowed := func() bool {
+		ok, err := b.CheckPermission("alice", global, "users", "read", Attributes{})
+		require.NoError(t, err)
+		return ok
+	}
+
+	_, err := a.AssignRole(t.Context(), "alice", "member", global)
+	require.NoError(t, err)
+	ev := next()
+	assert.Equal(t, PolicyEventAdd, ev.Type, "applied incrementally, not reloaded")
+	assert.Equal(t, []PolicyRule{{PType: "g", Values: []string{"alice", "member", global}}}, ev.Rules)
+	assert.True(t, allowed())
+
+	_, err = a.UnassignRole(t.Context(), "alice", "member", global)
+	require.NoError(t, err)
+	assert.Equal(t, PolicyEventRemove, next().Type)
+	assert.False(t, allowed())
+}
+
+func TestMemoryWatcher_IgnoresOwnMessages(t *testing.T) {
+	hub := NewMemoryHub()
+	w := NewMemoryWatcher(hub)
+	peer := NewMemoryWatcher(hub)
+	defer w.Close()
+
+	got := make(chan string, 2)
+	require.NoError(t, w.SetUpdateCallback(func(msg string) { got <- msg }))
+	require.NoError(t, peer.SetUpdateCallback(func(msg string) { got <- "peer: " + msg }))
+
+	require.NoError(t, w.Update())
+	select {
+	case msg := <-got:
+		assert.Contains(t, msg, "peer: ")
+	case <-time.After(time.Second):
+		t.Fatal("peer got no message")
+	}
+
+	peer.Close()
+	require.NoError(t, w.Update())
+	select {
+	case msg := <-got:
+		t.Fatalf("closed or own watcher got %q", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWatcherMessages(t *testing.T) {
+	var sent []string
+	w := newWatcherMessages(func(data []byte) error {
+		sent = append(sent, string(data))
+		return nil
+	})
+	require.NoError(t, w.UpdateForRemovePolicies("p", "p", []string{"member", "*", "users", "read"}))
+
+	var m rediswatcher.MSG
+	require.NoError(t, m.UnmarshalBinary([]byte(sent[0])))
+	assert.Equal(t, rediswatcher.UpdateForRemovePolicies, m.Method)
+	assert.Equal(t, [][]string{{"member", "*", "users", "read"}}, m.NewRules)
+	assert.True(t, w.own(sent[0]))
+	assert.False(t, newWatcherMessages(nil).own(sent[0]))
+	assert.False(t, w.own(reloadMessage))
+}
+
+func TestNewPolicyWatcher(t *testing.T) {
+	w, err := NewPolicyWatcher(&config.Config{PolicyWatcher: WatcherMemory}, nil)
+	require.NoError(t, err)
+	w.Close()
+
+	_, err = NewPolicyWatcher(&config.Config{PolicyWatcher: "etcd"}, nil)
+	assert.ErrorContains(t, err, "etcd")
+}
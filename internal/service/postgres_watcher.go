@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	rediswatcher "github.com/casbin/redis-watcher/v2"
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// PostgresPolicyChannel is the LISTEN/NOTIFY channel of the Postgres watcher.
+const PostgresPolicyChannel = "casbin_policy"
+
+// notifyPayloadLimit is the largest NOTIFY payload Postgres accepts, less a
+// margin. Bigger changes are announced as a plain update, which makes the
+// other replicas reload.
+const notifyPayloadLimit = 7900
+
+const (
+	listenMinBackoff = time.Second
+	listenMaxBackoff = 30 * time.Second
+)
+
+// PostgresWatcher tells the other replicas about policy changes with
+// LISTEN/NOTIFY on the database the policies are stored in. It notifies
+// through db and listens on a connection of its own, reconnecting when it
+// drops; after a reconnect the replica reloads, since notifications sent in
+// the meantime are lost.
+type PostgresWatcher struct {
+	watcherMessages
+	channel string
+
+	mu        sync.Mutex
+	callback  func(string)
+	connected atomic.Bool
+	stop      context.CancelFunc
+	done      chan struct{}
+}
+
+// NewPostgresWatcher starts listening on channel with a connection to dsn.
+func NewPostgresWatcher(db *gorm.DB, dsn, channel string) (*PostgresWatcher, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	conn, err := listen(ctx, dsn, channel)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("error create postgres watcher: %w", err)
+	}
+	w := &PostgresWatcher{channel: channel, stop: cancel, done: make(chan struct{})}
+	w.watcherMessages = newWatcherMessages(func(data []byte) error {
+		if len(data) > notifyPayloadLimit {
+			var err error
+			if data, err = (&rediswatcher.MSG{Method: rediswatcher.Update, ID: w.id}).MarshalBinary(); err != nil {
+				return err
+			}
+		}
+		return db.Exec("SELECT pg_notify(?, ?)", channel, string(data)).Error
+	})
+	w.connected.Store(true)
+	go w.run(ctx, conn, dsn)
+	return w, nil
+}
+
+func listen(ctx context.Context, dsn, channel string) (*pgx.Conn, error) {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		conn.Close(context.Background())
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (w *PostgresWatcher) run(ctx context.Context, conn *pgx.Conn, dsn string) {
+	defer close(w.done)
+	backoff := listenMinBackoff
+	for {
+		err := w.receive(ctx, conn)
+		w.connected.Store(false)
+		conn.Close(context.Background())
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("Postgres policy watcher disconnected", "error", err)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if conn, err = listen(ctx, dsn, w.channel); err == nil {
+				break
+			}
+			backoff = min(backoff*2, listenMaxBackoff)
+			slog.Warn("Postgres policy watcher not reconnected", "retry", backoff, "error", err)
+		}
+		backoff = listenMinBackoff
+		w.connected.Store(true)
+		slog.Info("Postgres policy watcher reconnected")
+		// Changes made while disconnected were not heard.
+		w.deliver(reloadMessage)
+	}
+}
+
+func (w *PostgresWatcher) receive(ctx context.Context, conn *pgx.Conn) error {
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		if !w.own(n.Payload) {
+			w.deliver(n.Payload)
+		}
+	}
+}
+
+func (w *PostgresWatcher) deliver(msg string) {
+	w.mu.Lock()
+	callback := w.callback
+	w.mu.Unlock()
+	if callback != nil {
+		callback(msg)
+	}
+}
+
+// Healthy fails while the watcher is not listening.
+func (w *PostgresWatcher) Healthy() error {
+	if !w.connected.Load() {
+		return errors.New("postgres policy watcher is disconnected")
+	}
+	return nil
+}
+
+func (w *PostgresWatcher) SetUpdateCallback(callback func(string)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callback = callback
+	return nil
+}
+
+// Close stops listening and waits for the listener to finish.
+func (w *PostgresWatcher) Close() {
+	w.stop()
+	<-w.done
+}
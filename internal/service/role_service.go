@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+)
+
+var (
+	ErrRoleEmpty    = errors.New("role can't be empty")
+	ErrRoleReserved = errors.New("role name is reserved")
+)
+
+type RoleService struct {
+	users            *UserService
+	permissionClient PermissionClient
+}
+
+func NewRoleService(users *UserService, perm PermissionClient) *RoleService {
+	return &RoleService{users: users, permissionClient: perm}
+}
+
+// AssignRole grants the role to the user and makes it the user's primary role,
+// so the role claim in issued tokens follows the Casbin grouping. Roles held
+// before stay assigned until unassigned; DeleteUser revokes all of them.
+func (s *RoleService) AssignRole(ctx context.Context, role string, userID uuid.UUID) (bool, error) {
+	if role == "" {
+		return false, ErrRoleEmpty
+	}
+	// Anyone holding the "self" role would own every users/:id resource.
+	if role == models.SelfSubject {
+		return false, ErrRoleReserved
+	}
+	if _, err := s.users.ReadUser(ctx, userID); err != nil {
+		return false, ErrUserNotFount
+	}
+	assigned, err := s.permissionClient.AssignRole(ctx, userID.String(), role)
+	if err != nil {
+		return false, err
+	}
+	if err := s.users.UpdateRole(ctx, userID, role); err != nil {
+		return assigned, err
+	}
+	return assigned, nil
+}
+
+// UnassignRole revokes the role. When it was the user's primary role the user
+// falls back to the member role.
+func (s *RoleService) UnassignRole(ctx context.Context, role string, userID uuid.UUID) (bool, error) {
+	if role == "" {
+		return false, ErrRoleEmpty
+	}
+	user, err := s.users.ReadUser(ctx, userID)
+	if err != nil {
+		return false, ErrUserNotFount
+	}
+	unassigned, err := s.permissionClient.UnassignRole(ctx, userID.String(), role)
+	if err != nil {
+		return false, err
+	}
+	if user.Role == role && role != models.RoleMember {
+		if _, err := s.permissionClient.AssignRole(ctx, userID.String(), models.RoleMember); err != nil {
+			return unassigned, err
+		}
+		if err := s.users.UpdateRole(ctx, userID, models.RoleMember); err != nil {
+			return unassigned, err
+		}
+	}
+	return unassigned, nil
+}
+
+func (s *RoleService) ListMembers(ctx context.Context, role string) ([]string, error) {
+	if role == "" {
+		return nil, ErrRoleEmpty
+	}
+	return s.permissionClient.GetRoleMembers(ctx, role)
+}
+
+// RoleDetails is a role with the policies granted to it and its members.
+type RoleDetails struct {
+	Role     string
+	Policies []auth.Permission
+	Members  []string
+}
+
+// ListRoles returns the roles assigned in domain, or in any domain when
+// domain is empty.
+func (s *RoleService) ListRoles(ctx context.Context, domain string) ([]string, error) {
+	return s.permissionClient.ListRoles(ctx, domain)
+}
+
+// ReadRole returns the policies stored for the role, across domains, and its
+// members in the global domain.
+func (s *RoleService) ReadRole(ctx context.Context, role string) (*RoleDetails, error) {
+	if role == "" {
+		return nil, ErrRoleEmpty
+	}
+	details := &RoleDetails{Role: role}
+	filter := auth.PolicyFilter{Subject: role}
+	token := ""
+	for {
+		policies, next, err := s.permissionClient.ListPolicies(ctx, filter, 0, token)
+		if err != nil {
+			return nil, err
+		}
+		details.Policies = append(details.Policies, policies...)
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	members, err := s.permissionClient.GetRoleMembers(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	details.Members = members
+	return details, nil
+}
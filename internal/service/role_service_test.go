@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/internal/domain/models"
+	repomock "github.com/mrhumster/web-server-gin/internal/repository/mock"
+	authmock "github.com/mrhumster/web-server-gin/pkg/auth/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestRoleService_AssignRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockUserRepository(ctrl)
+	permissionClient := authmock.NewMockPermissionClient(ctrl)
+	service := NewRoleService(NewUserService(repo, permissionClient), permissionClient)
+
+	userID := uuid.New()
+	user := models.User{Email: "admin@test.local", Role: models.RoleMember}
+	user.ID = userID
+
+	repo.EXPECT().ReadUserByID(gomock.Any(), userID).Return(&user, nil)
+	permissionClient.EXPECT().AssignRole(gomock.Any(), userID.String(), models.RoleAdmin).Return(true, nil)
+	repo.EXPECT().UpdateRole(gomock.Any(), userID, models.RoleAdmin).Return(nil)
+
+	assigned, err := service.AssignRole(context.Background(), models.RoleAdmin, userID)
+	require.NoError(t, err)
+	assert.True(t, assigned)
+}
+
+func TestRoleService_AssignRole_UnknownUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockUserRepository(ctrl)
+	permissionClient := authmock.NewMockPermissionClient(ctrl)
+	service := NewRoleService(NewUserService(repo, permissionClient), permissionClient)
+
+	userID := uuid.New()
+	repo.EXPECT().ReadUserByID(gomock.Any(), userID).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.AssignRole(context.Background(), models.RoleAdmin, userID)
+	assert.ErrorIs(t, err, ErrUserNotFount)
+
+	_, err = service.AssignRole(context.Background(), "", userID)
+	assert.ErrorIs(t, err, ErrRoleEmpty)
+}
+
+func TestRoleService_UnassignPrimaryRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockUserRepository(ctrl)
+	permissionClient := authmock.NewMockPermissionClient(ctrl)
+	service := NewRoleService(NewUserService(repo, permissionClient), permissionClient)
+
+	userID := uuid.New()
+	user := models.User{Email: "admin@test.local", Role: models.RoleAdmin}
+	user.ID = userID
+
+	repo.EXPECT().ReadUserByID(gomock.Any(), userID).Return(&user, nil)
+	permissionClient.EXPECT().UnassignRole(gomock.Any(), userID.String(), models.RoleAdmin).Return(true, nil)
+	permissionClient.EXPECT().AssignRole(gomock.Any(), userID.String(), models.RoleMember).Return(true, nil)
+	repo.EXPECT().UpdateRole(gomock.Any(), userID, models.RoleMember).Return(nil)
+
+	unassigned, err := service.UnassignRole(context.Background(), models.RoleAdmin, userID)
+	require.NoError(t, err)
+	assert.True(t, unassigned)
+}
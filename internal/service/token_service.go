@@ -2,10 +2,14 @@ package service
 
 import (
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -22,6 +26,18 @@ type TokenService struct {
 	accessExpiry      time.Duration
 	refreshExpiry     time.Duration
 	issuer            string
+	// accessKeyID names the access key in JWKS and token headers.
+	accessKeyID string
+}
+
+// JSONWebKey is an RSA public key in JWK form (RFC 7517).
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
 }
 
 func NewTokenService(cfg *config.JWT) (*TokenService, error) {
@@ -49,22 +65,62 @@ func NewTokenService(cfg *config.JWT) (*TokenService, error) {
 		accessExpiry:      cfg.AccessTokenExpiry,
 		refreshExpiry:     cfg.RefreshTokenExpiry,
 		issuer:            cfg.Issuer,
+		accessKeyID:       keyThumbprint(accessPublicKey),
 	}, nil
 }
 
+// keyThumbprint is the RFC 7638 thumbprint of key, so the same key gets the
+// same id on every replica.
+func keyThumbprint(key *rsa.PublicKey) string {
+	data, _ := json.Marshal(map[string]string{
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		"kty": "RSA",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+	})
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// JWKS returns the key access and service tokens are signed with. Refresh
+// tokens are only ever verified here, so their key is not published.
+func (s *TokenService) JWKS() []JSONWebKey {
+	return []JSONWebKey{{
+		Kty: "RSA",
+		Kid: s.accessKeyID,
+		Use: "sig",
+		Alg: jwt.SigningMethodRS256.Alg(),
+		N:   base64.RawURLEncoding.EncodeToString(s.accessPublicKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.accessPublicKey.E)).Bytes()),
+	}}
+}
+
+// signAccess signs claims with the access key, naming it in the kid header.
+func (s *TokenService) signAccess(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.accessKeyID
+	return token.SignedString(s.accessPrivateKey)
+}
+
 func (s *TokenService) GenerateToken(user *models.User) (*models.TokenPair, error) {
+	return s.GenerateTokenForOrganization(user, "")
+}
+
+// GenerateTokenForOrganization issues a token pair scoped to orgID. An empty
+// orgID gives a platform-scoped token.
+func (s *TokenService) GenerateTokenForOrganization(user *models.User, orgID string) (*models.TokenPair, error) {
 	accessExpiresAt := time.Now().Add(s.accessExpiry)
 	accessClaims := &models.AccessClaims{
-		UserID: user.ID.String(),
-		Role:   user.Role,
+		UserID:       user.ID.String(),
+		Role:         user.Role,
+		OrgID:        orgID,
+		TokenVersion: user.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(accessExpiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    s.issuer,
 		},
 	}
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodRS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString(s.accessPrivateKey)
+	accessTokenString, err := s.signAccess(accessClaims)
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +128,7 @@ func (s *TokenService) GenerateToken(user *models.User) (*models.TokenPair, erro
 	refreshClaims := &models.RefreshClaims{
 		UserID:       fmt.Sprintf("%s", user.ID),
 		TokenVersion: user.TokenVersion,
+		OrgID:        orgID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(refreshExpiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -92,6 +149,48 @@ func (s *TokenService) GenerateToken(user *models.User) (*models.TokenPair, erro
 	}, nil
 }
 
+// GenerateServiceToken issues a token identifying the named service to the
+// permission gRPC server. It is signed with the access key and lives as long
+// as an access token.
+func (s *TokenService) GenerateServiceToken(name string) (string, time.Time, error) {
+	if name == "" {
+		return "", time.Time{}, errors.New("service name can't be empty")
+	}
+	expiresAt := time.Now().Add(s.accessExpiry)
+	claims := &models.ServiceClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   name,
+			Audience:  jwt.ClaimStrings{models.ServiceAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    s.issuer,
+		},
+	}
+	token, err := s.signAccess(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// ValidateServiceToken checks a service token and returns the service name.
+func (s *TokenService) ValidateServiceToken(tokenString string) (string, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &models.ServiceClaims{}, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return s.accessPublicKey, nil
+	}, jwt.WithAudience(models.ServiceAudience), jwt.WithIssuer(s.issuer))
+	if err != nil {
+		return "", err
+	}
+	claims, ok := token.Claims.(*models.ServiceClaims)
+	if !ok || !token.Valid || claims.Subject == "" {
+		return "", errors.New("invalid token")
+	}
+	return claims.Subject, nil
+}
+
 func (s *TokenService) ValidateAccessToken(tokenString string) (*dto.AccessClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &dto.AccessClaims{}, func(token *jwt.Token) (any, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
@@ -102,7 +201,8 @@ func (s *TokenService) ValidateAccessToken(tokenString string) (*dto.AccessClaim
 	if err != nil {
 		return nil, err
 	}
-	if claims, ok := token.Claims.(*dto.AccessClaims); ok && token.Valid {
+	// Service tokens share the signing key but carry no user.
+	if claims, ok := token.Claims.(*dto.AccessClaims); ok && token.Valid && claims.UserID != "" {
 		return claims, nil
 	}
 	return nil, errors.New("invalid token")
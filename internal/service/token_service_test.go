@@ -1,7 +1,10 @@
 package service
 
 import (
+	"crypto/rsa"
+	"encoding/base64"
 	"fmt"
+	"math/big"
 	"testing"
 	"time"
 
@@ -55,3 +58,54 @@ func TestTokenService_ValidateToken_Invalid(t *testing.T) {
 	_, err = service.ValidateAccessToken(expiredToken)
 	assert.Error(t, err)
 }
+
+func TestTokenService_ServiceToken(t *testing.T) {
+	cfg, _ := config.TestConfig()
+	service, _ := NewTokenService(&cfg.JWT)
+
+	token, expiresAt, err := service.GenerateServiceToken("billing")
+	assert.NoError(t, err)
+	assert.True(t, expiresAt.After(time.Now()))
+
+	name, err := service.ValidateServiceToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "billing", name)
+
+	_, err = service.ValidateAccessToken(token)
+	assert.Error(t, err, "a service token must not pass as a user token")
+
+	pair, err := service.GenerateToken(&models.User{Role: "admin"})
+	assert.NoError(t, err)
+	_, err = service.ValidateServiceToken(pair.AccessToken)
+	assert.Error(t, err, "a user token must not pass as a service token")
+}
+
+func TestTokenService_JWKS(t *testing.T) {
+	cfg, _ := config.TestConfig()
+	service, _ := NewTokenService(&cfg.JWT)
+
+	keys := service.JWKS()
+	assert.Len(t, keys, 1)
+	key := keys[0]
+	assert.Equal(t, "RSA", key.Kty)
+	assert.Equal(t, "RS256", key.Alg)
+	assert.NotEmpty(t, key.Kid)
+
+	pair, err := service.GenerateToken(&models.User{Role: "member", TokenVersion: "v2"})
+	assert.NoError(t, err)
+
+	// A consumer verifies the token with the published key alone.
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	assert.NoError(t, err)
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	assert.NoError(t, err)
+	pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	claims := &models.AccessClaims{}
+	token, err := jwt.ParseWithClaims(pair.AccessToken, claims, func(token *jwt.Token) (any, error) {
+		assert.Equal(t, key.Kid, token.Header["kid"])
+		return pub, nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, token.Valid)
+	assert.Equal(t, "v2", claims.TokenVersion)
+}
@@ -4,38 +4,68 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"sync"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/mrhumster/web-server-gin/internal/delivery/http/dto/request"
 	"github.com/mrhumster/web-server-gin/internal/domain/models"
 	"github.com/mrhumster/web-server-gin/internal/repository"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
 	"gorm.io/gorm"
 )
 
 var (
 	ErrUserAlreadyExists = errors.New("user already exists")
 	ErrUserNotFount      = errors.New("user not found")
+	ErrUserDisabled      = errors.New("user is disabled")
 )
 
 type UserService struct {
 	repo             repository.UserRepository
 	permissionClient PermissionClient
-	mu               sync.RWMutex
+	outbox           *PolicyOutbox
+	templates        *auth.ProvisioningTemplates
 }
 
 func NewUserService(repo repository.UserRepository, perm PermissionClient) *UserService {
-	return &UserService{repo: repo, permissionClient: perm}
+	return &UserService{repo: repo, permissionClient: perm, templates: auth.DefaultProvisioningTemplates()}
 }
 
+// SetProvisioningTemplates replaces the templates of config/provisioning.yaml.
+// The "user" template is granted to every new user and revoked on deletion.
+func (s *UserService) SetProvisioningTemplates(t *auth.ProvisioningTemplates) {
+	s.templates = t
+}
+
+// SetPolicyOutbox wakes outbox whenever a user's policy operations are
+// stored, so they are applied right away.
+func (s *UserService) SetPolicyOutbox(outbox *PolicyOutbox) {
+	s.outbox = outbox
+}
+
+// CreateUser stores the user together with the rules of its provisioning
+// template in the policy outbox, so the user never exists without the rights
+// it is meant to have.
 func (s *UserService) CreateUser(ctx context.Context, user models.User) (*uuid.UUID, error) {
 	if user.Role == "" {
-		role := "member"
-		user.Role = role
+		user.Role = models.RoleMember
+	}
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	// Signups are recorded as made by the new user unless someone else is
+	// creating the account.
+	actor, ok := auth.ActorFromContext(ctx)
+	if !ok {
+		actor = user.ID.String()
+	}
+	rules, err := s.templates.Render(userResource, userValues(user))
+	if err != nil {
+		return nil, err
 	}
-	id, err := s.repo.CreateUser(ctx, user)
+	id, err := s.repo.CreateUser(ctx, user, policyOperations(models.PolicyChangeAdd, rules, actor))
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -48,18 +78,7 @@ func (s *UserService) CreateUser(ctx context.Context, user models.User) (*uuid.U
 		}
 		return nil, err
 	}
-
-	s.mu.Lock()
-	policy := id.String()
-	resource := fmt.Sprintf("users/%s", id.String())
-	log.Printf("⚠️ UserService. CreateUser Permission debug: %s %s", policy, resource)
-	s.permissionClient.AddPolicy(ctx, policy, resource, "read")
-	s.permissionClient.AddPolicy(ctx, policy, resource, "write")
-	s.permissionClient.AddPolicy(ctx, policy, resource, "delete")
-	s.permissionClient.AddPolicy(ctx, policy, "users", "read")
-	s.permissionClient.AddPolicy(ctx, policy, "stream", "read")
-	s.permissionClient.AddPolicy(ctx, policy, "stream", "write")
-	s.mu.Unlock()
+	s.notifyOutbox()
 	return id, nil
 }
 
@@ -72,24 +91,157 @@ func (s *UserService) UpdateUser(ctx context.Context, id uuid.UUID, user request
 	return s.repo.UpdateUser(ctx, id, user)
 }
 
+// DeleteUser deletes the user and queues, in the same transaction, revoking
+// what its provisioning template granted and every role it holds globally or
+// in its organizations, including roles it was assigned before its current
+// one.
 func (s *UserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
-	err := s.repo.DeleteUserByID(ctx, id)
-	if err == nil {
-		policy := id.String()
-		resource := fmt.Sprintf("users/%s", id.String())
-		s.permissionClient.RemovePolicy(ctx, policy, resource, "read")
-		s.permissionClient.RemovePolicy(ctx, policy, resource, "write")
-		s.permissionClient.RemovePolicy(ctx, policy, resource, "delete")
-		s.permissionClient.RemovePolicy(ctx, policy, "users", "read")
-		s.permissionClient.RemovePolicy(ctx, policy, "stream", "read")
+	user, err := s.repo.ReadUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	rules, err := s.templates.Render(userResource, userValues(*user))
+	if err != nil {
+		return err
+	}
+	slices.Reverse(rules)
+	orgs, err := s.repo.ReadOrganizationIDsForUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	actor, _ := auth.ActorFromContext(ctx)
+	ops := policyOperations(models.PolicyChangeRemove, rules, actor)
+	for _, dom := range append([]string{auth.GlobalDomain}, uuidStrings(orgs)...) {
+		ops = append(ops, models.PolicyOperation{
+			Op:      models.PolicyOperationRevokeRoles,
+			PType:   "g",
+			Rule:    []string{id.String(), dom},
+			Subject: id.String(),
+			Actor:   actor,
+		})
+	}
+	if err := s.repo.DeleteUserByID(ctx, id, ops); err != nil {
+		return err
+	}
+	s.notifyOutbox()
+	return nil
+}
+
+// userResource is the provisioning template type of users.
+const userResource = "user"
+
+// userValues fills the placeholders of the user template.
+func userValues(user models.User) map[string]string {
+	return map[string]string{"id": user.ID.String(), "email": user.Email, "role": user.Role}
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out
+}
+
+func (s *UserService) notifyOutbox() {
+	if s.outbox != nil {
+		s.outbox.Notify()
+	}
+}
+
+// ReadUsers returns the users with the given ids that exist, in no
+// particular order.
+func (s *UserService) ReadUsers(ctx context.Context, ids []uuid.UUID) ([]models.User, error) {
+	return s.repo.ReadUsersByIDs(ctx, ids)
+}
+
+// SearchUsers returns a window of the users whose email contains query,
+// newest first, and the number of matches.
+func (s *UserService) SearchUsers(ctx context.Context, query string, offset, limit int) ([]models.User, int64, error) {
+	return s.repo.SearchUsers(ctx, query, offset, limit)
+}
+
+// DisableUser stops the user from signing in. Changing the token version
+// makes its refresh tokens invalid; access tokens run out on their own.
+func (s *UserService) DisableUser(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	if err := s.repo.DisableUser(ctx, id, NewTokenVersion()); err != nil {
+		return nil, err
+	}
+	return s.repo.ReadUserByID(ctx, id)
+}
+
+// TokenRevocation tells why the tokens of the user issued at tokenVersion
+// are revoked, or returns "" when they are not. An empty version, as in
+// access tokens issued before they carried one, is not compared.
+func (s *UserService) TokenRevocation(ctx context.Context, id uuid.UUID, tokenVersion string) (string, error) {
+	user, err := s.repo.ReadUserByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "user deleted", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case user.Disabled:
+		return "user disabled", nil
+	case tokenVersion != "" && tokenVersion != user.TokenVersion:
+		return "token version changed", nil
 	}
-	return err
+	return "", nil
+}
+
+// NewTokenVersion returns a token version that differs from the previous
+// ones, for revoking a user's tokens.
+func NewTokenVersion() string {
+	return "v" + time.Now().Format("20060102150405")
 }
 
 func (s *UserService) ReadUserList(ctx context.Context, limit, page int64) ([]models.User, int64, error) {
 	return s.repo.ReadUserList(ctx, limit, page)
 }
 
+// FilterReadable keeps the users the caller may read in the given domain,
+// checking the whole page with a single batch call.
+func (s *UserService) FilterReadable(ctx context.Context, callerID uuid.UUID, domain string, users []models.User) ([]models.User, error) {
+	if len(users) == 0 {
+		return users, nil
+	}
+	checks := make([]auth.CheckRequest, len(users))
+	for i, user := range users {
+		checks[i] = auth.CheckRequest{
+			UserID:   callerID.String(),
+			Domain:   domain,
+			Resource: fmt.Sprintf("users/%s", user.ID),
+			Action:   "read",
+		}
+	}
+	allowed, err := s.permissionClient.BatchCheckPermission(ctx, checks)
+	if err != nil {
+		return nil, err
+	}
+	visible := make([]models.User, 0, len(users))
+	for i, user := range users {
+		if allowed[i] {
+			visible = append(visible, user)
+		}
+	}
+	return visible, nil
+}
+
+// ListPermissions returns the user's roles and one page of the policies that
+// apply to them in the domain, so clients can hide actions they can't use.
+func (s *UserService) ListPermissions(ctx context.Context, userID uuid.UUID, domain string, pageSize int32, pageToken string) ([]string, []auth.Permission, string, error) {
+	roles, err := s.permissionClient.GetImplicitRolesForUser(ctx, userID.String(), domain)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	perms, next, err := s.permissionClient.ListPermissionsForSubject(ctx, userID.String(), domain, pageSize, pageToken)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return roles, perms, next, nil
+}
+
 func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	return s.repo.ReadUserByEmail(ctx, email)
 }
@@ -102,11 +254,18 @@ func (s *UserService) ValidateUser(ctx context.Context, email, password string)
 	}
 
 	if user.CheckPassword(password) {
+		if user.Disabled {
+			return nil, ErrUserDisabled
+		}
 		return user, nil
 	}
 	return nil, errors.New("invalid password")
 }
 
+func (s *UserService) UpdateRole(ctx context.Context, userID uuid.UUID, role string) error {
+	return s.repo.UpdateRole(ctx, userID, role)
+}
+
 func (s *UserService) UpdateTokenVersion(ctx context.Context, userID *uuid.UUID, version string) error {
 	return s.repo.UpdateTokenVersion(ctx, userID, version)
 }
@@ -7,10 +7,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/mrhumster/web-server-gin/internal/domain/models"
 	repomock "github.com/mrhumster/web-server-gin/internal/repository/mock"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
 	authmock "github.com/mrhumster/web-server-gin/pkg/auth/mock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func TestUserService_Create(t *testing.T) {
@@ -29,19 +31,17 @@ func TestUserService_Create(t *testing.T) {
 	repo.EXPECT().
 		CreateUser(
 			gomock.Any(),
-			gomock.AssignableToTypeOf(models.User{})).
+			gomock.AssignableToTypeOf(models.User{}),
+			[]models.PolicyOperation{{
+				Op:      models.PolicyChangeAdd,
+				PType:   "g",
+				Rule:    []string{userID.String(), "member", auth.GlobalDomain},
+				Subject: userID.String(),
+				Actor:   userID.String(),
+			}}).
 		Return(&userID, nil).
 		Times(1)
 
-	permissionClient.EXPECT().
-		AddPolicy(
-			gomock.Any(),
-			gomock.Any(),
-			gomock.Any(),
-			gomock.Any()).
-		Return(true, nil).
-		AnyTimes()
-
 	service := NewUserService(repo, permissionClient)
 
 	ctx := context.Background()
@@ -51,6 +51,42 @@ func TestUserService_Create(t *testing.T) {
 	require.Equal(t, userID, *id)
 }
 
+func TestUserService_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockUserRepository(ctrl)
+	permissionClient := authmock.NewMockPermissionClient(ctrl)
+
+	user := models.User{Role: "admin"}
+	user.ID = uuid.New()
+	org := uuid.New()
+	revokeRoles := func(dom string) models.PolicyOperation {
+		return models.PolicyOperation{
+			Op:      models.PolicyOperationRevokeRoles,
+			PType:   "g",
+			Rule:    []string{user.ID.String(), dom},
+			Subject: user.ID.String(),
+			Actor:   "operator",
+		}
+	}
+	repo.EXPECT().ReadUserByID(gomock.Any(), user.ID).Return(&user, nil)
+	repo.EXPECT().ReadOrganizationIDsForUser(gomock.Any(), user.ID).Return([]uuid.UUID{org}, nil)
+	// Roles assigned besides the template's, globally or in the user's
+	// organizations, are revoked too.
+	repo.EXPECT().
+		DeleteUserByID(gomock.Any(), user.ID, []models.PolicyOperation{{
+			Op:      models.PolicyChangeRemove,
+			PType:   "g",
+			Rule:    []string{user.ID.String(), "admin", auth.GlobalDomain},
+			Subject: user.ID.String(),
+			Actor:   "operator",
+		}, revokeRoles(auth.GlobalDomain), revokeRoles(org.String())}).
+		Return(nil)
+
+	service := NewUserService(repo, permissionClient)
+	require.NoError(t, service.DeleteUser(auth.WithActor(t.Context(), "operator"), user.ID))
+}
+
 func TestUserService_Validate(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -69,7 +105,8 @@ func TestUserService_Validate(t *testing.T) {
 	repo.EXPECT().
 		CreateUser(
 			gomock.Any(),
-			gomock.AssignableToTypeOf(models.User{})).
+			gomock.AssignableToTypeOf(models.User{}),
+			gomock.Any()).
 		Return(&userID, nil).
 		Times(1)
 
@@ -95,6 +132,14 @@ func TestUserService_Validate(t *testing.T) {
 		Return(true, nil).
 		AnyTimes()
 
+	permissionClient.EXPECT().
+		AssignRole(
+			gomock.Any(),
+			gomock.Any(),
+			"member").
+		Return(true, nil).
+		AnyTimes()
+
 	ctx := context.Background()
 	t.Run("Password validate success", func(t *testing.T) {
 
@@ -106,3 +151,116 @@ func TestUserService_Validate(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestUserService_FilterReadable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockUserRepository(ctrl)
+	permissionClient := authmock.NewMockPermissionClient(ctrl)
+	service := NewUserService(repo, permissionClient)
+
+	callerID := uuid.New()
+	users := make([]models.User, 3)
+	for i := range users {
+		users[i].ID = uuid.New()
+	}
+
+	permissionClient.EXPECT().
+		BatchCheckPermission(gomock.Any(), gomock.Len(len(users))).
+		DoAndReturn(func(_ context.Context, checks []auth.CheckRequest) ([]bool, error) {
+			for i, check := range checks {
+				assert.Equal(t, callerID.String(), check.UserID)
+				assert.Equal(t, auth.GlobalDomain, check.Domain)
+				assert.Equal(t, "users/"+users[i].ID.String(), check.Resource)
+				assert.Equal(t, "read", check.Action)
+			}
+			return []bool{true, false, true}, nil
+		}).
+		Times(1)
+
+	visible, err := service.FilterReadable(context.Background(), callerID, auth.GlobalDomain, users)
+	require.NoError(t, err)
+	assert.Equal(t, []models.User{users[0], users[2]}, visible)
+}
+
+func TestUserService_ProvisioningTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomock.NewMockUserRepository(ctrl)
+	service := NewUserService(repo, authmock.NewMockPermissionClient(ctrl))
+
+	templates, err := auth.ParseProvisioningTemplates([]byte(`
+templates:
+  user:
+    policies:
+      - {subject: "{id}", resource: "users/{id}", actions: [read, write]}
+    roles:
+      - {subject: "{id}", role: "{role}"}
+`))
+	require.NoError(t, err)
+	service.SetProvisioningTemplates(templates)
+
+	user := models.User{Email: "u@example.com"}
+	user.ID = uuid.New()
+	id := user.ID.String()
+	op := func(op, ptype string, rule ...string) models.PolicyOperation {
+		return models.PolicyOperation{Op: op, PType: ptype, Rule: rule, Subject: id, Actor: "operator"}
+	}
+	ctx := auth.WithActor(t.Context(), "operator")
+
+	repo.EXPECT().
+		CreateUser(gomock.Any(), gomock.Any(), []models.PolicyOperation{
+			op(models.PolicyChangeAdd, "p", id, "*", "users/"+id, "read"),
+			op(models.PolicyChangeAdd, "p", id, "*", "users/"+id, "write"),
+			op(models.PolicyChangeAdd, "g", id, "member", "*"),
+		}).
+		Return(&user.ID, nil)
+	_, err = service.CreateUser(ctx, user)
+	require.NoError(t, err)
+
+	user.Role = models.RoleMember
+	repo.EXPECT().ReadUserByID(gomock.Any(), user.ID).Return(&user, nil)
+	repo.EXPECT().ReadOrganizationIDsForUser(gomock.Any(), user.ID).Return(nil, nil)
+	repo.EXPECT().
+		DeleteUserByID(gomock.Any(), user.ID, []models.PolicyOperation{
+			op(models.PolicyChangeRemove, "g", id, "member", "*"),
+			op(models.PolicyChangeRemove, "p", id, "*", "users/"+id, "write"),
+			op(models.PolicyChangeRemove, "p", id, "*", "users/"+id, "read"),
+			op(models.PolicyOperationRevokeRoles, "g", id, "*"),
+		}).
+		Return(nil)
+	require.NoError(t, service.DeleteUser(ctx, user.ID))
+}
+
+func TestUserService_TokenRevocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomock.NewMockUserRepository(ctrl)
+	service := NewUserService(repo, nil)
+	ctx := context.Background()
+
+	active := models.User{TokenVersion: "v2"}
+	active.ID = uuid.New()
+	disabled := models.User{TokenVersion: "v2", Disabled: true}
+	disabled.ID = uuid.New()
+	deleted := uuid.New()
+	repo.EXPECT().ReadUserByID(gomock.Any(), active.ID).Return(&active, nil).AnyTimes()
+	repo.EXPECT().ReadUserByID(gomock.Any(), disabled.ID).Return(&disabled, nil)
+	repo.EXPECT().ReadUserByID(gomock.Any(), deleted).Return(nil, gorm.ErrRecordNotFound)
+
+	cases := []struct {
+		id      uuid.UUID
+		version string
+		reason  string
+	}{
+		{active.ID, "v2", ""},
+		{active.ID, "", ""},
+		{active.ID, "v1", "token version changed"},
+		{disabled.ID, "v2", "user disabled"},
+		{deleted, "v2", "user deleted"},
+	}
+	for _, tc := range cases {
+		reason, err := service.TokenRevocation(ctx, tc.id, tc.version)
+		require.NoError(t, err)
+		assert.Equal(t, tc.reason, reason)
+	}
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ActorMetadataKey is the call metadata naming the user a call is made for.
+// The permission service records it with the policy changes the call makes.
+const ActorMetadataKey = "x-actor"
+
+// TenantMetadataKey is the call metadata naming the organization the user
+// acts in, as the organization its access token is scoped to. Calls without
+// it act in GlobalDomain.
+const TenantMetadataKey = "x-tenant"
+
+type (
+	actorKey  struct{}
+	tenantKey struct{}
+)
+
+// WithActor marks the calls made with ctx as made for user, e.g. the user of
+// the HTTP request being served.
+func WithActor(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, actorKey{}, user)
+}
+
+// ActorFromContext returns the user set by WithActor.
+func ActorFromContext(ctx context.Context) (string, bool) {
+	user, ok := ctx.Value(actorKey{}).(string)
+	return user, ok && user != ""
+}
+
+// WithTenant marks the calls made with ctx as made in the organization org.
+func WithTenant(ctx context.Context, org string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, org)
+}
+
+// TenantFromContext returns the organization set by WithTenant.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	org, ok := ctx.Value(tenantKey{}).(string)
+	return org, ok && org != ""
+}
+
+func actorInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(actorMetadata(ctx), method, req, reply, cc, opts...)
+}
+
+func actorStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(actorMetadata(ctx), desc, cc, method, opts...)
+}
+
+// actorMetadata puts the actor and tenant of ctx into its call metadata.
+func actorMetadata(ctx context.Context) context.Context {
+	if user, ok := ActorFromContext(ctx); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx, ActorMetadataKey, user)
+	}
+	if org, ok := TenantFromContext(ctx); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx, TenantMetadataKey, org)
+	}
+	return ctx
+}
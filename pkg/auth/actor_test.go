@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestActorInterceptor(t *testing.T) {
+	sent := func(ctx context.Context) []string {
+		var actors []string
+		err := actorInterceptor(ctx, "/permission.PermissionService/AddPolicy", nil, nil, nil,
+			func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				actors = md.Get(ActorMetadataKey)
+				return nil
+			})
+		assert.NoError(t, err)
+		return actors
+	}
+
+	assert.Equal(t, []string{"user-1"}, sent(WithActor(t.Context(), "user-1")))
+	assert.Empty(t, sent(t.Context()))
+	assert.Empty(t, sent(WithActor(t.Context(), "")))
+}
+
+func TestActorStreamInterceptor(t *testing.T) {
+	ctx := WithTenant(WithActor(t.Context(), "user-1"), "org-1")
+	var md metadata.MD
+	_, err := actorStreamInterceptor(ctx, &grpc.StreamDesc{ServerStreams: true}, nil, "/permission.PermissionService/WatchPolicies",
+		func(ctx context.Context, _ *grpc.StreamDesc, _ *grpc.ClientConn, _ string, _ ...grpc.CallOption) (grpc.ClientStream, error) {
+			md, _ = metadata.FromOutgoingContext(ctx)
+			return nil, nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user-1"}, md.Get(ActorMetadataKey))
+	assert.Equal(t, []string{"org-1"}, md.Get(TenantMetadataKey))
+}
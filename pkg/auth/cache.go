@@ -0,0 +1,327 @@
+package auth
+
+import (
+	"container/list"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PolicyChannel is the Redis channel the permission server's watcher
+// publishes on after every policy change.
+const PolicyChannel = "/casbin"
+
+// CacheOptions configure CachingPermissionClient. Zero values get defaults.
+type CacheOptions struct {
+	// Size is the maximum number of cached decisions.
+	Size int
+	// TTL bounds how long a decision is reused. It also bounds how stale a
+	// decision based on a time-dependent ABAC condition can get.
+	TTL time.Duration
+	// Settle is how long after an invalidation new decisions are not cached,
+	// giving every server replica time to reload its policies.
+	Settle time.Duration
+}
+
+// CacheStats are the counters of a CachingPermissionClient.
+type CacheStats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Invalidations uint64 `json:"invalidations"`
+	Entries       int    `json:"entries"`
+}
+
+type cacheEntry struct {
+	key       string
+	allowed   bool
+	expiresAt time.Time
+}
+
+// CachingPermissionClient keeps recent check decisions in an LRU in front of
+// another PermissionClient. The whole cache is dropped on every policy change
+// seen on the watcher channel or made through this client, and decisions
+// fetched while a change was in flight are never stored, so an allow is not
+// served after a revocation has been observed.
+type CachingPermissionClient struct {
+	PermissionClient
+
+	opts CacheOptions
+
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List
+	generation uint64
+	quietUntil time.Time
+	// enabled is false while the watcher is disconnected, since changes made
+	// in that time would go unnoticed.
+	enabled bool
+
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	invalidations atomic.Uint64
+}
+
+func NewCachingPermissionClient(next PermissionClient, opts CacheOptions) *CachingPermissionClient {
+	if opts.Size <= 0 {
+		opts.Size = 10000
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 10 * time.Second
+	}
+	if opts.Settle <= 0 {
+		opts.Settle = time.Second
+	}
+	return &CachingPermissionClient{
+		PermissionClient: next,
+		opts:             opts,
+		entries:          make(map[string]*list.Element),
+		lru:              list.New(),
+		enabled:          true,
+	}
+}
+
+// Invalidate drops every cached decision.
+func (c *CachingPermissionClient) Invalidate() {
+	c.mu.Lock()
+	c.invalidateLocked()
+	c.mu.Unlock()
+}
+
+func (c *CachingPermissionClient) invalidateLocked() {
+	c.generation++
+	c.quietUntil = time.Now().Add(c.opts.Settle)
+	clear(c.entries)
+	c.lru.Init()
+	c.invalidations.Add(1)
+}
+
+func (c *CachingPermissionClient) setEnabled(enabled bool) {
+	c.mu.Lock()
+	c.invalidateLocked()
+	c.enabled = enabled
+	c.mu.Unlock()
+}
+
+func (c *CachingPermissionClient) Stats() CacheStats {
+	c.mu.Lock()
+	entries := c.lru.Len()
+	c.mu.Unlock()
+	return CacheStats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Invalidations: c.invalidations.Load(),
+		Entries:       entries,
+	}
+}
+
+// lookup returns the cached decision for key and the generation a fetched
+// decision has to be stored under.
+func (c *CachingPermissionClient) lookup(key string) (allowed, found bool, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok && c.enabled {
+		entry := el.Value.(*cacheEntry)
+		if time.Now().Before(entry.expiresAt) {
+			c.lru.MoveToFront(el)
+			c.hits.Add(1)
+			return entry.allowed, true, c.generation
+		}
+		c.lru.Remove(el)
+		delete(c.entries, key)
+	}
+	c.misses.Add(1)
+	return false, false, c.generation
+}
+
+func (c *CachingPermissionClient) store(key string, allowed bool, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if !c.enabled || generation != c.generation || now.Before(c.quietUntil) {
+		return
+	}
+	if el, ok := c.entries[key]; ok {
+		c.lru.Remove(el)
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, allowed: allowed, expiresAt: now.Add(c.opts.TTL)})
+	for c.lru.Len() > c.opts.Size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *CachingPermissionClient) CheckPermission(ctx context.Context, userID, resource, action string) (bool, error) {
+	return c.Check(ctx, CheckRequest{UserID: userID, Resource: resource, Action: action})
+}
+
+func (c *CachingPermissionClient) CheckPermissionInDomain(ctx context.Context, userID, domain, resource, action string) (bool, error) {
+	return c.Check(ctx, CheckRequest{UserID: userID, Domain: domain, Resource: resource, Action: action})
+}
+
+func (c *CachingPermissionClient) Check(ctx context.Context, req CheckRequest) (bool, error) {
+	key := cacheKey(req)
+	allowed, found, generation := c.lookup(key)
+	if found {
+		return allowed, nil
+	}
+	allowed, err := c.PermissionClient.Check(ctx, req)
+	if err != nil {
+		return false, err
+	}
+	c.store(key, allowed, generation)
+	return allowed, nil
+}
+
+// BatchCheckPermission answers what it can from the cache and sends the rest
+// in one batch.
+func (c *CachingPermissionClient) BatchCheckPermission(ctx context.Context, checks []CheckRequest) ([]bool, error) {
+	results := make([]bool, len(checks))
+	keys := make([]string, len(checks))
+	var (
+		missing    []CheckRequest
+		missingIdx []int
+		generation uint64
+	)
+	for i, check := range checks {
+		keys[i] = cacheKey(check)
+		allowed, found, gen := c.lookup(keys[i])
+		if found {
+			results[i] = allowed
+			continue
+		}
+		if len(missing) == 0 {
+			generation = gen
+		}
+		missing = append(missing, check)
+		missingIdx = append(missingIdx, i)
+	}
+	if len(missing) == 0 {
+		return results, nil
+	}
+	allowed, err := c.PermissionClient.BatchCheckPermission(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range missingIdx {
+		results[i] = allowed[j]
+		c.store(keys[i], allowed[j], generation)
+	}
+	return results, nil
+}
+
+func (c *CachingPermissionClient) AddPolicy(ctx context.Context, userID, resource, action string) (bool, error) {
+	defer c.Invalidate()
+	return c.PermissionClient.AddPolicy(ctx, userID, resource, action)
+}
+
+func (c *CachingPermissionClient) RemovePolicy(ctx context.Context, userID, resource, action string) (bool, error) {
+	defer c.Invalidate()
+	return c.PermissionClient.RemovePolicy(ctx, userID, resource, action)
+}
+
+func (c *CachingPermissionClient) AddPolicyIfNotExists(ctx context.Context, userID, resource, action string) (bool, error) {
+	defer c.Invalidate()
+	return c.PermissionClient.AddPolicyIfNotExists(ctx, userID, resource, action)
+}
+
+func (c *CachingPermissionClient) AssignRole(ctx context.Context, userID, role string) (bool, error) {
+	defer c.Invalidate()
+	return c.PermissionClient.AssignRole(ctx, userID, role)
+}
+
+func (c *CachingPermissionClient) UnassignRole(ctx context.Context, userID, role string) (bool, error) {
+	defer c.Invalidate()
+	return c.PermissionClient.UnassignRole(ctx, userID, role)
+}
+
+func (c *CachingPermissionClient) AddPolicyInDomain(ctx context.Context, userID, domain, resource, action string) (bool, error) {
+	defer c.Invalidate()
+	return c.PermissionClient.AddPolicyInDomain(ctx, userID, domain, resource, action)
+}
+
+func (c *CachingPermissionClient) AssignRoleInDomain(ctx context.Context, userID, role, domain string) (bool, error) {
+	defer c.Invalidate()
+	return c.PermissionClient.AssignRoleInDomain(ctx, userID, role, domain)
+}
+
+func (c *CachingPermissionClient) UnassignRoleInDomain(ctx context.Context, userID, role, domain string) (bool, error) {
+	defer c.Invalidate()
+	return c.PermissionClient.UnassignRoleInDomain(ctx, userID, role, domain)
+}
+
+func (c *CachingPermissionClient) AddConditionalPolicy(ctx context.Context, subject, domain, resource, action, condition string) (bool, error) {
+	defer c.Invalidate()
+	return c.PermissionClient.AddConditionalPolicy(ctx, subject, domain, resource, action, condition)
+}
+
+func (c *CachingPermissionClient) RemoveConditionalPolicy(ctx context.Context, subject, domain, resource, action, condition string) (bool, error) {
+	defer c.Invalidate()
+	return c.PermissionClient.RemoveConditionalPolicy(ctx, subject, domain, resource, action, condition)
+}
+
+func (c *CachingPermissionClient) RemovePolicyInDomain(ctx context.Context, subject, domain, resource, action string) (bool, error) {
+	defer c.Invalidate()
+	return c.PermissionClient.RemovePolicyInDomain(ctx, subject, domain, resource, action)
+}
+
+func (c *CachingPermissionClient) ReplacePolicies(ctx context.Context, filter PolicyFilter, policies []Permission) (int, int, error) {
+	defer c.Invalidate()
+	return c.PermissionClient.ReplacePolicies(ctx, filter, policies)
+}
+
+func (c *CachingPermissionClient) RevertPolicies(ctx context.Context, revision uint64, dryRun bool) (*PolicyPlan, error) {
+	defer c.Invalidate()
+	return c.PermissionClient.RevertPolicies(ctx, revision, dryRun)
+}
+
+// WatchRedis invalidates the cache on every message on channel until ctx is
+// done. Caching is off until the subscription is confirmed and whenever the
+// connection drops, because changes published meanwhile are lost.
+func (c *CachingPermissionClient) WatchRedis(ctx context.Context, rdb redis.UniversalClient, channel string) {
+	c.setEnabled(false)
+	watchPolicyChannel(ctx, rdb, channel,
+		func() { c.setEnabled(true) },
+		c.Invalidate,
+		func() { c.setEnabled(false) })
+}
+
+// WatchPolicies is WatchRedis for the permission service's WatchPolicies
+// stream, for processes without access to Redis.
+func (c *CachingPermissionClient) WatchPolicies(ctx context.Context, w PolicyWatcher) {
+	c.setEnabled(false)
+	w.WatchPolicies(ctx,
+		func() { c.setEnabled(true) },
+		func(PolicyEvent) { c.Invalidate() },
+		func() { c.setEnabled(false) })
+}
+
+func cacheKey(req CheckRequest) string {
+	var b strings.Builder
+	for _, s := range []string{req.UserID, req.Domain, req.Resource, req.Action} {
+		b.WriteString(s)
+		b.WriteByte(0)
+	}
+	for _, attrs := range []map[string]string{req.Attributes.Subject, req.Attributes.Resource, req.Attributes.Environment} {
+		keys := make([]string, 0, len(attrs))
+		for k := range attrs {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			b.WriteString(k)
+			b.WriteByte('=')
+			b.WriteString(attrs[k])
+			b.WriteByte(0)
+		}
+		b.WriteByte(1)
+	}
+	return b.String()
+}
+
+var _ PermissionClient = (*CachingPermissionClient)(nil)
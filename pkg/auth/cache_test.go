@@ -0,0 +1,129 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+	authmock "github.com/mrhumster/web-server-gin/pkg/auth/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCachingPermissionClient(t *testing.T) {
+	ctx := context.Background()
+	read := auth.CheckRequest{UserID: "u1", Domain: auth.GlobalDomain, Resource: "users", Action: "read"}
+
+	t.Run("serves repeated checks from the cache", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		next := authmock.NewMockPermissionClient(ctrl)
+		c := auth.NewCachingPermissionClient(next, auth.CacheOptions{Settle: time.Nanosecond})
+
+		next.EXPECT().Check(gomock.Any(), read).Return(true, nil).Times(1)
+		for range 3 {
+			allowed, err := c.Check(ctx, read)
+			require.NoError(t, err)
+			assert.True(t, allowed)
+		}
+		stats := c.Stats()
+		assert.Equal(t, uint64(2), stats.Hits)
+		assert.Equal(t, uint64(1), stats.Misses)
+		assert.Equal(t, 1, stats.Entries)
+	})
+
+	t.Run("attributes are part of the key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		next := authmock.NewMockPermissionClient(ctrl)
+		c := auth.NewCachingPermissionClient(next, auth.CacheOptions{Settle: time.Nanosecond})
+
+		eu := read
+		eu.Attributes.Subject = map[string]string{"region": "eu"}
+		next.EXPECT().Check(gomock.Any(), read).Return(false, nil).Times(1)
+		next.EXPECT().Check(gomock.Any(), eu).Return(true, nil).Times(1)
+
+		allowed, _ := c.Check(ctx, read)
+		assert.False(t, allowed)
+		allowed, _ = c.Check(ctx, eu)
+		assert.True(t, allowed)
+	})
+
+	t.Run("a policy change drops cached allows", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		next := authmock.NewMockPermissionClient(ctrl)
+		c := auth.NewCachingPermissionClient(next, auth.CacheOptions{Settle: time.Nanosecond})
+
+		gomock.InOrder(
+			next.EXPECT().Check(gomock.Any(), read).Return(true, nil),
+			next.EXPECT().UnassignRole(gomock.Any(), "u1", "member").Return(true, nil),
+			next.EXPECT().Check(gomock.Any(), read).Return(false, nil),
+		)
+		allowed, _ := c.Check(ctx, read)
+		assert.True(t, allowed)
+		_, err := c.UnassignRole(ctx, "u1", "member")
+		require.NoError(t, err)
+		allowed, _ = c.Check(ctx, read)
+		assert.False(t, allowed)
+	})
+
+	t.Run("decisions fetched across a change are not stored", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		next := authmock.NewMockPermissionClient(ctrl)
+		c := auth.NewCachingPermissionClient(next, auth.CacheOptions{Settle: time.Nanosecond})
+
+		next.EXPECT().Check(gomock.Any(), read).DoAndReturn(func(context.Context, auth.CheckRequest) (bool, error) {
+			// The revocation is observed while the old decision is in flight.
+			c.Invalidate()
+			return true, nil
+		})
+		next.EXPECT().Check(gomock.Any(), read).Return(false, nil)
+
+		allowed, _ := c.Check(ctx, read)
+		assert.True(t, allowed)
+		allowed, _ = c.Check(ctx, read)
+		assert.False(t, allowed)
+	})
+
+	t.Run("nothing is stored while replicas settle", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		next := authmock.NewMockPermissionClient(ctrl)
+		c := auth.NewCachingPermissionClient(next, auth.CacheOptions{Settle: time.Hour})
+
+		c.Invalidate()
+		next.EXPECT().Check(gomock.Any(), read).Return(true, nil).Times(2)
+		c.Check(ctx, read)
+		c.Check(ctx, read)
+	})
+
+	t.Run("batch only sends misses", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		next := authmock.NewMockPermissionClient(ctrl)
+		c := auth.NewCachingPermissionClient(next, auth.CacheOptions{Settle: time.Nanosecond})
+
+		write := read
+		write.Action = "write"
+		next.EXPECT().Check(gomock.Any(), read).Return(true, nil)
+		next.EXPECT().BatchCheckPermission(gomock.Any(), []auth.CheckRequest{write}).Return([]bool{false}, nil)
+
+		c.Check(ctx, read)
+		allowed, err := c.BatchCheckPermission(ctx, []auth.CheckRequest{read, write})
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, false}, allowed)
+	})
+
+	t.Run("evicts the least recently used decision", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		next := authmock.NewMockPermissionClient(ctrl)
+		c := auth.NewCachingPermissionClient(next, auth.CacheOptions{Size: 1, Settle: time.Nanosecond})
+
+		write := read
+		write.Action = "write"
+		next.EXPECT().Check(gomock.Any(), read).Return(true, nil).Times(2)
+		next.EXPECT().Check(gomock.Any(), write).Return(true, nil).Times(1)
+		c.Check(ctx, read)
+		c.Check(ctx, write)
+		c.Check(ctx, read)
+		assert.Equal(t, 1, c.Stats().Entries)
+	})
+}
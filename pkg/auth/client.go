@@ -1,13 +1,11 @@
 package auth
 
-import "github.com/mrhumster/web-server-gin/internal/permission"
-
 type PermissionClientWrapper struct {
-	*permission.PermissionGRPCClient
+	*PermissionGRPCClient
 }
 
-func NewPermissionClient(url string) (*PermissionClientWrapper, error) {
-	client, err := permission.NewPermissionGRPCClient(url)
+func NewPermissionClient(url string, opts ...ClientOption) (*PermissionClientWrapper, error) {
+	client, err := NewPermissionGRPCClient(url, opts...)
 	if err != nil {
 		return nil, err
 	}
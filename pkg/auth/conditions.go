@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"strconv"
+	"time"
+)
+
+// ConditionInput builds the attrs value handed to the enforcer for the ABAC
+// matcher. Values that look like numbers or booleans are converted so
+// conditions can compare them naturally. subject.id is sub and env.hour and
+// env.weekday are taken from now, whatever the caller sent, so a request
+// can't claim ownership or a time window. The permission server and the
+// local enforcer both use it, so conditions evaluate the same in either.
+func (a Attributes) ConditionInput(sub string, now time.Time) map[string]any {
+	subject := convertAttributes(a.Subject)
+	subject["id"] = sub
+	env := convertAttributes(a.Environment)
+	env["hour"] = float64(now.Hour())
+	env["weekday"] = float64(now.Weekday())
+	return map[string]any{
+		"subject":  subject,
+		"resource": convertAttributes(a.Resource),
+		"env":      env,
+	}
+}
+
+func convertAttributes(attrs map[string]string) map[string]any {
+	out := make(map[string]any, len(attrs)+2)
+	for k, v := range attrs {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			out[k] = f
+		} else if b, err := strconv.ParseBool(v); err == nil {
+			out[k] = b
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+// TLSConfig points at the PEM files used to reach the permission server.
+// CAFile verifies the server; CertFile and KeyFile, when set, are presented
+// as the client certificate for mutual TLS.
+type TLSConfig struct {
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+// TokenSource issues a service token and reports when it expires.
+type TokenSource func() (token string, expiresAt time.Time, err error)
+
+type clientOptions struct {
+	tls    *TLSConfig
+	tokens TokenSource
+
+	callTimeout     time.Duration
+	retryAttempts   int
+	breakerFailures uint32
+	breakerTimeout  time.Duration
+}
+
+type ClientOption func(*clientOptions)
+
+// WithTLS dials the server over TLS instead of plaintext.
+func WithTLS(cfg TLSConfig) ClientOption {
+	return func(o *clientOptions) {
+		o.tls = &cfg
+	}
+}
+
+// WithServiceToken sends a service JWT with every call. Tokens are reused
+// until shortly before they expire.
+func WithServiceToken(source TokenSource) ClientOption {
+	return func(o *clientOptions) {
+		o.tokens = source
+	}
+}
+
+// KeepaliveTime is how often clients ping a connection without traffic, so a
+// dead server or a proxy dropping idle connections is noticed, WatchPolicies
+// streams included. The permission server accepts pings this often.
+const KeepaliveTime = time.Minute
+
+const keepaliveTimeout = 20 * time.Second
+
+// Dial connects to the gRPC server of the permission service with the
+// options of NewPermissionGRPCClient, for calling the other services it
+// serves.
+func Dial(url string, opts ...ClientOption) (*grpc.ClientConn, error) {
+	o := defaultClientOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	dialOpts, err := o.dialOptions()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.NewClient(url, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("⚠️ failed to connect to auth service: %w", err)
+	}
+	return conn, nil
+}
+
+func (o *clientOptions) dialOptions() ([]grpc.DialOption, error) {
+	opts := []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(actorInterceptor),
+		grpc.WithChainStreamInterceptor(actorStreamInterceptor),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                KeepaliveTime,
+			Timeout:             keepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+	}
+	if o.tls == nil {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		creds, err := o.tls.credentials()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	}
+	if o.tokens != nil {
+		opts = append(opts, grpc.WithPerRPCCredentials(&tokenCredentials{source: o.tokens}))
+	}
+	resilience, err := o.resilienceOptions()
+	if err != nil {
+		return nil, err
+	}
+	return append(opts, resilience...), nil
+}
+
+func (c *TLSConfig) credentials() (credentials.TransportCredentials, error) {
+	tlsCfg := &tls.Config{
+		ServerName: c.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read permission service CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("permission service CA %s has no certificates", c.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load permission client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
+
+// tokenRefreshMargin renews a cached token this long before it expires.
+const tokenRefreshMargin = 30 * time.Second
+
+type tokenCredentials struct {
+	source    TokenSource
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func (c *tokenCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == "" || time.Until(c.expiresAt) < tokenRefreshMargin {
+		token, expiresAt, err := c.source()
+		if err != nil {
+			return nil, fmt.Errorf("issue service token: %w", err)
+		}
+		c.token, c.expiresAt = token, expiresAt
+	}
+	return map[string]string{"authorization": "Bearer " + c.token}, nil
+}
+
+// RequireTransportSecurity is false so local setups without certificates
+// keep working; production deployments are expected to configure TLS.
+func (c *tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
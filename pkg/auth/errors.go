@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error kinds returned by PermissionGRPCClient, UserGRPCClient and
+// AuthGRPCClient. Match them with errors.Is; use errors.As with *Error for
+// the status code and field violations.
+var (
+	// ErrInvalidRequest means the server rejected the request itself, e.g. an
+	// empty subject or a malformed condition. Retrying won't help.
+	ErrInvalidRequest = errors.New("invalid permission request")
+	// ErrUnavailable means the permission service could not be reached or
+	// did not answer in time. The decision is unknown.
+	ErrUnavailable = errors.New("permission service unavailable")
+	// ErrInternal means the service answered but failed to evaluate the
+	// request, e.g. the enforcer or its storage is broken.
+	ErrInternal = errors.New("permission service internal error")
+	// ErrUnauthorized means the server did not accept this client's
+	// certificate or service token, or the caller may not use the method.
+	ErrUnauthorized = errors.New("permission service rejected the caller")
+	// ErrNotFound means the user asked for doesn't exist or, for a call made
+	// for a user, isn't visible to them.
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists means a user with the same email exists.
+	ErrAlreadyExists = errors.New("already exists")
+)
+
+// Error is a failed permission RPC.
+type Error struct {
+	Op      string
+	Code    codes.Code
+	Message string
+	// Reason is the ErrorInfo reason sent by the server, if any.
+	Reason string
+	// Violations maps request fields to what is wrong with them.
+	Violations map[string]string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s failed: %s", e.Op, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	switch e.Code {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return ErrInvalidRequest
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled, codes.ResourceExhausted:
+		return ErrUnavailable
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return ErrUnauthorized
+	case codes.NotFound:
+		return ErrNotFound
+	case codes.AlreadyExists:
+		return ErrAlreadyExists
+	default:
+		return ErrInternal
+	}
+}
+
+// IsUnavailable reports whether err means the permission service could not
+// be reached or did not answer in time, including an open circuit breaker.
+// Only then may a check fail open: a service that answered with an error, or
+// rejected this client's credentials, must not let requests through.
+func IsUnavailable(err error) bool {
+	var e *Error
+	if errors.As(err, &e) {
+		switch e.Code {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+			return true
+		default:
+			return false
+		}
+	}
+	return errors.Is(err, ErrUnavailable)
+}
+
+// rpcError converts a gRPC error into an *Error.
+func rpcError(op string, err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return &Error{Op: op, Code: codes.Unknown, Message: err.Error()}
+	}
+	e := &Error{Op: op, Code: st.Code(), Message: st.Message()}
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			e.Reason = d.GetReason()
+		case *errdetails.BadRequest:
+			e.Violations = make(map[string]string, len(d.GetFieldViolations()))
+			for _, v := range d.GetFieldViolations() {
+				e.Violations[v.GetField()] = v.GetDescription()
+			}
+		}
+	}
+	return e
+}
+
+// responseError handles servers that still report failures in the
+// deprecated error field of the response.
+func responseError(op, msg string) error {
+	return &Error{Op: op, Code: codes.Internal, Message: msg}
+}
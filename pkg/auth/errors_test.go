@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRPCError(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "check permission: invalid request").
+		WithDetails(&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "action", Description: "must not be empty"},
+		}})
+	require.NoError(t, err)
+
+	invalid := rpcError("permission check", st.Err())
+	assert.ErrorIs(t, invalid, ErrInvalidRequest)
+	assert.False(t, IsUnavailable(invalid))
+	var e *Error
+	require.True(t, errors.As(invalid, &e))
+	assert.Equal(t, map[string]string{"action": "must not be empty"}, e.Violations)
+
+	unavailable := rpcError("permission check", status.Error(codes.Unavailable, "connection refused"))
+	assert.ErrorIs(t, unavailable, ErrUnavailable)
+	assert.True(t, IsUnavailable(unavailable))
+
+	internal := rpcError("permission check", status.Error(codes.Internal, "enforcer failed"))
+	assert.ErrorIs(t, internal, ErrInternal)
+	assert.False(t, IsUnavailable(internal), "the service answered")
+
+	rejected := rpcError("permission check", status.Error(codes.Unauthenticated, "bad service token"))
+	assert.ErrorIs(t, rejected, ErrUnauthorized)
+	assert.False(t, IsUnavailable(rejected))
+
+	canceled := rpcError("permission check", status.Error(codes.Canceled, "context canceled"))
+	assert.False(t, IsUnavailable(canceled), "the caller gave up")
+
+	assert.True(t, IsUnavailable(rpcError("permission check", status.Error(codes.DeadlineExceeded, "deadline"))))
+}
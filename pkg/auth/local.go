@@ -0,0 +1,469 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+	"github.com/mrhumster/web-server-gin/config"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+)
+
+// Mode selects where a PermissionClient evaluates checks.
+type Mode string
+
+const (
+	// ModeRemote sends every call to the permission service.
+	ModeRemote Mode = "remote"
+	// ModeLocal evaluates checks in-process and never asks the service.
+	ModeLocal Mode = "local"
+	// ModeLocalWithFallback evaluates checks in-process and asks the service
+	// while local policies are missing or may be stale.
+	ModeLocalWithFallback Mode = "local-fallback"
+)
+
+// ErrReadOnly is returned by a local client without a remote for calls that
+// only the permission service can answer, such as policy changes.
+var ErrReadOnly = errors.New("local permission client has no remote service")
+
+// abacContext selects the r2/p2/e2/m2 section of the model.
+var abacContext = casbin.NewEnforceContext("2")
+
+// LocalOptions configure LocalPermissionClient.
+type LocalOptions struct {
+	// Adapter loads policies from storage, e.g. a gorm adapter on the
+	// permission database. When nil, snapshots are fetched from Remote.
+	Adapter persist.Adapter
+	// Remote serves everything but checks: policy changes, explanations and
+	// introspection. It may be nil when Adapter is set.
+	Remote PermissionClient
+	// Redis follows the policy watcher channel to reload on every change.
+	// Without it, changes are followed over the WatchPolicies stream of
+	// Remote when it has one, and applied one by one.
+	Redis redis.UniversalClient
+	// Fallback sends checks to Remote while local policies are not loaded
+	// or the watcher is disconnected, and when local enforcement fails.
+	Fallback bool
+}
+
+// LocalPermissionClient enforces with an in-process Casbin enforcer built
+// from the same model.conf as the permission server, so checks need no
+// network round trip.
+type LocalPermissionClient struct {
+	PermissionClient
+
+	enforcer *casbin.Enforcer
+	mu       sync.RWMutex
+	loaded   bool
+	// synced is false while the watcher is disconnected.
+	synced   atomic.Bool
+	fallback bool
+	remote   PermissionClient
+	stop     context.CancelFunc
+	// lastLoad is when policies were last (re)loaded, in Unix nanoseconds.
+	lastLoad atomic.Int64
+	// now is the time conditions are evaluated at.
+	now func() time.Time
+}
+
+// reloadRetryInterval throttles new attempts after policies failed to load.
+const reloadRetryInterval = 5 * time.Second
+
+func NewLocalPermissionClient(opts LocalOptions) (*LocalPermissionClient, error) {
+	adapter := opts.Adapter
+	if adapter == nil {
+		source, ok := opts.Remote.(policySnapshotter)
+		if !ok {
+			return nil, errors.New("local permission client needs an adapter or a remote that serves policy snapshots")
+		}
+		adapter = &snapshotAdapter{source: source}
+	}
+	if opts.Fallback && opts.Remote == nil {
+		return nil, errors.New("local permission client fallback needs a remote")
+	}
+	m, err := model.NewModelFromString(config.ModelConf)
+	if err != nil {
+		return nil, fmt.Errorf("load casbin model: %w", err)
+	}
+	e, err := casbin.NewEnforcer(m)
+	if err != nil {
+		return nil, fmt.Errorf("create local enforcer: %w", err)
+	}
+	e.SetAdapter(adapter)
+	e.EnableAutoSave(false)
+
+	c := &LocalPermissionClient{
+		PermissionClient: opts.Remote,
+		enforcer:         e,
+		fallback:         opts.Fallback,
+		remote:           opts.Remote,
+		stop:             func() {},
+		now:              time.Now,
+	}
+	if c.PermissionClient == nil {
+		c.PermissionClient = readOnlyClient{}
+	}
+	c.synced.Store(true)
+
+	if err := c.Reload(); err != nil {
+		if !c.fallback {
+			return nil, err
+		}
+		slog.Warn("Local policies not loaded, checks go to the permission service", "error", err)
+	}
+	if opts.Redis != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		c.stop = cancel
+		c.synced.Store(false)
+		go watchPolicyChannel(ctx, opts.Redis, PolicyChannel,
+			func() {
+				// Changes may have been missed before the subscription.
+				c.reloadOrWarn()
+				c.synced.Store(true)
+			},
+			c.reloadOrWarn,
+			func() { c.synced.Store(false) })
+	} else if w, ok := opts.Remote.(PolicyWatcher); ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		c.stop = cancel
+		c.synced.Store(false)
+		go w.WatchPolicies(ctx,
+			func() { c.synced.Store(true) },
+			c.applyOrReload,
+			func() { c.synced.Store(false) })
+	}
+	return c, nil
+}
+
+// NewPermissionClientWithMode builds the client for mode. Local modes fetch
+// policy snapshots from the service at url unless local.Adapter is set.
+func NewPermissionClientWithMode(mode Mode, url string, local LocalOptions, opts ...ClientOption) (PermissionClient, error) {
+	switch mode {
+	case ModeRemote, "":
+		return NewPermissionClient(url, opts...)
+	case ModeLocal, ModeLocalWithFallback:
+		if url != "" {
+			remote, err := NewPermissionClient(url, opts...)
+			if err != nil {
+				return nil, err
+			}
+			local.Remote = remote
+		}
+		local.Fallback = mode == ModeLocalWithFallback
+		return NewLocalPermissionClient(local)
+	default:
+		return nil, fmt.Errorf("unknown permission client mode %q", mode)
+	}
+}
+
+// Reload replaces the local policies with the current ones.
+func (c *LocalPermissionClient) Reload() error {
+	c.lastLoad.Store(time.Now().UnixNano())
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.enforcer.LoadPolicy(); err != nil {
+		return fmt.Errorf("load local policies: %w", err)
+	}
+	c.loaded = true
+	return nil
+}
+
+func (c *LocalPermissionClient) reloadOrWarn() {
+	if err := c.Reload(); err != nil {
+		slog.Error("Failed to reload local policies", "error", err)
+	}
+}
+
+// Apply applies a policy change to the local policies.
+func (c *LocalPermissionClient) Apply(ev PolicyEvent) error {
+	if ev.Type == PolicyEventReload {
+		return c.Reload()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rule := range ev.Rules {
+		if len(rule) < 2 {
+			return fmt.Errorf("malformed policy rule %v", rule)
+		}
+		ptype, values := rule[0], rule[1:]
+		sec := ptype[:1]
+		var err error
+		switch ev.Type {
+		case PolicyEventAdd:
+			_, err = c.enforcer.SelfAddPolicy(sec, ptype, values)
+		case PolicyEventRemove:
+			_, err = c.enforcer.SelfRemovePolicy(sec, ptype, values)
+		default:
+			err = fmt.Errorf("unknown policy event %q", ev.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("apply policy %s: %w", ev.Type, err)
+		}
+	}
+	return nil
+}
+
+// applyOrReload applies ev, reloading every policy when that fails so the
+// local policies don't drift from the service's.
+func (c *LocalPermissionClient) applyOrReload(ev PolicyEvent) {
+	if err := c.Apply(ev); err != nil {
+		slog.Warn("Policy change not applied, reloading", "error", err)
+		c.reloadOrWarn()
+	}
+}
+
+// useRemote reports whether checks should go to the permission service.
+func (c *LocalPermissionClient) useRemote() bool {
+	if !c.fallback {
+		return false
+	}
+	c.mu.RLock()
+	loaded := c.loaded
+	c.mu.RUnlock()
+	if !loaded {
+		c.retryLoad()
+	}
+	return !loaded || !c.synced.Load()
+}
+
+// retryLoad tries again in the background to load policies that failed to
+// load, at most once per reloadRetryInterval.
+func (c *LocalPermissionClient) retryLoad() {
+	last := c.lastLoad.Load()
+	if time.Since(time.Unix(0, last)) < reloadRetryInterval {
+		return
+	}
+	if c.lastLoad.CompareAndSwap(last, time.Now().UnixNano()) {
+		go c.reloadOrWarn()
+	}
+}
+
+func (c *LocalPermissionClient) CheckPermission(ctx context.Context, userID, resource, action string) (bool, error) {
+	return c.Check(ctx, CheckRequest{UserID: userID, Resource: resource, Action: action})
+}
+
+func (c *LocalPermissionClient) CheckPermissionInDomain(ctx context.Context, userID, domain, resource, action string) (bool, error) {
+	return c.Check(ctx, CheckRequest{UserID: userID, Domain: domain, Resource: resource, Action: action})
+}
+
+func (c *LocalPermissionClient) Check(ctx context.Context, req CheckRequest) (bool, error) {
+	if c.useRemote() {
+		return c.remote.Check(ctx, req)
+	}
+	c.mu.RLock()
+	allowed, err := c.enforce(req)
+	c.mu.RUnlock()
+	if err != nil && c.fallback {
+		slog.Warn("Local check failed, asking the permission service", "error", err)
+		return c.remote.Check(ctx, req)
+	}
+	return allowed, err
+}
+
+func (c *LocalPermissionClient) BatchCheckPermission(ctx context.Context, checks []CheckRequest) ([]bool, error) {
+	if c.useRemote() {
+		return c.remote.BatchCheckPermission(ctx, checks)
+	}
+	results := make([]bool, len(checks))
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for i, check := range checks {
+		allowed, err := c.enforce(check)
+		if err != nil {
+			if c.fallback {
+				slog.Warn("Local batch check failed, asking the permission service", "error", err)
+				return c.remote.BatchCheckPermission(ctx, checks)
+			}
+			return nil, err
+		}
+		results[i] = allowed
+	}
+	return results, nil
+}
+
+// enforce decides like the permission server: RBAC first, then the ABAC
+// rules. The caller holds the read lock.
+func (c *LocalPermissionClient) enforce(req CheckRequest) (bool, error) {
+	if !c.loaded {
+		return false, &Error{Op: "local check", Code: codes.Unavailable, Message: "policies not loaded"}
+	}
+	domain := req.Domain
+	if domain == "" {
+		domain = GlobalDomain
+	}
+	allowed, err := c.enforcer.Enforce(req.UserID, domain, req.Resource, req.Action)
+	if err != nil {
+		return false, &Error{Op: "local check", Code: codes.Internal, Message: err.Error()}
+	}
+	if allowed {
+		return true, nil
+	}
+	if rules, ok := c.enforcer.GetModel()["p"]["p2"]; !ok || len(rules.Policy) == 0 {
+		return false, nil
+	}
+	allowed, err = c.enforcer.Enforce(abacContext, req.UserID, domain, req.Resource, req.Action,
+		req.Attributes.ConditionInput(req.UserID, c.now()))
+	if err != nil {
+		slog.Warn("ABAC condition not evaluated", "sub", req.UserID, "obj", req.Resource, "act", req.Action, "error", err)
+		return false, nil
+	}
+	return allowed, nil
+}
+
+func (c *LocalPermissionClient) Close() error {
+	c.stop()
+	return c.PermissionClient.Close()
+}
+
+type policySnapshotter interface {
+	PolicySnapshot(ctx context.Context) ([][]string, error)
+}
+
+// snapshotLoadTimeout bounds a snapshot fetch during a reload.
+const snapshotLoadTimeout = 10 * time.Second
+
+// snapshotAdapter loads policies from the permission service. It is read
+// only: policies are changed through the service.
+type snapshotAdapter struct {
+	source policySnapshotter
+}
+
+func (a *snapshotAdapter) LoadPolicy(m model.Model) error {
+	ctx, cancel := context.WithTimeout(context.Background(), snapshotLoadTimeout)
+	defer cancel()
+	rules, err := a.source.PolicySnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if err := persist.LoadPolicyArray(rule, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *snapshotAdapter) SavePolicy(model.Model) error { return ErrReadOnly }
+
+func (a *snapshotAdapter) AddPolicy(string, string, []string) error { return ErrReadOnly }
+
+func (a *snapshotAdapter) RemovePolicy(string, string, []string) error { return ErrReadOnly }
+
+func (a *snapshotAdapter) RemoveFilteredPolicy(string, string, int, ...string) error {
+	return ErrReadOnly
+}
+
+// readOnlyClient stands in for the remote of a purely local client.
+type readOnlyClient struct{}
+
+func (readOnlyClient) CheckPermission(context.Context, string, string, string) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyClient) AddPolicy(context.Context, string, string, string) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyClient) RemovePolicy(context.Context, string, string, string) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyClient) AddPolicyIfNotExists(context.Context, string, string, string) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyClient) AssignRole(context.Context, string, string) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyClient) UnassignRole(context.Context, string, string) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyClient) GetRoleMembers(context.Context, string) ([]string, error) {
+	return nil, ErrReadOnly
+}
+
+func (readOnlyClient) CheckPermissionInDomain(context.Context, string, string, string, string) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyClient) AddPolicyInDomain(context.Context, string, string, string, string) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyClient) AssignRoleInDomain(context.Context, string, string, string) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyClient) UnassignRoleInDomain(context.Context, string, string, string) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyClient) Check(context.Context, CheckRequest) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyClient) BatchCheckPermission(context.Context, []CheckRequest) ([]bool, error) {
+	return nil, ErrReadOnly
+}
+
+func (readOnlyClient) ExplainPermission(context.Context, CheckRequest) (*Explanation, error) {
+	return nil, ErrReadOnly
+}
+
+func (readOnlyClient) ListPermissionsForSubject(context.Context, string, string, int32, string) ([]Permission, string, error) {
+	return nil, "", ErrReadOnly
+}
+
+func (readOnlyClient) ListSubjectsForResource(context.Context, string, string, string, int32, string) ([]string, string, error) {
+	return nil, "", ErrReadOnly
+}
+
+func (readOnlyClient) GetImplicitRolesForUser(context.Context, string, string) ([]string, error) {
+	return nil, ErrReadOnly
+}
+
+func (readOnlyClient) AddConditionalPolicy(context.Context, string, string, string, string, string) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyClient) RemoveConditionalPolicy(context.Context, string, string, string, string, string) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyClient) RemovePolicyInDomain(context.Context, string, string, string, string) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (readOnlyClient) ListPolicies(context.Context, PolicyFilter, int32, string) ([]Permission, string, error) {
+	return nil, "", ErrReadOnly
+}
+
+func (readOnlyClient) ReplacePolicies(context.Context, PolicyFilter, []Permission) (int, int, error) {
+	return 0, 0, ErrReadOnly
+}
+
+func (readOnlyClient) ListRoles(context.Context, string) ([]string, error) {
+	return nil, ErrReadOnly
+}
+
+func (readOnlyClient) ListPolicyChanges(context.Context, PolicyChangeFilter, int32, string) ([]PolicyChange, string, error) {
+	return nil, "", ErrReadOnly
+}
+
+func (readOnlyClient) RevertPolicies(context.Context, uint64, bool) (*PolicyPlan, error) {
+	return nil, ErrReadOnly
+}
+
+func (readOnlyClient) Close() error { return nil }
+
+var _ PermissionClient = (*LocalPermissionClient)(nil)
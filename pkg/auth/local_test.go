@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRemote struct {
+	readOnlyClient
+	rules  [][]string
+	err    error
+	checks int
+}
+
+func (f *fakeRemote) PolicySnapshot(context.Context) ([][]string, error) {
+	return f.rules, f.err
+}
+
+func (f *fakeRemote) Check(context.Context, CheckRequest) (bool, error) {
+	f.checks++
+	return true, nil
+}
+
+func TestLocalPermissionClient(t *testing.T) {
+	ctx := context.Background()
+	remote := &fakeRemote{rules: [][]string{
+		{"p", "self", "*", "users/:id", "write"},
+		{"p", "member", "*", "users", "read"},
+		{"p", "org_admin", "org1", "orgs/org1", "write"},
+		{"p2", "member", "*", "stream", "read", "r2.attrs.env.hour < 18"},
+		{"g", "alice", "member", "*"},
+		{"g", "alice", "org_admin", "org1"},
+	}}
+	c, err := NewLocalPermissionClient(LocalOptions{Remote: remote})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name    string
+		req     CheckRequest
+		hour    int
+		allowed bool
+	}{
+		{"role policy", CheckRequest{UserID: "alice", Resource: "users", Action: "read"}, 10, true},
+		{"owner rule", CheckRequest{UserID: "bob", Domain: GlobalDomain, Resource: "users/bob", Action: "write"}, 10, true},
+		{"not the owner", CheckRequest{UserID: "bob", Domain: GlobalDomain, Resource: "users/alice", Action: "write"}, 10, false},
+		{"org role in its org", CheckRequest{UserID: "alice", Domain: "org1", Resource: "orgs/org1", Action: "write"}, 10, true},
+		{"org role in another org", CheckRequest{UserID: "alice", Domain: "org2", Resource: "orgs/org1", Action: "write"}, 10, false},
+		{"condition holds", CheckRequest{UserID: "alice", Resource: "stream", Action: "read"}, 10, true},
+		{"condition fails", CheckRequest{UserID: "alice", Resource: "stream", Action: "read"}, 20, false},
+		{"hour can't be spoofed", CheckRequest{UserID: "alice", Resource: "stream", Action: "read",
+			Attributes: Attributes{Environment: map[string]string{"hour": "10"}}}, 20, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c.now = func() time.Time { return time.Date(2026, 1, 5, tc.hour, 0, 0, 0, time.UTC) }
+			allowed, err := c.Check(ctx, tc.req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.allowed, allowed)
+		})
+	}
+	assert.Zero(t, remote.checks, "local checks must not reach the service")
+
+	_, err = c.AddPolicy(ctx, "bob", "users", "read")
+	assert.ErrorIs(t, err, ErrReadOnly)
+
+	remote.rules = remote.rules[:1]
+	require.NoError(t, c.Reload())
+	allowed, err := c.Check(ctx, CheckRequest{UserID: "alice", Resource: "users", Action: "read"})
+	require.NoError(t, err)
+	assert.False(t, allowed, "reload must drop removed rules")
+}
+
+func TestLocalPermissionClient_Fallback(t *testing.T) {
+	ctx := context.Background()
+	remote := &fakeRemote{err: errors.New("snapshot unavailable")}
+
+	_, err := NewLocalPermissionClient(LocalOptions{Remote: remote})
+	require.Error(t, err, "local mode needs its policies")
+
+	c, err := NewLocalPermissionClient(LocalOptions{Remote: remote, Fallback: true})
+	require.NoError(t, err)
+	allowed, err := c.Check(ctx, CheckRequest{UserID: "alice", Resource: "users", Action: "read"})
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, 1, remote.checks)
+}
+
+func TestLocalPermissionClient_Apply(t *testing.T) {
+	ctx := context.Background()
+	remote := &fakeRemote{rules: [][]string{{"p", "member", "*", "users", "read"}}}
+	c, err := NewLocalPermissionClient(LocalOptions{Remote: remote})
+	require.NoError(t, err)
+	check := CheckRequest{UserID: "alice", Resource: "users", Action: "read"}
+
+	require.NoError(t, c.Apply(PolicyEvent{Type: PolicyEventAdd, Rules: [][]string{{"g", "alice", "member", "*"}}}))
+	allowed, err := c.Check(ctx, check)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	require.NoError(t, c.Apply(PolicyEvent{Type: PolicyEventRemove, Rules: [][]string{{"p", "member", "*", "users", "read"}}}))
+	allowed, err = c.Check(ctx, check)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	require.NoError(t, c.Apply(PolicyEvent{Type: PolicyEventReload}))
+	allowed, err = c.Check(ctx, check)
+	require.NoError(t, err)
+	assert.False(t, allowed, "a reload drops changes the snapshot doesn't have")
+
+	assert.Error(t, c.Apply(PolicyEvent{Type: "rename", Rules: [][]string{{"p", "x"}}}))
+}
@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	auth "github.com/mrhumster/web-server-gin/pkg/auth"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,6 +41,21 @@ func (m *MockPermissionClient) EXPECT() *MockPermissionClientMockRecorder {
 	return m.recorder
 }
 
+// AddConditionalPolicy mocks base method.
+func (m *MockPermissionClient) AddConditionalPolicy(ctx context.Context, subject, domain, resource, action, condition string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddConditionalPolicy", ctx, subject, domain, resource, action, condition)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddConditionalPolicy indicates an expected call of AddConditionalPolicy.
+func (mr *MockPermissionClientMockRecorder) AddConditionalPolicy(ctx, subject, domain, resource, action, condition any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddConditionalPolicy", reflect.TypeOf((*MockPermissionClient)(nil).AddConditionalPolicy), ctx, subject, domain, resource, action, condition)
+}
+
 // AddPolicy mocks base method.
 func (m *MockPermissionClient) AddPolicy(ctx context.Context, userID, resource, action string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +86,81 @@ func (mr *MockPermissionClientMockRecorder) AddPolicyIfNotExists(ctx, userID, re
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPolicyIfNotExists", reflect.TypeOf((*MockPermissionClient)(nil).AddPolicyIfNotExists), ctx, userID, resource, action)
 }
 
+// AddPolicyInDomain mocks base method.
+func (m *MockPermissionClient) AddPolicyInDomain(ctx context.Context, userID, domain, resource, action string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPolicyInDomain", ctx, userID, domain, resource, action)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddPolicyInDomain indicates an expected call of AddPolicyInDomain.
+func (mr *MockPermissionClientMockRecorder) AddPolicyInDomain(ctx, userID, domain, resource, action any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPolicyInDomain", reflect.TypeOf((*MockPermissionClient)(nil).AddPolicyInDomain), ctx, userID, domain, resource, action)
+}
+
+// AssignRole mocks base method.
+func (m *MockPermissionClient) AssignRole(ctx context.Context, userID, role string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRole", ctx, userID, role)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignRole indicates an expected call of AssignRole.
+func (mr *MockPermissionClientMockRecorder) AssignRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRole", reflect.TypeOf((*MockPermissionClient)(nil).AssignRole), ctx, userID, role)
+}
+
+// AssignRoleInDomain mocks base method.
+func (m *MockPermissionClient) AssignRoleInDomain(ctx context.Context, userID, role, domain string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRoleInDomain", ctx, userID, role, domain)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignRoleInDomain indicates an expected call of AssignRoleInDomain.
+func (mr *MockPermissionClientMockRecorder) AssignRoleInDomain(ctx, userID, role, domain any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRoleInDomain", reflect.TypeOf((*MockPermissionClient)(nil).AssignRoleInDomain), ctx, userID, role, domain)
+}
+
+// BatchCheckPermission mocks base method.
+func (m *MockPermissionClient) BatchCheckPermission(ctx context.Context, checks []auth.CheckRequest) ([]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchCheckPermission", ctx, checks)
+	ret0, _ := ret[0].([]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchCheckPermission indicates an expected call of BatchCheckPermission.
+func (mr *MockPermissionClientMockRecorder) BatchCheckPermission(ctx, checks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCheckPermission", reflect.TypeOf((*MockPermissionClient)(nil).BatchCheckPermission), ctx, checks)
+}
+
+// Check mocks base method.
+func (m *MockPermissionClient) Check(ctx context.Context, req auth.CheckRequest) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, req)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockPermissionClientMockRecorder) Check(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockPermissionClient)(nil).Check), ctx, req)
+}
+
 // CheckPermission mocks base method.
 func (m *MockPermissionClient) CheckPermission(ctx context.Context, userID, resource, action string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +176,21 @@ func (mr *MockPermissionClientMockRecorder) CheckPermission(ctx, userID, resourc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPermission", reflect.TypeOf((*MockPermissionClient)(nil).CheckPermission), ctx, userID, resource, action)
 }
 
+// CheckPermissionInDomain mocks base method.
+func (m *MockPermissionClient) CheckPermissionInDomain(ctx context.Context, userID, domain, resource, action string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckPermissionInDomain", ctx, userID, domain, resource, action)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPermissionInDomain indicates an expected call of CheckPermissionInDomain.
+func (mr *MockPermissionClientMockRecorder) CheckPermissionInDomain(ctx, userID, domain, resource, action any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPermissionInDomain", reflect.TypeOf((*MockPermissionClient)(nil).CheckPermissionInDomain), ctx, userID, domain, resource, action)
+}
+
 // Close mocks base method.
 func (m *MockPermissionClient) Close() error {
 	m.ctrl.T.Helper()
@@ -99,6 +205,145 @@ func (mr *MockPermissionClientMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockPermissionClient)(nil).Close))
 }
 
+// ExplainPermission mocks base method.
+func (m *MockPermissionClient) ExplainPermission(ctx context.Context, req auth.CheckRequest) (*auth.Explanation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainPermission", ctx, req)
+	ret0, _ := ret[0].(*auth.Explanation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExplainPermission indicates an expected call of ExplainPermission.
+func (mr *MockPermissionClientMockRecorder) ExplainPermission(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainPermission", reflect.TypeOf((*MockPermissionClient)(nil).ExplainPermission), ctx, req)
+}
+
+// GetImplicitRolesForUser mocks base method.
+func (m *MockPermissionClient) GetImplicitRolesForUser(ctx context.Context, userID, domain string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImplicitRolesForUser", ctx, userID, domain)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImplicitRolesForUser indicates an expected call of GetImplicitRolesForUser.
+func (mr *MockPermissionClientMockRecorder) GetImplicitRolesForUser(ctx, userID, domain any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImplicitRolesForUser", reflect.TypeOf((*MockPermissionClient)(nil).GetImplicitRolesForUser), ctx, userID, domain)
+}
+
+// GetRoleMembers mocks base method.
+func (m *MockPermissionClient) GetRoleMembers(ctx context.Context, role string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoleMembers", ctx, role)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoleMembers indicates an expected call of GetRoleMembers.
+func (mr *MockPermissionClientMockRecorder) GetRoleMembers(ctx, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleMembers", reflect.TypeOf((*MockPermissionClient)(nil).GetRoleMembers), ctx, role)
+}
+
+// ListPermissionsForSubject mocks base method.
+func (m *MockPermissionClient) ListPermissionsForSubject(ctx context.Context, subject, domain string, pageSize int32, pageToken string) ([]auth.Permission, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPermissionsForSubject", ctx, subject, domain, pageSize, pageToken)
+	ret0, _ := ret[0].([]auth.Permission)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPermissionsForSubject indicates an expected call of ListPermissionsForSubject.
+func (mr *MockPermissionClientMockRecorder) ListPermissionsForSubject(ctx, subject, domain, pageSize, pageToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPermissionsForSubject", reflect.TypeOf((*MockPermissionClient)(nil).ListPermissionsForSubject), ctx, subject, domain, pageSize, pageToken)
+}
+
+// ListPolicies mocks base method.
+func (m *MockPermissionClient) ListPolicies(ctx context.Context, filter auth.PolicyFilter, pageSize int32, pageToken string) ([]auth.Permission, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPolicies", ctx, filter, pageSize, pageToken)
+	ret0, _ := ret[0].([]auth.Permission)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPolicies indicates an expected call of ListPolicies.
+func (mr *MockPermissionClientMockRecorder) ListPolicies(ctx, filter, pageSize, pageToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPolicies", reflect.TypeOf((*MockPermissionClient)(nil).ListPolicies), ctx, filter, pageSize, pageToken)
+}
+
+// ListPolicyChanges mocks base method.
+func (m *MockPermissionClient) ListPolicyChanges(ctx context.Context, filter auth.PolicyChangeFilter, pageSize int32, pageToken string) ([]auth.PolicyChange, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPolicyChanges", ctx, filter, pageSize, pageToken)
+	ret0, _ := ret[0].([]auth.PolicyChange)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPolicyChanges indicates an expected call of ListPolicyChanges.
+func (mr *MockPermissionClientMockRecorder) ListPolicyChanges(ctx, filter, pageSize, pageToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPolicyChanges", reflect.TypeOf((*MockPermissionClient)(nil).ListPolicyChanges), ctx, filter, pageSize, pageToken)
+}
+
+// ListRoles mocks base method.
+func (m *MockPermissionClient) ListRoles(ctx context.Context, domain string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoles", ctx, domain)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoles indicates an expected call of ListRoles.
+func (mr *MockPermissionClientMockRecorder) ListRoles(ctx, domain any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoles", reflect.TypeOf((*MockPermissionClient)(nil).ListRoles), ctx, domain)
+}
+
+// ListSubjectsForResource mocks base method.
+func (m *MockPermissionClient) ListSubjectsForResource(ctx context.Context, resource, action, domain string, pageSize int32, pageToken string) ([]string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubjectsForResource", ctx, resource, action, domain, pageSize, pageToken)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListSubjectsForResource indicates an expected call of ListSubjectsForResource.
+func (mr *MockPermissionClientMockRecorder) ListSubjectsForResource(ctx, resource, action, domain, pageSize, pageToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubjectsForResource", reflect.TypeOf((*MockPermissionClient)(nil).ListSubjectsForResource), ctx, resource, action, domain, pageSize, pageToken)
+}
+
+// RemoveConditionalPolicy mocks base method.
+func (m *MockPermissionClient) RemoveConditionalPolicy(ctx context.Context, subject, domain, resource, action, condition string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveConditionalPolicy", ctx, subject, domain, resource, action, condition)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveConditionalPolicy indicates an expected call of RemoveConditionalPolicy.
+func (mr *MockPermissionClientMockRecorder) RemoveConditionalPolicy(ctx, subject, domain, resource, action, condition any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveConditionalPolicy", reflect.TypeOf((*MockPermissionClient)(nil).RemoveConditionalPolicy), ctx, subject, domain, resource, action, condition)
+}
+
 // RemovePolicy mocks base method.
 func (m *MockPermissionClient) RemovePolicy(ctx context.Context, userID, resource, action string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -113,3 +358,79 @@ func (mr *MockPermissionClientMockRecorder) RemovePolicy(ctx, userID, resource,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePolicy", reflect.TypeOf((*MockPermissionClient)(nil).RemovePolicy), ctx, userID, resource, action)
 }
+
+// RemovePolicyInDomain mocks base method.
+func (m *MockPermissionClient) RemovePolicyInDomain(ctx context.Context, subject, domain, resource, action string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemovePolicyInDomain", ctx, subject, domain, resource, action)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemovePolicyInDomain indicates an expected call of RemovePolicyInDomain.
+func (mr *MockPermissionClientMockRecorder) RemovePolicyInDomain(ctx, subject, domain, resource, action any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePolicyInDomain", reflect.TypeOf((*MockPermissionClient)(nil).RemovePolicyInDomain), ctx, subject, domain, resource, action)
+}
+
+// ReplacePolicies mocks base method.
+func (m *MockPermissionClient) ReplacePolicies(ctx context.Context, filter auth.PolicyFilter, policies []auth.Permission) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplacePolicies", ctx, filter, policies)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReplacePolicies indicates an expected call of ReplacePolicies.
+func (mr *MockPermissionClientMockRecorder) ReplacePolicies(ctx, filter, policies any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplacePolicies", reflect.TypeOf((*MockPermissionClient)(nil).ReplacePolicies), ctx, filter, policies)
+}
+
+// RevertPolicies mocks base method.
+func (m *MockPermissionClient) RevertPolicies(ctx context.Context, revision uint64, dryRun bool) (*auth.PolicyPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertPolicies", ctx, revision, dryRun)
+	ret0, _ := ret[0].(*auth.PolicyPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertPolicies indicates an expected call of RevertPolicies.
+func (mr *MockPermissionClientMockRecorder) RevertPolicies(ctx, revision, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertPolicies", reflect.TypeOf((*MockPermissionClient)(nil).RevertPolicies), ctx, revision, dryRun)
+}
+
+// UnassignRole mocks base method.
+func (m *MockPermissionClient) UnassignRole(ctx context.Context, userID, role string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignRole", ctx, userID, role)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnassignRole indicates an expected call of UnassignRole.
+func (mr *MockPermissionClientMockRecorder) UnassignRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignRole", reflect.TypeOf((*MockPermissionClient)(nil).UnassignRole), ctx, userID, role)
+}
+
+// UnassignRoleInDomain mocks base method.
+func (m *MockPermissionClient) UnassignRoleInDomain(ctx context.Context, userID, role, domain string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignRoleInDomain", ctx, userID, role, domain)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnassignRoleInDomain indicates an expected call of UnassignRoleInDomain.
+func (mr *MockPermissionClientMockRecorder) UnassignRoleInDomain(ctx, userID, role, domain any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignRoleInDomain", reflect.TypeOf((*MockPermissionClient)(nil).UnassignRoleInDomain), ctx, userID, role, domain)
+}
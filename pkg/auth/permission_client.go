@@ -2,12 +2,108 @@
 
 package auth
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// GlobalDomain is the tenant used for platform-wide checks. Roles and
+// policies granted in it apply in every organization.
+const GlobalDomain = "*"
+
+// Attributes are the ABAC inputs of a check. Policy conditions read them as
+// r2.attrs.subject.<key>, r2.attrs.resource.<key> and r2.attrs.env.<key>;
+// the server fills env.hour and env.weekday when they are not set.
+type Attributes struct {
+	Subject     map[string]string
+	Resource    map[string]string
+	Environment map[string]string
+}
+
+// Permission is a policy line returned by introspection. Condition is set for
+// ABAC rules only.
+type Permission struct {
+	Subject   string
+	Domain    string
+	Resource  string
+	Action    string
+	Condition string
+}
+
+// Explanation is the outcome of ExplainPermission: the decision, the policy
+// lines that produced it and the roles the subject holds in the domain.
+type Explanation struct {
+	Allowed         bool
+	MatchedPolicies []Permission
+	Roles           []string
+	Reason          string
+}
+
+// PolicyFilter selects stored policies by exact field values. Empty fields
+// match any value.
+type PolicyFilter struct {
+	Subject  string
+	Domain   string
+	Resource string
+	Action   string
+}
+
+// PolicyChange is a recorded change of the policy set. Before is the rule
+// removed and After the rule added, in the PolicySnapshot layout; the other
+// is nil. Revisions number the changes in the order they took effect.
+type PolicyChange struct {
+	Revision  uint64
+	ChangedAt time.Time
+	Service   string
+	Actor     string
+	Type      string
+	Before    []string
+	After     []string
+}
+
+// PolicyChangeFilter selects policy changes. Zero fields match everything.
+type PolicyChangeFilter struct {
+	Actor   string
+	Service string
+	Subject string
+	Domain  string
+	Since   time.Time
+	Until   time.Time
+}
+
+type CheckRequest struct {
+	UserID     string
+	Domain     string
+	Resource   string
+	Action     string
+	Attributes Attributes
+}
 
 type PermissionClient interface {
 	CheckPermission(ctx context.Context, userID, resource, action string) (bool, error)
 	AddPolicy(ctx context.Context, userID, resource, action string) (bool, error)
 	RemovePolicy(ctx context.Context, userID, resource, action string) (bool, error)
 	AddPolicyIfNotExists(ctx context.Context, userID, resource, action string) (bool, error)
+	AssignRole(ctx context.Context, userID, role string) (bool, error)
+	UnassignRole(ctx context.Context, userID, role string) (bool, error)
+	GetRoleMembers(ctx context.Context, role string) ([]string, error)
+	CheckPermissionInDomain(ctx context.Context, userID, domain, resource, action string) (bool, error)
+	AddPolicyInDomain(ctx context.Context, userID, domain, resource, action string) (bool, error)
+	AssignRoleInDomain(ctx context.Context, userID, role, domain string) (bool, error)
+	UnassignRoleInDomain(ctx context.Context, userID, role, domain string) (bool, error)
+	Check(ctx context.Context, req CheckRequest) (bool, error)
+	BatchCheckPermission(ctx context.Context, checks []CheckRequest) ([]bool, error)
+	ExplainPermission(ctx context.Context, req CheckRequest) (*Explanation, error)
+	ListPermissionsForSubject(ctx context.Context, subject, domain string, pageSize int32, pageToken string) ([]Permission, string, error)
+	ListSubjectsForResource(ctx context.Context, resource, action, domain string, pageSize int32, pageToken string) ([]string, string, error)
+	GetImplicitRolesForUser(ctx context.Context, userID, domain string) ([]string, error)
+	AddConditionalPolicy(ctx context.Context, subject, domain, resource, action, condition string) (bool, error)
+	RemoveConditionalPolicy(ctx context.Context, subject, domain, resource, action, condition string) (bool, error)
+	RemovePolicyInDomain(ctx context.Context, subject, domain, resource, action string) (bool, error)
+	ListPolicies(ctx context.Context, filter PolicyFilter, pageSize int32, pageToken string) ([]Permission, string, error)
+	ReplacePolicies(ctx context.Context, filter PolicyFilter, policies []Permission) (added, removed int, err error)
+	ListRoles(ctx context.Context, domain string) ([]string, error)
+	ListPolicyChanges(ctx context.Context, filter PolicyChangeFilter, pageSize int32, pageToken string) ([]PolicyChange, string, error)
+	RevertPolicies(ctx context.Context, revision uint64, dryRun bool) (*PolicyPlan, error)
 	Close() error
 }
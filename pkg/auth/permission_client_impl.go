@@ -3,10 +3,10 @@ package auth
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/mrhumster/web-server-gin/gen/go/permission"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 type PermissionGRPCClient struct {
@@ -14,10 +14,13 @@ type PermissionGRPCClient struct {
 	service permission.PermissionServiceClient
 }
 
-func NewPermissionGRPCClient(url string) (*PermissionGRPCClient, error) {
-	conn, err := grpc.NewClient(url, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// NewPermissionGRPCClient connects to the permission service. Calls without a
+// deadline get DefaultCallTimeout, reads are retried and a circuit breaker
+// fails calls fast while the service is down; see the options to change that.
+func NewPermissionGRPCClient(url string, opts ...ClientOption) (*PermissionGRPCClient, error) {
+	conn, err := Dial(url, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("⚠️ failed to connect to auth service: %w", err)
+		return nil, err
 	}
 
 	return &PermissionGRPCClient{
@@ -37,7 +40,7 @@ func (c *PermissionGRPCClient) CheckPermission(ctx context.Context, userID, reso
 		Action:   action,
 	})
 	if err != nil {
-		return false, fmt.Errorf("permission check failed: %w", err)
+		return false, rpcError("permission check", err)
 	}
 	return resp.Allowed, nil
 }
@@ -49,7 +52,7 @@ func (c *PermissionGRPCClient) AddPolicy(ctx context.Context, userID, resource,
 		Permission: action,
 	})
 	if err != nil {
-		return false, fmt.Errorf("add policy failed: %w", err)
+		return false, rpcError("add policy", err)
 	}
 	return resp.Added, nil
 }
@@ -61,7 +64,7 @@ func (c *PermissionGRPCClient) RemovePolicy(ctx context.Context, userID, resourc
 		Permission: action,
 	})
 	if err != nil {
-		return false, fmt.Errorf("remove policy failed: %w", err)
+		return false, rpcError("remove policy", err)
 	}
 	return resp.Removed, nil
 }
@@ -73,7 +76,445 @@ func (c *PermissionGRPCClient) AddPolicyIfNotExists(ctx context.Context, userID,
 		Permission: action,
 	})
 	if err != nil {
-		return false, fmt.Errorf("add policy failed: %w", err)
+		return false, rpcError("add policy", err)
 	}
 	return resp.Exists, nil
 }
+
+func (c *PermissionGRPCClient) AssignRole(ctx context.Context, userID, role string) (bool, error) {
+	resp, err := c.service.AssignRole(ctx, &permission.AssignRoleRequest{
+		UserId: userID,
+		Role:   role,
+	})
+	if err != nil {
+		return false, rpcError("assign role", err)
+	}
+	return resp.Assigned, nil
+}
+
+func (c *PermissionGRPCClient) UnassignRole(ctx context.Context, userID, role string) (bool, error) {
+	resp, err := c.service.UnassignRole(ctx, &permission.UnassignRoleRequest{
+		UserId: userID,
+		Role:   role,
+	})
+	if err != nil {
+		return false, rpcError("unassign role", err)
+	}
+	return resp.Unassigned, nil
+}
+
+func (c *PermissionGRPCClient) GetRoleMembers(ctx context.Context, role string) ([]string, error) {
+	resp, err := c.service.ListRoleMembers(ctx, &permission.ListRoleMembersRequest{
+		Role: role,
+	})
+	if err != nil {
+		return nil, rpcError("list role members", err)
+	}
+	return resp.UserIds, nil
+}
+
+func (c *PermissionGRPCClient) CheckPermissionInDomain(ctx context.Context, userID, domain, resource, action string) (bool, error) {
+	resp, err := c.service.CheckPermission(ctx, &permission.CheckPermissionRequest{
+		UserId:   userID,
+		Domain:   domain,
+		Resource: resource,
+		Action:   action,
+	})
+	if err != nil {
+		return false, rpcError("permission check", err)
+	}
+	return resp.Allowed, nil
+}
+
+func (c *PermissionGRPCClient) AddPolicyInDomain(ctx context.Context, userID, domain, resource, action string) (bool, error) {
+	resp, err := c.service.AddPolicy(ctx, &permission.AddPolicyRequest{
+		Policy:     userID,
+		Domain:     domain,
+		Resorce:    resource,
+		Permission: action,
+	})
+	if err != nil {
+		return false, rpcError("add policy", err)
+	}
+	return resp.Added, nil
+}
+
+func (c *PermissionGRPCClient) AssignRoleInDomain(ctx context.Context, userID, role, domain string) (bool, error) {
+	resp, err := c.service.AssignRole(ctx, &permission.AssignRoleRequest{
+		UserId: userID,
+		Role:   role,
+		Domain: domain,
+	})
+	if err != nil {
+		return false, rpcError("assign role", err)
+	}
+	return resp.Assigned, nil
+}
+
+func (c *PermissionGRPCClient) UnassignRoleInDomain(ctx context.Context, userID, role, domain string) (bool, error) {
+	resp, err := c.service.UnassignRole(ctx, &permission.UnassignRoleRequest{
+		UserId: userID,
+		Role:   role,
+		Domain: domain,
+	})
+	if err != nil {
+		return false, rpcError("unassign role", err)
+	}
+	return resp.Unassigned, nil
+}
+
+func (c *PermissionGRPCClient) Check(ctx context.Context, req CheckRequest) (bool, error) {
+	resp, err := c.service.CheckPermission(ctx, &permission.CheckPermissionRequest{
+		UserId:             req.UserID,
+		Domain:             req.Domain,
+		Resource:           req.Resource,
+		Action:             req.Action,
+		SubjectAttributes:  req.Attributes.Subject,
+		ResourceAttributes: req.Attributes.Resource,
+		Environment:        req.Attributes.Environment,
+	})
+	if err != nil {
+		return false, rpcError("permission check", err)
+	}
+	return resp.Allowed, nil
+}
+
+func (c *PermissionGRPCClient) AddConditionalPolicy(ctx context.Context, subject, domain, resource, action, condition string) (bool, error) {
+	resp, err := c.service.AddPolicy(ctx, &permission.AddPolicyRequest{
+		Policy:     subject,
+		Domain:     domain,
+		Resorce:    resource,
+		Permission: action,
+		Condition:  condition,
+	})
+	if err != nil {
+		return false, rpcError("add policy", err)
+	}
+	return resp.Added, nil
+}
+
+func (c *PermissionGRPCClient) RemoveConditionalPolicy(ctx context.Context, subject, domain, resource, action, condition string) (bool, error) {
+	resp, err := c.service.RemovePolicy(ctx, &permission.RemovePolicyRequest{
+		Policy:     subject,
+		Domain:     domain,
+		Resource:   resource,
+		Permission: action,
+		Condition:  condition,
+	})
+	if err != nil {
+		return false, rpcError("remove policy", err)
+	}
+	return resp.Removed, nil
+}
+
+func (c *PermissionGRPCClient) RemovePolicyInDomain(ctx context.Context, subject, domain, resource, action string) (bool, error) {
+	resp, err := c.service.RemovePolicy(ctx, &permission.RemovePolicyRequest{
+		Policy:     subject,
+		Domain:     domain,
+		Resource:   resource,
+		Permission: action,
+	})
+	if err != nil {
+		return false, rpcError("remove policy", err)
+	}
+	return resp.Removed, nil
+}
+
+// BatchCheckPermission checks all requests in one round trip and returns the
+// decisions in request order.
+func (c *PermissionGRPCClient) BatchCheckPermission(ctx context.Context, checks []CheckRequest) ([]bool, error) {
+	if len(checks) == 0 {
+		return []bool{}, nil
+	}
+	req := &permission.BatchCheckPermissionRequest{
+		Checks: make([]*permission.CheckPermissionRequest, len(checks)),
+	}
+	for i, check := range checks {
+		req.Checks[i] = &permission.CheckPermissionRequest{
+			UserId:             check.UserID,
+			Domain:             check.Domain,
+			Resource:           check.Resource,
+			Action:             check.Action,
+			SubjectAttributes:  check.Attributes.Subject,
+			ResourceAttributes: check.Attributes.Resource,
+			Environment:        check.Attributes.Environment,
+		}
+	}
+	resp, err := c.service.BatchCheckPermission(ctx, req)
+	if err != nil {
+		return nil, rpcError("batch permission check", err)
+	}
+	if resp.Error != "" {
+		return nil, responseError("batch permission check", resp.Error)
+	}
+	if len(resp.Allowed) != len(checks) {
+		return nil, responseError("batch permission check", fmt.Sprintf("got %d decisions for %d checks", len(resp.Allowed), len(checks)))
+	}
+	return resp.Allowed, nil
+}
+
+// ExplainPermission evaluates the check like Check and also reports which
+// policy decided it.
+func (c *PermissionGRPCClient) ExplainPermission(ctx context.Context, req CheckRequest) (*Explanation, error) {
+	resp, err := c.service.ExplainPermission(ctx, &permission.CheckPermissionRequest{
+		UserId:             req.UserID,
+		Domain:             req.Domain,
+		Resource:           req.Resource,
+		Action:             req.Action,
+		SubjectAttributes:  req.Attributes.Subject,
+		ResourceAttributes: req.Attributes.Resource,
+		Environment:        req.Attributes.Environment,
+	})
+	if err != nil {
+		return nil, rpcError("explain permission", err)
+	}
+	if resp.Error != "" {
+		return nil, responseError("explain permission", resp.Error)
+	}
+	ex := &Explanation{
+		Allowed:         resp.Allowed,
+		MatchedPolicies: make([]Permission, len(resp.MatchedPolicies)),
+		Roles:           resp.Roles,
+		Reason:          resp.Reason,
+	}
+	for i, p := range resp.MatchedPolicies {
+		ex.MatchedPolicies[i] = Permission{
+			Subject:   p.GetSubject(),
+			Domain:    p.GetDomain(),
+			Resource:  p.GetResource(),
+			Action:    p.GetAction(),
+			Condition: p.GetCondition(),
+		}
+	}
+	return ex, nil
+}
+
+// ListPermissionsForSubject returns one page of the policies that apply to the
+// subject in the domain and the token of the next page, empty on the last one.
+func (c *PermissionGRPCClient) ListPermissionsForSubject(ctx context.Context, subject, domain string, pageSize int32, pageToken string) ([]Permission, string, error) {
+	resp, err := c.service.ListPermissionsForSubject(ctx, &permission.ListPermissionsForSubjectRequest{
+		Subject:   subject,
+		Domain:    domain,
+		PageSize:  pageSize,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", rpcError("list permissions", err)
+	}
+	if resp.Error != "" {
+		return nil, "", responseError("list permissions", resp.Error)
+	}
+	perms := make([]Permission, len(resp.Permissions))
+	for i, p := range resp.Permissions {
+		perms[i] = Permission{
+			Subject:   p.GetSubject(),
+			Domain:    p.GetDomain(),
+			Resource:  p.GetResource(),
+			Action:    p.GetAction(),
+			Condition: p.GetCondition(),
+		}
+	}
+	return perms, resp.NextPageToken, nil
+}
+
+func (c *PermissionGRPCClient) ListSubjectsForResource(ctx context.Context, resource, action, domain string, pageSize int32, pageToken string) ([]string, string, error) {
+	resp, err := c.service.ListSubjectsForResource(ctx, &permission.ListSubjectsForResourceRequest{
+		Resource:  resource,
+		Action:    action,
+		Domain:    domain,
+		PageSize:  pageSize,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", rpcError("list subjects", err)
+	}
+	if resp.Error != "" {
+		return nil, "", responseError("list subjects", resp.Error)
+	}
+	return resp.Subjects, resp.NextPageToken, nil
+}
+
+func (c *PermissionGRPCClient) GetImplicitRolesForUser(ctx context.Context, userID, domain string) ([]string, error) {
+	resp, err := c.service.GetImplicitRolesForUser(ctx, &permission.GetImplicitRolesForUserRequest{
+		UserId: userID,
+		Domain: domain,
+	})
+	if err != nil {
+		return nil, rpcError("get implicit roles", err)
+	}
+	if resp.Error != "" {
+		return nil, responseError("get implicit roles", resp.Error)
+	}
+	return resp.Roles, nil
+}
+
+// ListPolicies returns one page of the stored policies matching filter and
+// the token of the next page, empty on the last one.
+func (c *PermissionGRPCClient) ListPolicies(ctx context.Context, filter PolicyFilter, pageSize int32, pageToken string) ([]Permission, string, error) {
+	resp, err := c.service.ListPolicies(ctx, &permission.ListPoliciesRequest{
+		Filter:    policyFilter(filter),
+		PageSize:  pageSize,
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", rpcError("list policies", err)
+	}
+	perms := make([]Permission, len(resp.Policies))
+	for i, p := range resp.Policies {
+		perms[i] = Permission{
+			Subject:   p.GetSubject(),
+			Domain:    p.GetDomain(),
+			Resource:  p.GetResource(),
+			Action:    p.GetAction(),
+			Condition: p.GetCondition(),
+		}
+	}
+	return perms, resp.NextPageToken, nil
+}
+
+// ReplacePolicies makes policies the only stored policies matching filter and
+// reports how many were added and removed.
+func (c *PermissionGRPCClient) ReplacePolicies(ctx context.Context, filter PolicyFilter, policies []Permission) (int, int, error) {
+	req := &permission.ReplacePoliciesRequest{
+		Filter:   policyFilter(filter),
+		Policies: make([]*permission.Policy, len(policies)),
+	}
+	for i, p := range policies {
+		req.Policies[i] = &permission.Policy{
+			Subject:   p.Subject,
+			Domain:    p.Domain,
+			Resource:  p.Resource,
+			Action:    p.Action,
+			Condition: p.Condition,
+		}
+	}
+	resp, err := c.service.ReplacePolicies(ctx, req)
+	if err != nil {
+		return 0, 0, rpcError("replace policies", err)
+	}
+	return int(resp.Added), int(resp.Removed), nil
+}
+
+// ListRoles returns the roles assigned in domain, or in every domain when
+// domain is empty.
+func (c *PermissionGRPCClient) ListRoles(ctx context.Context, domain string) ([]string, error) {
+	resp, err := c.service.ListRoles(ctx, &permission.ListRolesRequest{Domain: domain})
+	if err != nil {
+		return nil, rpcError("list roles", err)
+	}
+	return resp.Roles, nil
+}
+
+func policyFilter(f PolicyFilter) *permission.PolicyFilter {
+	return &permission.PolicyFilter{
+		Subject:  f.Subject,
+		Domain:   f.Domain,
+		Resource: f.Resource,
+		Action:   f.Action,
+	}
+}
+
+// PolicySnapshot fetches every rule the server holds. Each rule starts with
+// its type (p, p2, g), the layout persist.LoadPolicyArray expects.
+func (c *PermissionGRPCClient) PolicySnapshot(ctx context.Context) ([][]string, error) {
+	resp, err := c.service.GetPolicySnapshot(ctx, &permission.GetPolicySnapshotRequest{})
+	if err != nil {
+		return nil, rpcError("policy snapshot", err)
+	}
+	return policyRules(resp.Rules), nil
+}
+
+// PolicyPlan lists the rules a sync adds and removes, each starting with its
+// type like the rules of PolicySnapshot.
+type PolicyPlan struct {
+	Added   [][]string
+	Removed [][]string
+}
+
+// SyncOptions control SyncPolicies. Prune removes the stored rules in the
+// domains and of the role subjects the given rules cover that aren't given;
+// DryRun only reports the plan.
+type SyncOptions struct {
+	Prune  bool
+	DryRun bool
+}
+
+// SyncPolicies makes the server hold rules, in the PolicySnapshot layout, and
+// returns what it changed or, with DryRun, would change.
+func (c *PermissionGRPCClient) SyncPolicies(ctx context.Context, rules [][]string, opts SyncOptions) (*PolicyPlan, error) {
+	req := &permission.SyncPoliciesRequest{
+		Rules:  make([]*permission.PolicyRule, len(rules)),
+		Prune:  opts.Prune,
+		DryRun: opts.DryRun,
+	}
+	for i, r := range rules {
+		req.Rules[i] = &permission.PolicyRule{}
+		if len(r) > 0 {
+			req.Rules[i].Ptype, req.Rules[i].Values = r[0], r[1:]
+		}
+	}
+	resp, err := c.service.SyncPolicies(ctx, req)
+	if err != nil {
+		return nil, rpcError("sync policies", err)
+	}
+	return &PolicyPlan{Added: policyRules(resp.Added), Removed: policyRules(resp.Removed)}, nil
+}
+
+func policyRules(rules []*permission.PolicyRule) [][]string {
+	out := make([][]string, len(rules))
+	for i, r := range rules {
+		out[i] = append([]string{r.GetPtype()}, r.GetValues()...)
+	}
+	return out
+}
+
+// ListPolicyChanges returns one page of the recorded policy changes matching
+// filter, newest first.
+func (c *PermissionGRPCClient) ListPolicyChanges(ctx context.Context, filter PolicyChangeFilter, pageSize int32, pageToken string) ([]PolicyChange, string, error) {
+	req := &permission.ListPolicyChangesRequest{
+		Actor:     filter.Actor,
+		Service:   filter.Service,
+		Subject:   filter.Subject,
+		Domain:    filter.Domain,
+		PageSize:  pageSize,
+		PageToken: pageToken,
+	}
+	if !filter.Since.IsZero() {
+		req.Since = filter.Since.Format(time.RFC3339Nano)
+	}
+	if !filter.Until.IsZero() {
+		req.Until = filter.Until.Format(time.RFC3339Nano)
+	}
+	resp, err := c.service.ListPolicyChanges(ctx, req)
+	if err != nil {
+		return nil, "", rpcError("list policy changes", err)
+	}
+	changes := make([]PolicyChange, len(resp.Changes))
+	for i, ch := range resp.Changes {
+		changedAt, _ := time.Parse(time.RFC3339Nano, ch.GetChangedAt())
+		changes[i] = PolicyChange{
+			Revision:  ch.GetRevision(),
+			ChangedAt: changedAt,
+			Service:   ch.GetService(),
+			Actor:     ch.GetActor(),
+			Type:      ch.GetType(),
+		}
+		if r := ch.GetBefore(); r != nil {
+			changes[i].Before = append([]string{r.GetPtype()}, r.GetValues()...)
+		}
+		if r := ch.GetAfter(); r != nil {
+			changes[i].After = append([]string{r.GetPtype()}, r.GetValues()...)
+		}
+	}
+	return changes, resp.NextPageToken, nil
+}
+
+// RevertPolicies undoes every policy change made after revision and returns
+// what it changed or, with dryRun, would change.
+func (c *PermissionGRPCClient) RevertPolicies(ctx context.Context, revision uint64, dryRun bool) (*PolicyPlan, error) {
+	resp, err := c.service.RevertPolicies(ctx, &permission.RevertPoliciesRequest{Revision: revision, DryRun: dryRun})
+	if err != nil {
+		return nil, rpcError("revert policies", err)
+	}
+	return &PolicyPlan{Added: policyRules(resp.Added), Removed: policyRules(resp.Removed)}, nil
+}
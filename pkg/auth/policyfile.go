@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Policy file formats. CSV is casbin's own layout, one rule per line starting
+// with its type; YAML lists policies and role links by field name.
+const (
+	PolicyFormatCSV  = "csv"
+	PolicyFormatYAML = "yaml"
+)
+
+// ErrInvalidPolicyFile means a policy file could not be read or holds a rule
+// the model doesn't accept.
+var ErrInvalidPolicyFile = errors.New("invalid policy file")
+
+// policyArity is the number of values of each rule type in model.conf.
+var policyArity = map[string]int{"p": 4, "p2": 5, "g": 3}
+
+// PolicyFormat picks the format of a policy file by its extension: .yaml and
+// .yml are YAML, anything else CSV.
+func PolicyFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return PolicyFormatYAML
+	default:
+		return PolicyFormatCSV
+	}
+}
+
+// policyFile is the YAML layout. Domain defaults to the global domain; a
+// policy with a condition is an ABAC rule.
+type policyFile struct {
+	Policies []policyEntry `yaml:"policies,omitempty"`
+	Roles    []roleEntry   `yaml:"roles,omitempty"`
+}
+
+type policyEntry struct {
+	Subject   string `yaml:"subject"`
+	Domain    string `yaml:"domain,omitempty"`
+	Resource  string `yaml:"resource"`
+	Action    string `yaml:"action"`
+	Condition string `yaml:"condition,omitempty"`
+}
+
+type roleEntry struct {
+	Subject string `yaml:"subject"`
+	Role    string `yaml:"role"`
+	Domain  string `yaml:"domain,omitempty"`
+}
+
+// ParsePolicies reads a policy file into rules in the PolicySnapshot layout
+// and checks each has the values its type needs.
+func ParsePolicies(data []byte, format string) ([][]string, error) {
+	var (
+		rules [][]string
+		err   error
+	)
+	switch format {
+	case PolicyFormatCSV:
+		rules, err = parseCSVPolicies(data)
+	case PolicyFormatYAML:
+		rules, err = parseYAMLPolicies(data)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidPolicyFile, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, rule := range rules {
+		if err := validatePolicy(rule); err != nil {
+			return nil, fmt.Errorf("%w: rule %d: %s", ErrInvalidPolicyFile, i+1, err)
+		}
+	}
+	return rules, nil
+}
+
+func parseCSVPolicies(data []byte) ([][]string, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comment = '#'
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+	rules, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPolicyFile, err)
+	}
+	return rules, nil
+}
+
+func parseYAMLPolicies(data []byte) ([][]string, error) {
+	var f policyFile
+	if err := yaml.UnmarshalWithOptions(data, &f, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPolicyFile, err)
+	}
+	rules := make([][]string, 0, len(f.Policies)+len(f.Roles))
+	for _, p := range f.Policies {
+		rule := []string{"p", p.Subject, orGlobal(p.Domain), p.Resource, p.Action}
+		if p.Condition != "" {
+			rule = append(rule, p.Condition)
+			rule[0] = "p2"
+		}
+		rules = append(rules, rule)
+	}
+	for _, g := range f.Roles {
+		rules = append(rules, []string{"g", g.Subject, g.Role, orGlobal(g.Domain)})
+	}
+	return rules, nil
+}
+
+func orGlobal(domain string) string {
+	if domain == "" {
+		return GlobalDomain
+	}
+	return domain
+}
+
+func validatePolicy(rule []string) error {
+	if len(rule) == 0 {
+		return errors.New("empty rule")
+	}
+	arity, ok := policyArity[rule[0]]
+	if !ok {
+		return fmt.Errorf("unknown type %q", rule[0])
+	}
+	if len(rule)-1 != arity {
+		return fmt.Errorf("%s needs %d values, got %d", rule[0], arity, len(rule)-1)
+	}
+	if slices.Contains(rule[1:], "") {
+		return fmt.Errorf("%s has an empty value", rule[0])
+	}
+	return nil
+}
+
+// EncodePolicies writes rules in the PolicySnapshot layout as a policy file
+// ParsePolicies reads back.
+func EncodePolicies(rules [][]string, format string) ([]byte, error) {
+	for i, rule := range rules {
+		if err := validatePolicy(rule); err != nil {
+			return nil, fmt.Errorf("%w: rule %d: %s", ErrInvalidPolicyFile, i+1, err)
+		}
+	}
+	switch format {
+	case PolicyFormatCSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.WriteAll(rules); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case PolicyFormatYAML:
+		var f policyFile
+		for _, rule := range rules {
+			switch rule[0] {
+			case "g":
+				f.Roles = append(f.Roles, roleEntry{Subject: rule[1], Role: rule[2], Domain: rule[3]})
+			default:
+				p := policyEntry{Subject: rule[1], Domain: rule[2], Resource: rule[3], Action: rule[4]}
+				if rule[0] == "p2" {
+					p.Condition = rule[5]
+				}
+				f.Policies = append(f.Policies, p)
+			}
+		}
+		return yaml.Marshal(f)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidPolicyFile, format)
+	}
+}
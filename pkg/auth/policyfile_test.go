@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/mrhumster/web-server-gin/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicies(t *testing.T) {
+	yamlFile := []byte(`
+policies:
+  - {subject: member, resource: users, action: read}
+  - {subject: member, domain: org1, resource: stream, action: read, condition: "r2.attrs.env.hour < 18"}
+roles:
+  - {subject: admin, role: member}
+`)
+	csvFile := []byte(`# baseline
+p, member, *, users, read
+p2, member, org1, stream, read, "r2.attrs.env.hour < 18"
+g, admin, member, *
+`)
+	want := [][]string{
+		{"p", "member", "*", "users", "read"},
+		{"p2", "member", "org1", "stream", "read", "r2.attrs.env.hour < 18"},
+		{"g", "admin", "member", "*"},
+	}
+	for format, data := range map[string][]byte{PolicyFormatYAML: yamlFile, PolicyFormatCSV: csvFile} {
+		rules, err := ParsePolicies(data, format)
+		require.NoError(t, err, format)
+		assert.Equal(t, want, rules, format)
+
+		encoded, err := EncodePolicies(rules, format)
+		require.NoError(t, err, format)
+		again, err := ParsePolicies(encoded, format)
+		require.NoError(t, err, format)
+		assert.Equal(t, want, again, "%s round trip", format)
+	}
+
+	_, err := ParsePolicies(config.BaselinePolicies, PolicyFormatYAML)
+	assert.NoError(t, err, "config/policies.yaml")
+}
+
+func TestParsePolicies_Invalid(t *testing.T) {
+	cases := []struct {
+		name   string
+		data   string
+		format string
+	}{
+		{"unknown type", "x, a, b\n", PolicyFormatCSV},
+		{"missing value", "p, member, *, users\n", PolicyFormatCSV},
+		{"empty value", "g, alice, , *\n", PolicyFormatCSV},
+		{"missing action", "policies:\n  - {subject: member, resource: users}\n", PolicyFormatYAML},
+		{"unknown field", "policies:\n  - {subject: member, resource: users, action: read, effect: deny}\n", PolicyFormatYAML},
+		{"unknown format", "", "json"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParsePolicies([]byte(tc.data), tc.format)
+			assert.ErrorIs(t, err, ErrInvalidPolicyFile)
+		})
+	}
+}
+
+func TestPolicyFormat(t *testing.T) {
+	assert.Equal(t, PolicyFormatYAML, PolicyFormat("config/policies.yaml"))
+	assert.Equal(t, PolicyFormatYAML, PolicyFormat("policies.YML"))
+	assert.Equal(t, PolicyFormatCSV, PolicyFormat("policy.csv"))
+}
@@ -0,0 +1,255 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sync"
+
+	"github.com/goccy/go-yaml"
+	"github.com/mrhumster/web-server-gin/config"
+)
+
+var (
+	// ErrInvalidTemplate means a provisioning template could not be read, or
+	// could not be filled in because the resource lacks one of its values.
+	ErrInvalidTemplate = errors.New("invalid provisioning template")
+	// ErrUnknownResourceType means no template is registered for a resource
+	// type.
+	ErrUnknownResourceType = errors.New("unknown resource type")
+)
+
+// placeholder is a {name} in a template value, filled from the resource.
+var placeholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// ProvisioningTemplate lists the rules granted when a resource of one type is
+// created and revoked when it is deleted. Any value may hold {placeholders},
+// e.g. {id}, filled from the resource; Domain defaults to the global domain.
+type ProvisioningTemplate struct {
+	Policies []GrantTemplate `yaml:"policies,omitempty"`
+	Roles    []RoleTemplate  `yaml:"roles,omitempty"`
+}
+
+// GrantTemplate grants Subject each of Actions on Resource. A grant with a
+// condition is an ABAC rule.
+type GrantTemplate struct {
+	Subject   string   `yaml:"subject"`
+	Domain    string   `yaml:"domain,omitempty"`
+	Resource  string   `yaml:"resource"`
+	Actions   []string `yaml:"actions"`
+	Condition string   `yaml:"condition,omitempty"`
+}
+
+// RoleTemplate assigns Subject the role.
+type RoleTemplate struct {
+	Subject string `yaml:"subject"`
+	Role    string `yaml:"role"`
+	Domain  string `yaml:"domain,omitempty"`
+}
+
+// ProvisioningTemplates holds a template per resource type. It is safe for
+// concurrent use.
+type ProvisioningTemplates struct {
+	mu        sync.RWMutex
+	templates map[string]ProvisioningTemplate
+}
+
+func NewProvisioningTemplates() *ProvisioningTemplates {
+	return &ProvisioningTemplates{templates: make(map[string]ProvisioningTemplate)}
+}
+
+// ParseProvisioningTemplates reads a templates file: a map of resource type to
+// template under "templates".
+func ParseProvisioningTemplates(data []byte) (*ProvisioningTemplates, error) {
+	var f struct {
+		Templates map[string]ProvisioningTemplate `yaml:"templates"`
+	}
+	if err := yaml.UnmarshalWithOptions(data, &f, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTemplate, err)
+	}
+	t := NewProvisioningTemplates()
+	for resource, tmpl := range f.Templates {
+		if err := t.Register(resource, tmpl); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// ReadProvisioningTemplates parses the templates file at path.
+func ReadProvisioningTemplates(path string) (*ProvisioningTemplates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t, err := ParseProvisioningTemplates(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// DefaultProvisioningTemplates returns the templates of
+// config/provisioning.yaml.
+func DefaultProvisioningTemplates() *ProvisioningTemplates {
+	t, err := ParseProvisioningTemplates(config.ProvisioningTemplates)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Register sets the template of a resource type, replacing any earlier one.
+func (t *ProvisioningTemplates) Register(resource string, tmpl ProvisioningTemplate) error {
+	if resource == "" {
+		return fmt.Errorf("%w: empty resource type", ErrInvalidTemplate)
+	}
+	for i, g := range tmpl.Policies {
+		if g.Subject == "" || g.Resource == "" || len(g.Actions) == 0 || slices.Contains(g.Actions, "") {
+			return fmt.Errorf("%w: %s: policy %d needs a subject, a resource and actions", ErrInvalidTemplate, resource, i+1)
+		}
+	}
+	for i, r := range tmpl.Roles {
+		if r.Subject == "" || r.Role == "" {
+			return fmt.Errorf("%w: %s: role %d needs a subject and a role", ErrInvalidTemplate, resource, i+1)
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.templates[resource] = tmpl
+	return nil
+}
+
+// Resources returns the registered resource types, sorted.
+func (t *ProvisioningTemplates) Resources() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	resources := make([]string, 0, len(t.templates))
+	for resource := range t.templates {
+		resources = append(resources, resource)
+	}
+	slices.Sort(resources)
+	return resources
+}
+
+// Render fills in the template of a resource type with the resource's values
+// and returns its rules in the PolicySnapshot layout, policies first. The
+// same values render the same rules, so rendering on deletion gives the rules
+// to revoke.
+func (t *ProvisioningTemplates) Render(resource string, values map[string]string) ([][]string, error) {
+	t.mu.RLock()
+	tmpl, ok := t.templates[resource]
+	t.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownResourceType, resource)
+	}
+
+	var missing []string
+	fill := func(s string) string {
+		return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+			name := m[1 : len(m)-1]
+			v := values[name]
+			if v == "" && !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+			return v
+		})
+	}
+
+	var rules [][]string
+	for _, g := range tmpl.Policies {
+		for _, action := range g.Actions {
+			rule := []string{"p", fill(g.Subject), fill(orGlobal(g.Domain)), fill(g.Resource), fill(action)}
+			if g.Condition != "" {
+				rule = append(rule, fill(g.Condition))
+				rule[0] = "p2"
+			}
+			rules = append(rules, rule)
+		}
+	}
+	for _, r := range tmpl.Roles {
+		rules = append(rules, []string{"g", fill(r.Subject), fill(r.Role), fill(orGlobal(r.Domain))})
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s: no value for %v", ErrInvalidTemplate, resource, missing)
+	}
+	return rules, nil
+}
+
+// Provision grants the rules of a created resource. Rules that exist already
+// are left alone, so it is safe to call again after a failure.
+func (t *ProvisioningTemplates) Provision(ctx context.Context, client RuleWriter, resource string, values map[string]string) error {
+	rules, err := t.Render(resource, values)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if err := GrantRule(ctx, client, rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deprovision revokes the rules Provision granted for a deleted resource.
+// Rules that are gone already are skipped, so it is safe to call again after
+// a failure.
+func (t *ProvisioningTemplates) Deprovision(ctx context.Context, client RuleWriter, resource string, values map[string]string) error {
+	rules, err := t.Render(resource, values)
+	if err != nil {
+		return err
+	}
+	for _, rule := range slices.Backward(rules) {
+		if err := RevokeRule(ctx, client, rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RuleWriter is the part of PermissionClient that changes single rules.
+type RuleWriter interface {
+	AddPolicyInDomain(ctx context.Context, subject, domain, resource, action string) (bool, error)
+	RemovePolicyInDomain(ctx context.Context, subject, domain, resource, action string) (bool, error)
+	AddConditionalPolicy(ctx context.Context, subject, domain, resource, action, condition string) (bool, error)
+	RemoveConditionalPolicy(ctx context.Context, subject, domain, resource, action, condition string) (bool, error)
+	AssignRoleInDomain(ctx context.Context, userID, role, domain string) (bool, error)
+	UnassignRoleInDomain(ctx context.Context, userID, role, domain string) (bool, error)
+}
+
+// GrantRule and RevokeRule add and remove one rule in the PolicySnapshot
+// layout.
+func GrantRule(ctx context.Context, client RuleWriter, rule []string) error {
+	if err := validatePolicy(rule); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRequest, err)
+	}
+	var err error
+	switch v := rule[1:]; rule[0] {
+	case "g":
+		_, err = client.AssignRoleInDomain(ctx, v[0], v[1], v[2])
+	case "p2":
+		_, err = client.AddConditionalPolicy(ctx, v[0], v[1], v[2], v[3], v[4])
+	default:
+		_, err = client.AddPolicyInDomain(ctx, v[0], v[1], v[2], v[3])
+	}
+	return err
+}
+
+func RevokeRule(ctx context.Context, client RuleWriter, rule []string) error {
+	if err := validatePolicy(rule); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRequest, err)
+	}
+	var err error
+	switch v := rule[1:]; rule[0] {
+	case "g":
+		_, err = client.UnassignRoleInDomain(ctx, v[0], v[1], v[2])
+	case "p2":
+		_, err = client.RemoveConditionalPolicy(ctx, v[0], v[1], v[2], v[3], v[4])
+	default:
+		_, err = client.RemovePolicyInDomain(ctx, v[0], v[1], v[2], v[3])
+	}
+	return err
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvisioningTemplates_Render(t *testing.T) {
+	templates, err := ParseProvisioningTemplates([]byte(`
+templates:
+  user:
+    policies:
+      - {subject: "{id}", resource: "users/{id}", actions: [read, write, delete]}
+    roles:
+      - {subject: "{id}", role: "{role}"}
+  stream:
+    policies:
+      - {subject: "{owner}", domain: "{org}", resource: "stream/{id}", actions: [read], condition: "r2.attrs.env.hour < 18"}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stream", "user"}, templates.Resources())
+
+	rules, err := templates.Render("user", map[string]string{"id": "u1", "role": "member"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"p", "u1", "*", "users/u1", "read"},
+		{"p", "u1", "*", "users/u1", "write"},
+		{"p", "u1", "*", "users/u1", "delete"},
+		{"g", "u1", "member", "*"},
+	}, rules)
+
+	rules, err = templates.Render("stream", map[string]string{"id": "s1", "owner": "u1", "org": "org1"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"p2", "u1", "org1", "stream/s1", "read", "r2.attrs.env.hour < 18"}}, rules)
+
+	_, err = templates.Render("stream", map[string]string{"id": "s1"})
+	assert.ErrorIs(t, err, ErrInvalidTemplate)
+	assert.ErrorContains(t, err, "owner")
+
+	_, err = templates.Render("video", map[string]string{"id": "v1"})
+	assert.ErrorIs(t, err, ErrUnknownResourceType)
+}
+
+func TestProvisioningTemplates_Register(t *testing.T) {
+	templates := NewProvisioningTemplates()
+	err := templates.Register("video", ProvisioningTemplate{
+		Policies: []GrantTemplate{{Subject: "{owner}", Resource: "videos/{id}"}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidTemplate, "no actions")
+
+	require.NoError(t, templates.Register("video", ProvisioningTemplate{
+		Policies: []GrantTemplate{{Subject: "{owner}", Resource: "videos/{id}", Actions: []string{"read"}}},
+	}))
+	rules, err := templates.Render("video", map[string]string{"id": "v1", "owner": "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"p", "u1", "*", "videos/v1", "read"}}, rules)
+
+	_, err = ParseProvisioningTemplates([]byte("templates:\n  user:\n    grants: []\n"))
+	assert.ErrorIs(t, err, ErrInvalidTemplate, "unknown field")
+}
+
+func TestDefaultProvisioningTemplates(t *testing.T) {
+	templates := DefaultProvisioningTemplates()
+	rules, err := templates.Render("user", map[string]string{"id": "u1", "email": "u1@example.com", "role": "member"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"g", "u1", "member", "*"}}, rules)
+}
+
+// ruleLog records the calls of a RuleWriter.
+type ruleLog struct{ calls []string }
+
+func (l *ruleLog) record(call string, args ...string) (bool, error) {
+	l.calls = append(l.calls, call+" "+strings.Join(args, " "))
+	return true, nil
+}
+
+func (l *ruleLog) AddPolicyInDomain(_ context.Context, sub, dom, obj, act string) (bool, error) {
+	return l.record("add", sub, dom, obj, act)
+}
+
+func (l *ruleLog) RemovePolicyInDomain(_ context.Context, sub, dom, obj, act string) (bool, error) {
+	return l.record("remove", sub, dom, obj, act)
+}
+
+func (l *ruleLog) AddConditionalPolicy(_ context.Context, sub, dom, obj, act, cond string) (bool, error) {
+	return l.record("add", sub, dom, obj, act, cond)
+}
+
+func (l *ruleLog) RemoveConditionalPolicy(_ context.Context, sub, dom, obj, act, cond string) (bool, error) {
+	return l.record("remove", sub, dom, obj, act, cond)
+}
+
+func (l *ruleLog) AssignRoleInDomain(_ context.Context, user, role, dom string) (bool, error) {
+	return l.record("assign", user, role, dom)
+}
+
+func (l *ruleLog) UnassignRoleInDomain(_ context.Context, user, role, dom string) (bool, error) {
+	return l.record("unassign", user, role, dom)
+}
+
+func TestProvisioningTemplates_ProvisionAndDeprovision(t *testing.T) {
+	templates := NewProvisioningTemplates()
+	require.NoError(t, templates.Register("stream", ProvisioningTemplate{
+		Policies: []GrantTemplate{{Subject: "{owner}", Resource: "stream/{id}", Actions: []string{"read", "write"}}},
+		Roles:    []RoleTemplate{{Subject: "{owner}", Role: "streamer"}},
+	}))
+	values := map[string]string{"id": "s1", "owner": "u1"}
+
+	var log ruleLog
+	require.NoError(t, templates.Provision(t.Context(), &log, "stream", values))
+	require.NoError(t, templates.Deprovision(t.Context(), &log, "stream", values))
+	assert.Equal(t, []string{
+		"add u1 * stream/s1 read",
+		"add u1 * stream/s1 write",
+		"assign u1 streamer *",
+		"unassign u1 streamer *",
+		"remove u1 * stream/s1 write",
+		"remove u1 * stream/s1 read",
+	}, log.calls)
+
+	err := GrantRule(t.Context(), &log, []string{"g", "u1"})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
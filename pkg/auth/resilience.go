@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	authpb "github.com/mrhumster/web-server-gin/gen/go/auth"
+	"github.com/mrhumster/web-server-gin/gen/go/permission"
+	"github.com/mrhumster/web-server-gin/gen/go/user"
+	"github.com/sony/gobreaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Defaults of the permission client. Checks sit on the request path of every
+// protected route, so a call gives up long before the HTTP write timeout.
+const (
+	DefaultCallTimeout     = 3 * time.Second
+	DefaultRetryAttempts   = 3
+	DefaultBreakerFailures = 5
+	DefaultBreakerTimeout  = 30 * time.Second
+)
+
+// WithCallTimeout bounds calls whose context has no deadline, retries
+// included. Zero leaves them unbounded.
+func WithCallTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.callTimeout = d
+	}
+}
+
+// WithRetries sets how many times, the first included, a read RPC is tried
+// while the server is unavailable. Mutations are never retried. One or less
+// disables retries.
+func WithRetries(attempts int) ClientOption {
+	return func(o *clientOptions) {
+		o.retryAttempts = attempts
+	}
+}
+
+// WithCircuitBreaker fails calls right away for openFor once the server
+// failed failures times in a row, then lets a few through to probe it. Zero
+// failures disables the breaker.
+func WithCircuitBreaker(failures uint32, openFor time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.breakerFailures = failures
+		o.breakerTimeout = openFor
+	}
+}
+
+func defaultClientOptions() clientOptions {
+	return clientOptions{
+		callTimeout:     DefaultCallTimeout,
+		retryAttempts:   DefaultRetryAttempts,
+		breakerFailures: DefaultBreakerFailures,
+		breakerTimeout:  DefaultBreakerTimeout,
+	}
+}
+
+// resilienceOptions bound, retry and break unary calls. WatchPolicies is a
+// stream and is resumed by its callers instead.
+func (o *clientOptions) resilienceOptions() ([]grpc.DialOption, error) {
+	var (
+		opts         []grpc.DialOption
+		interceptors []grpc.UnaryClientInterceptor
+	)
+	if o.callTimeout > 0 {
+		interceptors = append(interceptors, deadlineInterceptor(o.callTimeout))
+	}
+	if o.breakerFailures > 0 {
+		interceptors = append(interceptors, breakerInterceptor(newBreaker(o.breakerFailures, o.breakerTimeout)))
+	}
+	if len(interceptors) > 0 {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(interceptors...))
+	}
+	if o.retryAttempts > 1 {
+		serviceConfig, err := retryServiceConfig(o.retryAttempts)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithDefaultServiceConfig(serviceConfig))
+	}
+	return opts, nil
+}
+
+func deadlineInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func newBreaker(failures uint32, openFor time.Duration) *gobreaker.CircuitBreaker {
+	return gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:        "permission service",
+		MaxRequests: 1,
+		Timeout:     openFor,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= failures
+		},
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			slog.Warn("Circuit breaker state changed", "breaker", name, "from", from.String(), "to", to.String())
+		},
+		IsSuccessful: func(err error) bool {
+			return !serverFailure(err)
+		},
+	})
+}
+
+// serverFailure tells the errors of a server that is down or broken from
+// rejected requests and callers giving up.
+func serverFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+func breakerInterceptor(cb *gobreaker.CircuitBreaker) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		_, err := cb.Execute(func() (any, error) {
+			return nil, invoker(ctx, method, req, reply, cc, opts...)
+		})
+		switch err {
+		case gobreaker.ErrOpenState, gobreaker.ErrTooManyRequests:
+			return status.Error(codes.Unavailable, "permission service circuit breaker is open")
+		}
+		return err
+	}
+}
+
+// retryMethods are the RPCs that only read and are safe to send again.
+var retryMethods = []string{
+	permission.PermissionService_CheckPermission_FullMethodName,
+	permission.PermissionService_BatchCheckPermission_FullMethodName,
+	permission.PermissionService_ExplainPermission_FullMethodName,
+	permission.PermissionService_ListRoleMembers_FullMethodName,
+	permission.PermissionService_ListPermissionsForSubject_FullMethodName,
+	permission.PermissionService_ListSubjectsForResource_FullMethodName,
+	permission.PermissionService_GetImplicitRolesForUser_FullMethodName,
+	permission.PermissionService_ListPolicies_FullMethodName,
+	permission.PermissionService_ListRoles_FullMethodName,
+	permission.PermissionService_GetPolicySnapshot_FullMethodName,
+	permission.PermissionService_ListPolicyChanges_FullMethodName,
+	user.UserService_GetUser_FullMethodName,
+	user.UserService_BatchGetUsers_FullMethodName,
+	user.UserService_ListUsers_FullMethodName,
+	user.UserService_SearchUsers_FullMethodName,
+	authpb.AuthService_ValidateToken_FullMethodName,
+	authpb.AuthService_GetJWKS_FullMethodName,
+}
+
+// retryServiceConfig lets gRPC retry the read RPCs on Unavailable with
+// exponential backoff.
+func retryServiceConfig(attempts int) (string, error) {
+	type name struct {
+		Service string `json:"service"`
+		Method  string `json:"method"`
+	}
+	names := make([]name, len(retryMethods))
+	for i, m := range retryMethods {
+		service, method, _ := strings.Cut(strings.TrimPrefix(m, "/"), "/")
+		names[i] = name{Service: service, Method: method}
+	}
+	cfg := map[string]any{
+		"methodConfig": []map[string]any{{
+			"name": names,
+			"retryPolicy": map[string]any{
+				"maxAttempts":          attempts,
+				"initialBackoff":       "0.1s",
+				"maxBackoff":           "1s",
+				"backoffMultiplier":    2,
+				"retryableStatusCodes": []string{"UNAVAILABLE"},
+			},
+		}},
+	}
+	data, err := json.Marshal(cfg)
+	return string(data), err
+}
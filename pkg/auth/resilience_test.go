@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mrhumster/web-server-gin/gen/go/permission"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flakyServer fails the first failures calls with code, then allows.
+type flakyServer struct {
+	permission.UnimplementedPermissionServiceServer
+	failures int32
+	code     codes.Code
+	block    bool
+	calls    atomic.Int32
+}
+
+func (s *flakyServer) answer(ctx context.Context) error {
+	n := s.calls.Add(1)
+	if s.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if n <= s.failures {
+		return status.Error(s.code, "flaky")
+	}
+	return nil
+}
+
+func (s *flakyServer) CheckPermission(ctx context.Context, _ *permission.CheckPermissionRequest) (*permission.CheckPermissionResponse, error) {
+	if err := s.answer(ctx); err != nil {
+		return nil, err
+	}
+	return &permission.CheckPermissionResponse{Allowed: true}, nil
+}
+
+func (s *flakyServer) AddPolicy(ctx context.Context, _ *permission.AddPolicyRequest) (*permission.AddPolicyResponse, error) {
+	if err := s.answer(ctx); err != nil {
+		return nil, err
+	}
+	return &permission.AddPolicyResponse{Added: true}, nil
+}
+
+func startFlakyServer(t *testing.T, s *flakyServer, opts ...ClientOption) *PermissionGRPCClient {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	permission.RegisterPermissionServiceServer(server, s)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	client, err := NewPermissionGRPCClient(lis.Addr().String(), opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+var checkReq = CheckRequest{UserID: "alice", Resource: "users", Action: "read"}
+
+func TestPermissionGRPCClient_RetriesReads(t *testing.T) {
+	s := &flakyServer{failures: 2, code: codes.Unavailable}
+	client := startFlakyServer(t, s, WithCircuitBreaker(0, 0))
+
+	allowed, err := client.Check(t.Context(), checkReq)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, int32(3), s.calls.Load())
+}
+
+func TestPermissionGRPCClient_DoesNotRetryMutations(t *testing.T) {
+	s := &flakyServer{failures: 1, code: codes.Unavailable}
+	client := startFlakyServer(t, s, WithCircuitBreaker(0, 0))
+
+	_, err := client.AddPolicy(t.Context(), "alice", "users", "read")
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, int32(1), s.calls.Load())
+}
+
+func TestPermissionGRPCClient_CircuitBreaker(t *testing.T) {
+	s := &flakyServer{failures: 100, code: codes.Unavailable}
+	client := startFlakyServer(t, s, WithRetries(1), WithCircuitBreaker(2, time.Minute))
+
+	for range 2 {
+		_, err := client.Check(t.Context(), checkReq)
+		assert.ErrorIs(t, err, ErrUnavailable)
+	}
+	_, err := client.Check(t.Context(), checkReq)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.ErrorContains(t, err, "circuit breaker is open")
+	assert.Equal(t, int32(2), s.calls.Load(), "the open breaker doesn't reach the server")
+}
+
+func TestPermissionGRPCClient_RejectedRequestsDontTripBreaker(t *testing.T) {
+	s := &flakyServer{failures: 3, code: codes.InvalidArgument}
+	client := startFlakyServer(t, s, WithCircuitBreaker(1, time.Minute))
+
+	for range 3 {
+		_, err := client.Check(t.Context(), checkReq)
+		assert.ErrorIs(t, err, ErrInvalidRequest)
+	}
+	allowed, err := client.Check(t.Context(), checkReq)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestPermissionGRPCClient_DefaultDeadline(t *testing.T) {
+	s := &flakyServer{block: true}
+	client := startFlakyServer(t, s, WithCallTimeout(50*time.Millisecond), WithRetries(1))
+
+	start := time.Now()
+	_, err := client.Check(context.Background(), checkReq)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	authpb "github.com/mrhumster/web-server-gin/gen/go/auth"
+	"google.golang.org/grpc"
+)
+
+// TokenInfo is what AuthGRPCClient.ValidateToken learned about a token.
+type TokenInfo struct {
+	// Valid is set when the token is well signed, unexpired and not revoked.
+	Valid bool
+	// Error tells why the token could not be parsed.
+	Error            string
+	Revoked          bool
+	RevocationReason string
+	UserID           string
+	Role             string
+	OrgID            string
+	TokenVersion     string
+	Issuer           string
+	IssuedAt         time.Time
+	ExpiresAt        time.Time
+}
+
+// JSONWebKey is a public signing key in JWK form (RFC 7517).
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// AuthGRPCClient validates and issues tokens through the AuthService served
+// next to the permission service.
+type AuthGRPCClient struct {
+	conn    *grpc.ClientConn
+	service authpb.AuthServiceClient
+}
+
+// NewAuthGRPCClient connects to the AuthService with the same options, and
+// the same defaults, as NewPermissionGRPCClient.
+func NewAuthGRPCClient(url string, opts ...ClientOption) (*AuthGRPCClient, error) {
+	conn, err := Dial(url, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthGRPCClient{conn: conn, service: authpb.NewAuthServiceClient(conn)}, nil
+}
+
+func (c *AuthGRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// ValidateAccessToken checks a user access token. A bad token is reported
+// in the TokenInfo; an error means it could not be checked.
+func (c *AuthGRPCClient) ValidateAccessToken(ctx context.Context, token string) (*TokenInfo, error) {
+	return c.validate(ctx, token, authpb.ValidateTokenRequest_ACCESS)
+}
+
+// ValidateRefreshToken checks a user refresh token like ValidateAccessToken.
+func (c *AuthGRPCClient) ValidateRefreshToken(ctx context.Context, token string) (*TokenInfo, error) {
+	return c.validate(ctx, token, authpb.ValidateTokenRequest_REFRESH)
+}
+
+func (c *AuthGRPCClient) validate(ctx context.Context, token string, kind authpb.ValidateTokenRequest_TokenType) (*TokenInfo, error) {
+	resp, err := c.service.ValidateToken(ctx, &authpb.ValidateTokenRequest{Token: token, Type: kind})
+	if err != nil {
+		return nil, rpcError("validate token", err)
+	}
+	claims := resp.GetClaims()
+	issuedAt, _ := time.Parse(time.RFC3339, claims.GetIssuedAt())
+	expiresAt, _ := time.Parse(time.RFC3339, claims.GetExpiresAt())
+	return &TokenInfo{
+		Valid:            resp.GetValid(),
+		Error:            resp.GetError(),
+		Revoked:          resp.GetRevoked(),
+		RevocationReason: resp.GetRevocationReason(),
+		UserID:           claims.GetUserId(),
+		Role:             claims.GetRole(),
+		OrgID:            claims.GetOrgId(),
+		TokenVersion:     claims.GetTokenVersion(),
+		Issuer:           claims.GetIssuer(),
+		IssuedAt:         issuedAt,
+		ExpiresAt:        expiresAt,
+	}, nil
+}
+
+// IssueServiceToken returns a service token for name, the caller when empty,
+// and when it expires. Only policy admins may name another service.
+func (c *AuthGRPCClient) IssueServiceToken(ctx context.Context, name string) (string, time.Time, error) {
+	resp, err := c.service.IssueServiceToken(ctx, &authpb.IssueServiceTokenRequest{ServiceName: name})
+	if err != nil {
+		return "", time.Time{}, rpcError("issue service token", err)
+	}
+	expiresAt, _ := time.Parse(time.RFC3339, resp.GetExpiresAt())
+	return resp.GetToken(), expiresAt, nil
+}
+
+// GetJWKS returns the keys access and service tokens are signed with, for
+// verifying them locally.
+func (c *AuthGRPCClient) GetJWKS(ctx context.Context) ([]JSONWebKey, error) {
+	resp, err := c.service.GetJWKS(ctx, &authpb.GetJWKSRequest{})
+	if err != nil {
+		return nil, rpcError("get jwks", err)
+	}
+	keys := make([]JSONWebKey, len(resp.GetKeys()))
+	for i, k := range resp.GetKeys() {
+		keys[i] = JSONWebKey{Kty: k.GetKty(), Kid: k.GetKid(), Use: k.GetUse(), Alg: k.GetAlg(), N: k.GetN(), E: k.GetE()}
+	}
+	return keys, nil
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/mrhumster/web-server-gin/gen/go/user"
+	"google.golang.org/grpc"
+)
+
+// User is an account as other services see it.
+type User struct {
+	ID        string
+	Email     string
+	Role      string
+	Disabled  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// UserGRPCClient looks users up in the UserService served next to the
+// permission service. Calls made with a context from WithActor are
+// authorized for that user, like the HTTP API; other calls for the service.
+type UserGRPCClient struct {
+	conn    *grpc.ClientConn
+	service user.UserServiceClient
+}
+
+// NewUserGRPCClient connects to the UserService with the same options, and
+// the same defaults, as NewPermissionGRPCClient.
+func NewUserGRPCClient(url string, opts ...ClientOption) (*UserGRPCClient, error) {
+	conn, err := Dial(url, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &UserGRPCClient{conn: conn, service: user.NewUserServiceClient(conn)}, nil
+}
+
+func (c *UserGRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// GetUser returns the user with the id. ErrNotFound means there is none.
+func (c *UserGRPCClient) GetUser(ctx context.Context, id string) (*User, error) {
+	resp, err := c.service.GetUser(ctx, &user.GetUserRequest{Id: id})
+	if err != nil {
+		return nil, rpcError("get user", err)
+	}
+	return userFromMessage(resp), nil
+}
+
+// GetUserByEmail returns the user with the email. ErrNotFound means there is
+// none.
+func (c *UserGRPCClient) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	resp, err := c.service.GetUser(ctx, &user.GetUserRequest{Email: email})
+	if err != nil {
+		return nil, rpcError("get user", err)
+	}
+	return userFromMessage(resp), nil
+}
+
+// BatchGetUsers returns the users with the ids in request order and the ids
+// of the users that don't exist or may not be read.
+func (c *UserGRPCClient) BatchGetUsers(ctx context.Context, ids []string) ([]User, []string, error) {
+	if len(ids) == 0 {
+		return []User{}, nil, nil
+	}
+	resp, err := c.service.BatchGetUsers(ctx, &user.BatchGetUsersRequest{Ids: ids})
+	if err != nil {
+		return nil, nil, rpcError("batch get users", err)
+	}
+	return usersFromMessages(resp.Users), resp.MissingIds, nil
+}
+
+// ListUsers returns one page of the users, newest first, and the token of
+// the next page, empty on the last one.
+func (c *UserGRPCClient) ListUsers(ctx context.Context, pageSize int32, pageToken string) ([]User, string, error) {
+	resp, err := c.service.ListUsers(ctx, &user.ListUsersRequest{PageSize: pageSize, PageToken: pageToken})
+	if err != nil {
+		return nil, "", rpcError("list users", err)
+	}
+	return usersFromMessages(resp.Users), resp.NextPageToken, nil
+}
+
+// SearchUsers pages through the users whose email contains query like
+// ListUsers.
+func (c *UserGRPCClient) SearchUsers(ctx context.Context, query string, pageSize int32, pageToken string) ([]User, string, error) {
+	resp, err := c.service.SearchUsers(ctx, &user.SearchUsersRequest{Query: query, PageSize: pageSize, PageToken: pageToken})
+	if err != nil {
+		return nil, "", rpcError("search users", err)
+	}
+	return usersFromMessages(resp.Users), resp.NextPageToken, nil
+}
+
+// CreateUser creates a user with the role, member when empty.
+// ErrAlreadyExists means the email is taken.
+func (c *UserGRPCClient) CreateUser(ctx context.Context, email, password, role string) (*User, error) {
+	resp, err := c.service.CreateUser(ctx, &user.CreateUserRequest{Email: email, Password: password, Role: role})
+	if err != nil {
+		return nil, rpcError("create user", err)
+	}
+	return userFromMessage(resp), nil
+}
+
+// DisableUser stops the user from signing in and revokes its tokens.
+func (c *UserGRPCClient) DisableUser(ctx context.Context, id string) (*User, error) {
+	resp, err := c.service.DisableUser(ctx, &user.DisableUserRequest{Id: id})
+	if err != nil {
+		return nil, rpcError("disable user", err)
+	}
+	return userFromMessage(resp), nil
+}
+
+func userFromMessage(m *user.User) *User {
+	createdAt, _ := time.Parse(time.RFC3339, m.GetCreatedAt())
+	updatedAt, _ := time.Parse(time.RFC3339, m.GetUpdatedAt())
+	return &User{
+		ID:        m.GetId(),
+		Email:     m.GetEmail(),
+		Role:      m.GetRole(),
+		Disabled:  m.GetDisabled(),
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}
+}
+
+func usersFromMessages(ms []*user.User) []User {
+	users := make([]User, len(ms))
+	for i, m := range ms {
+		users[i] = *userFromMessage(m)
+	}
+	return users
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mrhumster/web-server-gin/gen/go/permission"
+	"github.com/redis/go-redis/v9"
+)
+
+// watchPolicyChannel follows the policy watcher channel until ctx is done.
+// onSync runs whenever the subscription is (re)established, onChange for
+// every published change and onLost when the connection drops, since changes
+// published meanwhile are missed.
+func watchPolicyChannel(ctx context.Context, rdb redis.UniversalClient, channel string, onSync, onChange, onLost func()) {
+	sub := rdb.Subscribe(ctx, channel)
+	defer sub.Close()
+	for {
+		msg, err := sub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.Warn("Policy watcher disconnected", "channel", channel, "error", err)
+			onLost()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		switch msg.(type) {
+		case *redis.Subscription:
+			onSync()
+		case *redis.Message:
+			onChange()
+		}
+	}
+}
+
+// Policy event types streamed by WatchPolicies.
+const (
+	PolicyEventAdd    = "add"
+	PolicyEventRemove = "remove"
+	// PolicyEventReload means changes may have been missed and every policy
+	// has to be refetched.
+	PolicyEventReload = "reload"
+	// policyEventSync ends the replay of a resumed stream.
+	policyEventSync = "sync"
+)
+
+// PolicyEvent is a policy change. Each rule holds its ptype first, like the
+// rules of PolicySnapshot.
+type PolicyEvent struct {
+	Type  string
+	Rules [][]string
+}
+
+// PolicyWatcher streams policy changes from the permission service.
+type PolicyWatcher interface {
+	WatchPolicies(ctx context.Context, onSync func(), onEvent func(PolicyEvent), onLost func())
+}
+
+// WatchPolicies follows the WatchPolicies stream until ctx is done, resuming
+// after the last event seen whenever the stream breaks. onSync runs once the
+// stream has caught up, onEvent for every add, remove and reload, and onLost
+// when the stream breaks, since changes made meanwhile arrive late or, when
+// the server can't replay them, as a reload.
+func (c *PermissionGRPCClient) WatchPolicies(ctx context.Context, onSync func(), onEvent func(PolicyEvent), onLost func()) {
+	var (
+		epoch    string
+		revision uint64
+	)
+	for {
+		err := c.watchPolicies(ctx, epoch, revision, func(ev *permission.PolicyEvent) {
+			epoch, revision = ev.GetEpoch(), ev.GetRevision()
+			switch ev.GetType() {
+			case policyEventSync:
+				onSync()
+			case PolicyEventReload:
+				onEvent(PolicyEvent{Type: PolicyEventReload})
+				onSync()
+			default:
+				rules := make([][]string, len(ev.GetRules()))
+				for i, r := range ev.GetRules() {
+					rules[i] = append([]string{r.GetPtype()}, r.GetValues()...)
+				}
+				onEvent(PolicyEvent{Type: ev.GetType(), Rules: rules})
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("Policy stream disconnected", "revision", revision, "error", err)
+		onLost()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// watchPolicies runs one WatchPolicies stream until it fails.
+func (c *PermissionGRPCClient) watchPolicies(ctx context.Context, epoch string, revision uint64, handle func(*permission.PolicyEvent)) error {
+	stream, err := c.service.WatchPolicies(ctx, &permission.WatchPoliciesRequest{Epoch: epoch, Revision: revision})
+	if err != nil {
+		return rpcError("watch policies", err)
+	}
+	for {
+		ev, err := stream.Recv()
+		if err != nil {
+			return rpcError("watch policies", err)
+		}
+		handle(ev)
+	}
+}
@@ -11,11 +11,16 @@ func ErrorResponse(message string) map[string]any {
 type AccessClaims struct {
 	UserID string `json:"user_id"`
 	Role   string `json:"role"`
+	OrgID  string `json:"org_id,omitempty"`
+	// TokenVersion is the user's token version at issue; tokens issued
+	// before it changed are revoked.
+	TokenVersion string `json:"token_version,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type RefreshClaims struct {
 	UserID       string `json:"user_id"`
 	TokenVersion string `json:"token_version"`
+	OrgID        string `json:"org_id,omitempty"`
 	jwt.RegisteredClaims
 }
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -16,22 +17,131 @@ type TokenServiceIFace interface {
 	ValidateAccessToken(tokenString string) (*dto.AccessClaims, error)
 }
 
-func Authorize(client auth.PermissionClient, obj, act string) gin.HandlerFunc {
+type authorizeOptions struct {
+	subjectAttributes  func(c *gin.Context) map[string]string
+	resourceAttributes func(c *gin.Context) map[string]string
+	dryRun             bool
+	failPolicy         FailPolicy
+	resourceParam      string
+}
+
+type AuthorizeOption func(*authorizeOptions)
+
+// WithResourceAttributes lets a route describe the resource being accessed
+// (owner region, verification state, ...) for ABAC conditions.
+func WithResourceAttributes(fn func(c *gin.Context) map[string]string) AuthorizeOption {
+	return func(o *authorizeOptions) {
+		o.resourceAttributes = fn
+	}
+}
+
+// WithSubjectAttributes adds caller attributes on top of the role and
+// organization taken from the access token.
+func WithSubjectAttributes(fn func(c *gin.Context) map[string]string) AuthorizeOption {
+	return func(o *authorizeOptions) {
+		o.subjectAttributes = fn
+	}
+}
+
+// WithResourceParam names the route parameter whose value is appended to the
+// object, e.g. roles/{role}; the default is "id". An empty name checks the
+// object itself.
+func WithResourceParam(name string) AuthorizeOption {
+	return func(o *authorizeOptions) {
+		o.resourceParam = name
+	}
+}
+
+// WithDryRun puts the route in shadow mode: denials are logged together with
+// the explanation of the decision, but the request is let through. Use it to
+// roll out new policies before enforcing them.
+func WithDryRun() AuthorizeOption {
+	return func(o *authorizeOptions) {
+		o.dryRun = true
+	}
+}
+
+// FailMode decides a check the permission service could not answer.
+type FailMode string
+
+const (
+	// FailClosed denies the request with 503.
+	FailClosed FailMode = "closed"
+	// FailOpen lets the request through and logs it.
+	FailOpen FailMode = "open"
+)
+
+// FailPolicy picks the fail mode per action. Actions not listed use
+// Default; an empty Default fails closed.
+type FailPolicy struct {
+	Default FailMode
+	Actions map[string]FailMode
+}
+
+// Mode returns the fail mode of action.
+func (p FailPolicy) Mode(action string) FailMode {
+	if mode, ok := p.Actions[action]; ok {
+		return mode
+	}
+	if p.Default == FailOpen {
+		return FailOpen
+	}
+	return FailClosed
+}
+
+// WithFailPolicy decides what happens to the request when the permission
+// service is unavailable. Without it every action fails closed.
+func WithFailPolicy(p FailPolicy) AuthorizeOption {
+	return func(o *authorizeOptions) {
+		o.failPolicy = p
+	}
+}
+
+func Authorize(client auth.PermissionClient, obj, act string, opts ...AuthorizeOption) gin.HandlerFunc {
+	o := authorizeOptions{resourceParam: "id"}
+	for _, opt := range opts {
+		opt(&o)
+	}
 	return func(c *gin.Context) {
 		if c.Request.Method == http.MethodOptions {
 			c.Next()
 		}
 		userUUID := c.MustGet("user").(uuid.UUID)
-		resourceID := c.Param("id")
+		var resourceID string
+		if o.resourceParam != "" {
+			resourceID = c.Param(o.resourceParam)
+		}
 
 		fullResource := obj
 		if resourceID != "" {
 			fullResource = fmt.Sprintf("%s/%s", obj, resourceID)
 		}
 
-		ok, err := client.CheckPermission(c.Request.Context(), userUUID.String(), fullResource, act)
+		req := auth.CheckRequest{
+			UserID:     userUUID.String(),
+			Domain:     Tenant(c),
+			Resource:   fullResource,
+			Action:     act,
+			Attributes: o.attributes(c),
+		}
+		ok, err := client.Check(c.Request.Context(), req)
+		if o.dryRun && (err != nil || !ok) {
+			logShadowDenial(c, client, req, err)
+			c.Next()
+			return
+		}
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse("⚠️ Authorize middleware error"))
+			slog.Error("Authorize: permission check failed", "object", fullResource, "action", act, "error", err)
+			if auth.IsUnavailable(err) {
+				if o.failPolicy.Mode(act) == FailOpen {
+					slog.Warn("Authorize: permission service unavailable, request allowed", "object", fullResource, "action", act)
+					c.Next()
+					return
+				}
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, dto.ErrorResponse("Authorization service unavailable"))
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse("⚠️ Authorize middleware error"))
 			return
 		}
 		if !ok {
@@ -41,6 +151,64 @@ func Authorize(client auth.PermissionClient, obj, act string) gin.HandlerFunc {
 	}
 }
 
+func logShadowDenial(c *gin.Context, client auth.PermissionClient, req auth.CheckRequest, checkErr error) {
+	attrs := []any{
+		"user", req.UserID,
+		"domain", req.Domain,
+		"object", req.Resource,
+		"action", req.Action,
+		"path", c.FullPath(),
+	}
+	if checkErr != nil {
+		slog.Warn("Dry run: authorization failed, request allowed", append(attrs, "error", checkErr)...)
+		return
+	}
+	ex, err := client.ExplainPermission(c.Request.Context(), req)
+	if err != nil {
+		slog.Warn("Dry run: request would be denied", append(attrs, "explain_error", err)...)
+		return
+	}
+	slog.Warn("Dry run: request would be denied",
+		append(attrs, "reason", ex.Reason, "roles", ex.Roles)...)
+}
+
+func (o *authorizeOptions) attributes(c *gin.Context) auth.Attributes {
+	subject := map[string]string{}
+	if v, ok := c.Get("claims"); ok {
+		if claims, ok := v.(*dto.AccessClaims); ok {
+			subject["role"] = claims.Role
+			subject["org_id"] = claims.OrgID
+		}
+	}
+	if o.subjectAttributes != nil {
+		for k, v := range o.subjectAttributes(c) {
+			subject[k] = v
+		}
+	}
+	attrs := auth.Attributes{
+		Subject: subject,
+		Environment: map[string]string{
+			"ip":     c.ClientIP(),
+			"method": c.Request.Method,
+		},
+	}
+	if o.resourceAttributes != nil {
+		attrs.Resource = o.resourceAttributes(c)
+	}
+	return attrs
+}
+
+// Tenant returns the organization the request's access token is scoped to,
+// or auth.GlobalDomain for platform-scoped tokens.
+func Tenant(c *gin.Context) string {
+	if v, ok := c.Get("claims"); ok {
+		if claims, ok := v.(*dto.AccessClaims); ok && claims.OrgID != "" {
+			return claims.OrgID
+		}
+	}
+	return auth.GlobalDomain
+}
+
 func AuthMiddleware(tokenService TokenServiceIFace) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := extractToken(c.Request)
@@ -57,6 +225,7 @@ func AuthMiddleware(tokenService TokenServiceIFace) gin.HandlerFunc {
 		}
 		c.Set("user", userUUID)
 		c.Set("claims", claims)
+		c.Request = c.Request.WithContext(actorContext(c.Request.Context(), claims))
 		c.Next()
 	}
 }
@@ -85,10 +254,21 @@ func OptionalAuthMiddleware(TokenService TokenServiceIFace) gin.HandlerFunc {
 		}
 		c.Set("user", userUUID)
 		c.Set("claims", claims)
+		c.Request = c.Request.WithContext(actorContext(c.Request.Context(), claims))
 		c.Next()
 	}
 }
 
+// actorContext makes the calls made with ctx act for the token's user, in
+// the organization the token is scoped to.
+func actorContext(ctx context.Context, claims *dto.AccessClaims) context.Context {
+	ctx = auth.WithActor(ctx, claims.UserID)
+	if claims.OrgID != "" {
+		ctx = auth.WithTenant(ctx, claims.OrgID)
+	}
+	return ctx
+}
+
 func extractToken(r *http.Request) string {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mrhumster/web-server-gin/pkg/auth"
+	authmock "github.com/mrhumster/web-server-gin/pkg/auth/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+)
+
+func TestAuthorize_FailOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for name, tc := range map[string]struct {
+		allowed bool
+		code    codes.Code
+		want    int
+	}{
+		"allowed":               {allowed: true, want: http.StatusOK},
+		"denied":                {want: http.StatusForbidden},
+		"unavailable":           {code: codes.Unavailable, want: http.StatusOK},
+		"deadline exceeded":     {code: codes.DeadlineExceeded, want: http.StatusOK},
+		"permission denied":     {code: codes.PermissionDenied, want: http.StatusInternalServerError},
+		"unauthenticated":       {code: codes.Unauthenticated, want: http.StatusInternalServerError},
+		"enforcer failed":       {code: codes.Internal, want: http.StatusInternalServerError},
+		"invalid check request": {code: codes.InvalidArgument, want: http.StatusInternalServerError},
+	} {
+		t.Run(name, func(t *testing.T) {
+			client := authmock.NewMockPermissionClient(gomock.NewController(t))
+			var err error
+			if tc.code != codes.OK {
+				err = &auth.Error{Op: "CheckPermission", Code: tc.code, Message: name}
+			}
+			client.EXPECT().Check(gomock.Any(), gomock.Any()).Return(tc.allowed, err)
+
+			r := gin.New()
+			r.GET("/users/:id", func(c *gin.Context) {
+				c.Set("user", uuid.New())
+			}, Authorize(client, "users", "read", WithFailPolicy(FailPolicy{Default: FailOpen})), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+			assert.Equal(t, tc.want, w.Code)
+		})
+	}
+}
@@ -34,6 +34,10 @@ func InitTestDB() *gorm.DB {
 
 	err = TestDB.AutoMigrate(
 		&models.User{},
+		&models.Organization{},
+		&models.Membership{},
+		&models.PolicyOperation{},
+		&models.PolicyChange{},
 	)
 	if err != nil {
 		log.Fatalf("🔴 Failed apply migrations: %v", err)
@@ -57,7 +61,7 @@ func CleanTestDatabase() {
 
 	TestDB.Exec("SET session_replication_role = 'replica';")
 
-	tables := []string{"users", "casbin_rule"}
+	tables := []string{"users", "organizations", "memberships", "casbin_rule", "policy_outbox", "policy_changes"}
 	for _, table := range tables {
 		TestDB.Exec(fmt.Sprintf("TRUNCATE TABLE %s CASCADE;", table))
 	}